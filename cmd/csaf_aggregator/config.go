@@ -15,6 +15,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"time"
@@ -36,6 +37,14 @@ const (
 	defaultDomain         = "https://example.com"
 	defaultUpdateInterval = "on best effort"
 	defaultLockFile       = "/var/lock/csaf_aggregator/lock"
+	defaultRetryBackoff   = time.Second
+	defaultRetryJitter    = 0.1
+	defaultStatusFile     = "status.json"
+
+	defaultQuarantineFolder = "quarantine"
+
+	defaultFullInterval    = 24 * time.Hour
+	defaultInterimInterval = time.Hour
 )
 
 type provider struct {
@@ -65,8 +74,22 @@ type provider struct {
 
 	Range *models.TimeRange `toml:"time_range"`
 
+	// Policy overrides the global policy for advisories failing checks.
+	Policy *policy `toml:"policy"`
+	// SignatureMismatch overrides the global action for advisories
+	// with signatures not matching the keys of the provider.
+	SignatureMismatch *mismatchAction `toml:"signature_mismatch"`
+
+	// FullInterval and InterimInterval override the global
+	// intervals of the runs in scheduler mode.
+	FullInterval    *time.Duration `toml:"full_interval"`
+	InterimInterval *time.Duration `toml:"interim_interval"`
+
 	clientCerts   []tls.Certificate
 	ignorePattern filter.PatternMatcher
+
+	limiterOnce sync.Once
+	limiter     *util.HostLimiter
 }
 
 type config struct {
@@ -101,6 +124,41 @@ type config struct {
 	Interim bool `short:"i" long:"interim" description:"Perform an interim scan" toml:"interim"`
 	Version bool `long:"version" description:"Display version of the binary" toml:"-"`
 
+	// Schedule runs the aggregator as a long running scheduler
+	// performing the full and interim runs periodically.
+	Schedule bool `long:"schedule" description:"Run as scheduler performing full and interim runs periodically" toml:"schedule"`
+
+	// FullInterval is the interval of the full runs in scheduler mode.
+	FullInterval time.Duration `toml:"full_interval"`
+
+	// InterimInterval is the interval of the interim runs in scheduler mode.
+	// A negative value disables them.
+	InterimInterval time.Duration `toml:"interim_interval"`
+
+	// StatusFile is the file the status of the providers is written to.
+	StatusFile *string `toml:"status_file"`
+
+	// StatusAddress is the address the status is served at in scheduler mode.
+	StatusAddress string `toml:"status_address"`
+
+	// StaleAfter is the duration after which a provider without
+	// a successful full run is considered stale.
+	StaleAfter time.Duration `toml:"stale_after"`
+
+	// Policy decides about the advisories failing checks.
+	Policy *policy `toml:"policy"`
+
+	// QuarantineFolder is the folder to store quarantined advisories in.
+	QuarantineFolder string `toml:"quarantine_folder"`
+
+	// SignatureMismatch is the action for advisories with signatures
+	// not matching the public OpenPGP keys of their providers.
+	SignatureMismatch mismatchAction `toml:"signature_mismatch"`
+
+	// FullRebuild rebuilds the mirrors completely instead of
+	// taking the unchanged advisories from the existing ones.
+	FullRebuild bool `long:"full_rebuild" description:"Rebuild the mirrors completely" toml:"full_rebuild"`
+
 	// InterimYears is numbers numbers of years to look back
 	// for interim advisories. Less/equal zero means forever.
 	InterimYears int `toml:"interim_years"`
@@ -108,6 +166,9 @@ type config struct {
 	// RemoteValidator configures an optional remote validation.
 	RemoteValidatorOptions *csaf.RemoteValidatorOptions `toml:"remote_validator"`
 
+	// LocalValidator configures an optional validation with the built-in tests.
+	LocalValidatorOptions *csaf.LocalValidatorOptions `toml:"local_validator"`
+
 	// ServiceDocument incidates if we should create a service.json document.
 	ServiceDocument bool `toml:"create_service_document"`
 
@@ -121,6 +182,14 @@ type config struct {
 	// ExtraHeader adds extra HTTP header fields to client
 	ExtraHeader http.Header `toml:"header"`
 
+	// Retries is the number of retries of requests failing transiently.
+	Retries int `toml:"retries"`
+	// RetryBackoff is the interval before the first retry.
+	// It is doubled with each further retry.
+	RetryBackoff time.Duration `toml:"retry_backoff"`
+	// RetryJitter is the fraction by which the retry intervals are varied randomly.
+	RetryJitter *float64 `toml:"retry_jitter"`
+
 	Config string `short:"c" long:"config" description:"Path to config TOML file" value-name:"TOML-FILE" toml:"-"`
 
 	keyMu  sync.Mutex
@@ -218,6 +287,45 @@ func (p *provider) writeIndices(c *config) bool {
 	return c.WriteIndices
 }
 
+// fullInterval returns the interval of the full runs of a provider
+// in scheduler mode. The update interval is used if it can
+// be interpreted as a duration.
+func (p *provider) fullInterval(c *config) time.Duration {
+	if p.FullInterval != nil {
+		return *p.FullInterval
+	}
+	if p.UpdateInterval != nil {
+		if d, ok := util.ParseUpdateInterval(*p.UpdateInterval); ok {
+			return d
+		}
+	}
+	if c.FullInterval > 0 {
+		return c.FullInterval
+	}
+	if c.UpdateInterval != nil {
+		if d, ok := util.ParseUpdateInterval(*c.UpdateInterval); ok {
+			return d
+		}
+	}
+	return defaultFullInterval
+}
+
+// interimInterval returns the interval of the interim runs of
+// a provider in scheduler mode. Less/equal zero means none.
+func (p *provider) interimInterval(c *config) time.Duration {
+	// Only mirrors have interims.
+	if !p.runAsMirror(c) {
+		return 0
+	}
+	if p.InterimInterval != nil {
+		return *p.InterimInterval
+	}
+	if c.InterimInterval != 0 {
+		return c.InterimInterval
+	}
+	return defaultInterimInterval
+}
+
 func (p *provider) runAsMirror(c *config) bool {
 	if p.AggregatoryCategory != nil {
 		return *p.AggregatoryCategory == csaf.AggregatorAggregator
@@ -262,6 +370,23 @@ func (c *config) privateOpenPGPKey() (*crypto.Key, error) {
 	return c.key, c.keyErr
 }
 
+// hostLimiter returns the adaptive rate limiter per host shared
+// by all clients of the provider. The configured rate is the
+// ceiling for each host.
+func (p *provider) hostLimiter(c *config) *util.HostLimiter {
+	p.limiterOnce.Do(func() {
+		var r float64
+		if c.Rate != nil {
+			r = *c.Rate
+		}
+		if p.Rate != nil {
+			r = *p.Rate
+		}
+		p.limiter = util.NewHostLimiter(rate.Limit(r))
+	})
+	return p.limiter
+}
+
 func (c *config) httpClient(p *provider) util.Client {
 
 	hClient := http.Client{}
@@ -305,21 +430,21 @@ func (c *config) httpClient(p *provider) util.Client {
 		client = &util.LoggingClient{Client: client}
 	}
 
-	if p.Rate == nil && c.Rate == nil {
-		return client
+	client = &util.HostLimitingClient{
+		Client:  client,
+		Limiter: p.hostLimiter(c),
 	}
 
-	var r float64
-	if c.Rate != nil {
-		r = *c.Rate
-	}
-	if p.Rate != nil {
-		r = *p.Rate
-	}
-	return &util.LimitingClient{
-		Client:  client,
-		Limiter: rate.NewLimiter(rate.Limit(r), 1),
+	// Add optional retrying of transient failures.
+	if c.Retries > 0 {
+		client = &util.RetryingClient{
+			Client:   client,
+			Attempts: c.Retries + 1,
+			Backoff:  c.RetryBackoff,
+			Jitter:   *c.RetryJitter,
+		}
 	}
+	return client
 }
 
 func (c *config) checkProviders() error {
@@ -359,6 +484,31 @@ func (c *config) checkMirror() error {
 	return nil
 }
 
+// checkSchedule checks that the scheduler mode is not combined
+// with an interim run and that the intervals are positive.
+func (c *config) checkSchedule() error {
+	if !c.Schedule {
+		return nil
+	}
+	if c.Interim {
+		return errors.New("interim and schedule cannot be used at the same time")
+	}
+	for _, p := range c.Providers {
+		if p.fullInterval(c) <= 0 {
+			return fmt.Errorf("full interval of '%s' has to be positive", p.Name)
+		}
+	}
+	return nil
+}
+
+// checkValidators checks that not both a remote and a local validator are configured.
+func (c *config) checkValidators() error {
+	if c.RemoteValidatorOptions != nil && c.LocalValidatorOptions != nil {
+		return errors.New("remote and local validator cannot be used at the same time")
+	}
+	return nil
+}
+
 func (c *config) setDefaults() {
 	if c.Folder == "" {
 		c.Folder = defaultFolder
@@ -368,10 +518,23 @@ func (c *config) setDefaults() {
 		c.Web = defaultWeb
 	}
 
+	if c.QuarantineFolder == "" {
+		c.QuarantineFolder = filepath.Join(c.Folder, defaultQuarantineFolder)
+	}
+
 	if c.Domain == "" {
 		c.Domain = defaultDomain
 	}
 
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = defaultRetryBackoff
+	}
+
+	if c.RetryJitter == nil {
+		jitter := defaultRetryJitter
+		c.RetryJitter = &jitter
+	}
+
 	switch {
 	case c.LockFile == nil:
 		lockFile := defaultLockFile
@@ -380,6 +543,14 @@ func (c *config) setDefaults() {
 		c.LockFile = nil
 	}
 
+	switch {
+	case c.StatusFile == nil:
+		statusFile := filepath.Join(c.Web, defaultStatusFile)
+		c.StatusFile = &statusFile
+	case *c.StatusFile == "":
+		c.StatusFile = nil
+	}
+
 	if c.Workers <= 0 {
 		if n := runtime.NumCPU(); n > defaultWorkers {
 			c.Workers = defaultWorkers
@@ -463,6 +634,8 @@ func (c *config) prepare() error {
 		c.Aggregator.Validate,
 		c.checkProviders,
 		c.checkMirror,
+		c.checkValidators,
+		c.checkSchedule,
 	} {
 		if err := prepare(); err != nil {
 			return err
@@ -36,6 +36,8 @@ func (w *worker) setupProviderFull(provider *provider) error {
 
 	w.dir = ""
 	w.provider = provider
+	w.stats = nil
+	w.providerKeys, w.keysLoaded = nil, false
 
 	// Each job needs a separate client.
 	w.client = w.processor.cfg.httpClient(provider)
@@ -67,37 +69,69 @@ func (w *worker) fullWork(wg *sync.WaitGroup, jobs <-chan *fullJob) {
 	defer wg.Done()
 
 	for j := range jobs {
-		if err := w.setupProviderFull(j.provider); err != nil {
-			j.err = err
-			continue
+		run := &runStatus{kind: fullRun, started: time.Now()}
+		if j.err = w.setupProviderFull(j.provider); j.err == nil {
+			j.aggregatorProvider, j.err = j.work(w)
 		}
-		j.aggregatorProvider, j.err = j.work(w)
+		run.finished, run.err = time.Now(), j.err
+		w.processor.status.record(j.provider, run, w)
 	}
 }
 
-// full performs the complete lister/download
-func (p *processor) full() error {
+// openValidator sets up the remote or local validator if
+// one is configured and the aggregator runs in mirror mode.
+// The returned function closes the validator.
+func (p *processor) openValidator() (func(), error) {
 
-	if p.cfg.runAsMirror() {
-		log.Println("Running in aggregator mode")
+	if !p.cfg.runAsMirror() {
+		log.Println("Running in lister mode")
+		return func() {}, nil
+	}
 
-		// check if we need to setup a remote validator
-		if p.cfg.RemoteValidatorOptions != nil {
-			validator, err := p.cfg.RemoteValidatorOptions.Open()
-			if err != nil {
-				return err
-			}
+	log.Println("Running in aggregator mode")
 
-			// Not sure if we really need it to be serialized.
-			p.remoteValidator = csaf.SynchronizedRemoteValidator(validator)
-			defer func() {
-				p.remoteValidator.Close()
-				p.remoteValidator = nil
-			}()
-		}
-	} else {
-		log.Println("Running in lister mode")
+	// check if we need to setup a remote or local validator
+	var opener interface {
+		Open() (csaf.RemoteValidator, error)
+	}
+	switch {
+	case p.cfg.RemoteValidatorOptions != nil:
+		opener = p.cfg.RemoteValidatorOptions
+	case p.cfg.LocalValidatorOptions != nil:
+		opener = p.cfg.LocalValidatorOptions
+	}
+	if opener == nil {
+		return func() {}, nil
+	}
+	validator, err := opener.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	// Not sure if we really need it to be serialized.
+	p.remoteValidator = csaf.SynchronizedRemoteValidator(validator)
+	return func() {
+		p.remoteValidator.Close()
+		p.remoteValidator = nil
+	}, nil
+}
+
+// fullWorkFor returns the work to be done for a provider in a full run.
+func (p *processor) fullWorkFor(provider *provider) fullWorkFunc {
+	if provider.runAsMirror(p.cfg) {
+		return (*worker).mirror
 	}
+	return (*worker).lister
+}
+
+// full performs the complete lister/download
+func (p *processor) full() error {
+
+	closeValidator, err := p.openValidator()
+	if err != nil {
+		return err
+	}
+	defer closeValidator()
 
 	queue := make(chan *fullJob)
 	var wg sync.WaitGroup
@@ -112,15 +146,9 @@ func (p *processor) full() error {
 	jobs := make([]fullJob, len(p.cfg.Providers))
 
 	for i, provider := range p.cfg.Providers {
-		var work fullWorkFunc
-		if provider.runAsMirror(p.cfg) {
-			work = (*worker).mirror
-		} else {
-			work = (*worker).lister
-		}
 		jobs[i] = fullJob{
 			provider: provider,
-			work:     work,
+			work:     p.fullWorkFor(provider),
 		}
 		queue <- &jobs[i]
 	}
@@ -128,6 +156,15 @@ func (p *processor) full() error {
 
 	wg.Wait()
 
+	p.status.writeLogged()
+
+	return p.writeAggregator(jobs)
+}
+
+// writeAggregator writes the aggregator.json assembled
+// from the results of the given jobs.
+func (p *processor) writeAggregator(jobs []fullJob) error {
+
 	// Assemble aggregator data structure.
 	var providers []*csaf.AggregatorCSAFProvider
 	var publishers []*csaf.AggregatorCSAFPublisher
@@ -0,0 +1,198 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// previousMirror gives access to the advisories of
+// the mirror of a provider built by an earlier run.
+type previousMirror struct {
+	// files maps the labels and the filenames
+	// to the paths of the advisories.
+	files map[string]map[string]string
+}
+
+// webTarget returns the path under which the mirror
+// of the current provider is published.
+func (w *worker) webTarget() string {
+	return filepath.Join(
+		w.processor.cfg.Web, ".well-known", "csaf-aggregator", w.provider.Name)
+}
+
+// loadPreviousMirror scans the currently published mirror
+// of the provider. Returns nil if there is none or if
+// a full rebuild is configured.
+func (w *worker) loadPreviousMirror() (*previousMirror, error) {
+	if w.processor.cfg.FullRebuild {
+		return nil, nil
+	}
+	dir, err := filepath.EvalSymlinks(w.webTarget())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	labels, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pm := &previousMirror{files: map[string]map[string]string{}}
+	for _, label := range labels {
+		if !label.IsDir() {
+			continue
+		}
+		labelDir := filepath.Join(dir, label.Name())
+		years, err := os.ReadDir(labelDir)
+		if err != nil {
+			return nil, err
+		}
+		files := map[string]string{}
+		for _, year := range years {
+			if !year.IsDir() {
+				continue
+			}
+			if _, err := strconv.Atoi(year.Name()); err != nil {
+				continue
+			}
+			yearDir := filepath.Join(labelDir, year.Name())
+			entries, err := os.ReadDir(yearDir)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range entries {
+				if entry.Type().IsRegular() && util.ConformingFileName(entry.Name()) {
+					files[entry.Name()] = filepath.Join(yearDir, entry.Name())
+				}
+			}
+		}
+		if len(files) > 0 {
+			pm.files[label.Name()] = files
+		}
+	}
+	return pm, nil
+}
+
+// lookup returns the path of an advisory in the previous mirror.
+// Returns an empty string if it is not found.
+func (pm *previousMirror) lookup(label, filename string) string {
+	if pm == nil {
+		return ""
+	}
+	return pm.files[label][filename]
+}
+
+// fetchHash fetches a remote hash file and returns the hash sum.
+func (w *worker) fetchHash(url string) ([]byte, error) {
+	res, err := w.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errNotFound
+	}
+	hash, err := util.HashFromReader(io.LimitReader(res.Body, 1<<16))
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) == 0 {
+		return nil, errNotFound
+	}
+	return hash, nil
+}
+
+// unchanged checks if the advisory listed by the provider is the same
+// as the one at path in the previous mirror. The time of the last
+// update given by the listing is compared with the current_release_date
+// and the remote hashes are compared with the local ones.
+// If neither of them is available the advisory is considered changed.
+func (w *worker) unchanged(
+	file csaf.AdvisoryFile,
+	path string,
+	sum *csaf.AdvisorySummary,
+) bool {
+	var checked bool
+	if updated := csaf.FileUpdated(file); !updated.IsZero() {
+		if !updated.Equal(sum.CurrentReleaseDate) {
+			return false
+		}
+		checked = true
+	}
+	for _, h := range []struct {
+		url string
+		ext string
+	}{
+		{file.SHA256URL(), ".sha256"},
+		{file.SHA512URL(), ".sha512"},
+	} {
+		remote, err := w.fetchHash(h.url)
+		if err != nil {
+			continue
+		}
+		local, err := util.HashFromFile(path + h.ext)
+		return err == nil && bytes.Equal(remote, local)
+	}
+	return checked
+}
+
+// loadUnchanged loads the advisory at path from the previous mirror
+// if it is unchanged compared to the one listed by the provider.
+func (w *worker) loadUnchanged(
+	file csaf.AdvisoryFile,
+	path string,
+) (any, *csaf.AdvisorySummary, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("error: %v\n", err)
+		return nil, nil, false
+	}
+	var advisory any
+	if err := json.Unmarshal(data, &advisory); err != nil {
+		log.Printf("error: %s: %v\n", path, err)
+		return nil, nil, false
+	}
+	sum, err := csaf.NewAdvisorySummary(w.expr, advisory)
+	if err != nil {
+		log.Printf("error: %s: %v\n", path, err)
+		return nil, nil, false
+	}
+	if !w.unchanged(file, path, sum) {
+		return nil, nil, false
+	}
+	return advisory, sum, true
+}
+
+// linkAdvisory hard links an advisory of the previous mirror
+// along with its hashes and signature into the new mirror.
+// Advisories without a signature stay without one as they
+// may have been mirrored despite failed checks.
+func linkAdvisory(src, dst string) error {
+	for _, ext := range []string{"", ".sha256", ".sha512", ".asc"} {
+		if err := os.Link(src+ext, dst+ext); err != nil {
+			if ext == ".asc" && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+	}
+	return nil
+}
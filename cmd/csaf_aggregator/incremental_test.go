@@ -0,0 +1,251 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+const (
+	testSHA256 = "0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a"
+	testSHA512 = "0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b" +
+		"0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b"
+	otherSHA256 = "0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c0c"
+)
+
+// newTestWorker creates a worker for the provider "example".
+// Its client fetches the given files from a test server whose
+// URL is returned.
+func newTestWorker(t *testing.T, cfg *config, files map[string]string) (*worker, string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(rw, r)
+			return
+		}
+		rw.Write([]byte(content))
+	}))
+	t.Cleanup(srv.Close)
+	w := newWorker(1, &processor{cfg: cfg})
+	w.client = srv.Client()
+	w.provider = &provider{Name: "example"}
+	return w, srv.URL
+}
+
+func TestUnchanged(t *testing.T) {
+	released := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	// The previous mirror only has a SHA256 hash.
+	dir := t.TempDir()
+	path := filepath.Join(dir, "example-2023-0001.json")
+	if err := os.WriteFile(path+".sha256",
+		[]byte(testSHA256+"  example-2023-0001.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		updated time.Time
+		remote  map[string]string
+		want    bool
+	}{
+		{
+			name: "no time, no hashes",
+			want: false,
+		},
+		{
+			name:    "same time, no hashes",
+			updated: released,
+			want:    true,
+		},
+		{
+			name:    "same time in other zone",
+			updated: released.In(time.FixedZone("CEST", 2*60*60)),
+			want:    true,
+		},
+		{
+			name:    "newer time",
+			updated: released.Add(time.Hour),
+			remote:  map[string]string{"/a.json.sha256": testSHA256},
+			want:    false,
+		},
+		{
+			name:   "no time, same hash",
+			remote: map[string]string{"/a.json.sha256": testSHA256},
+			want:   true,
+		},
+		{
+			name:   "no time, other hash",
+			remote: map[string]string{"/a.json.sha256": otherSHA256},
+			want:   false,
+		},
+		{
+			name:    "same time, other hash",
+			updated: released,
+			remote:  map[string]string{"/a.json.sha256": otherSHA256},
+			want:    false,
+		},
+		{
+			name:   "no local hash",
+			remote: map[string]string{"/a.json.sha512": testSHA512},
+			want:   false,
+		},
+		{
+			name:    "same time, no local hash",
+			updated: released,
+			remote:  map[string]string{"/a.json.sha512": testSHA512},
+			want:    false,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, url := newTestWorker(t, &config{}, tc.remote)
+
+			var file csaf.AdvisoryFile = csaf.PlainAdvisoryFile(url + "/a.json")
+			if !tc.updated.IsZero() {
+				file = csaf.UpdatedAdvisoryFile{AdvisoryFile: file, Updated: tc.updated}
+			}
+			sum := &csaf.AdvisorySummary{CurrentReleaseDate: released}
+			if got := w.unchanged(file, path, sum); got != tc.want {
+				t.Errorf("got %t expected %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLoadPreviousMirror(t *testing.T) {
+	web := t.TempDir()
+	w, _ := newTestWorker(t, &config{Web: web}, nil)
+
+	// No mirror yet.
+	if pm, err := w.loadPreviousMirror(); err != nil || pm != nil {
+		t.Fatalf("got %v, %v expected no previous mirror", pm, err)
+	}
+
+	// The published mirror is a symbolic link to the real folder.
+	folder := filepath.Join(web, "example-2023")
+	for _, fname := range []string{
+		"white/2023/example-2023-0001.json",
+		"white/2023/example-2023-0001.json.sha256",
+		"white/2022/example-2022-0001.json",
+		"white/index.txt",
+		"white/misc/example-2023-0002.json",
+		"green/2023/example-2023-0003.json",
+		"amber/2023/not-conforming.txt",
+	} {
+		path := filepath.Join(folder, filepath.FromSlash(fname))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	target := w.webTarget()
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(folder, target); err != nil {
+		t.Fatal(err)
+	}
+
+	pm, err := w.loadPreviousMirror()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]string{
+		"white": {
+			"example-2023-0001.json": filepath.Join(folder, "white", "2023", "example-2023-0001.json"),
+			"example-2022-0001.json": filepath.Join(folder, "white", "2022", "example-2022-0001.json"),
+		},
+		"green": {
+			"example-2023-0003.json": filepath.Join(folder, "green", "2023", "example-2023-0003.json"),
+		},
+	}
+	if !reflect.DeepEqual(pm.files, want) {
+		t.Errorf("got %v expected %v", pm.files, want)
+	}
+	if got := pm.lookup("white", "example-2022-0001.json"); got != want["white"]["example-2022-0001.json"] {
+		t.Errorf("lookup returned %q", got)
+	}
+	if got := pm.lookup("amber", "example-2022-0001.json"); got != "" {
+		t.Errorf("lookup of unknown label returned %q", got)
+	}
+	if got := (*previousMirror)(nil).lookup("white", "example-2022-0001.json"); got != "" {
+		t.Errorf("lookup without previous mirror returned %q", got)
+	}
+
+	// A full rebuild ignores the previous mirror.
+	w.processor.cfg.FullRebuild = true
+	if pm, err := w.loadPreviousMirror(); err != nil || pm != nil {
+		t.Errorf("got %v, %v expected no previous mirror", pm, err)
+	}
+}
+
+func TestLinkAdvisory(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		files   []string
+		linked  []string
+		wantErr bool
+	}{
+		{
+			name:   "complete",
+			files:  []string{"", ".sha256", ".sha512", ".asc"},
+			linked: []string{"", ".sha256", ".sha512", ".asc"},
+		},
+		{
+			name:   "without signature",
+			files:  []string{"", ".sha256", ".sha512"},
+			linked: []string{"", ".sha256", ".sha512"},
+		},
+		{
+			name:    "without hash",
+			files:   []string{"", ".sha256", ".asc"},
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src.json")
+			dst := filepath.Join(dir, "dst.json")
+			for _, ext := range tc.files {
+				if err := os.WriteFile(src+ext, []byte(ext), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			err := linkAdvisory(src, dst)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, ext := range []string{"", ".sha256", ".sha512", ".asc"} {
+				_, err := os.Stat(dst + ext)
+				if linked := slices.Contains(tc.linked, ext); linked != (err == nil) {
+					t.Errorf("%q: linked %t, stat error %v", ext, linked, err)
+				}
+			}
+		})
+	}
+}
@@ -229,11 +229,6 @@ func (w *worker) writeROLIE(label string, summaries []summary) error {
 
 	entries := make([]*csaf.Entry, len(summaries))
 
-	format := csaf.Format{
-		Schema:  "https://docs.oasis-open.org/csaf/csaf/v2.0/csaf_json_schema.json",
-		Version: "2.0",
-	}
-
 	for i := range summaries {
 		s := &summaries[i]
 
@@ -253,7 +248,7 @@ func (w *worker) writeROLIE(label string, summaries []summary) error {
 				{Rel: "hash", HRef: csafURL + ".sha512"},
 				{Rel: "signature", HRef: csafURL + ".asc"},
 			},
-			Format: format,
+			Format: csaf.CSAFFormat(s.summary.CSAFVersion),
 			Content: csaf.Content{
 				Type: "application/json",
 				Src:  csafURL,
@@ -75,8 +75,9 @@ func (w *worker) checkInterims(
 		}
 
 		s256 := sha256.New()
+		s512 := sha512.New()
 		data.Reset()
-		hasher := io.MultiWriter(s256, &data)
+		hasher := io.MultiWriter(s256, s512, &data)
 
 		var doc any
 		if err := func() error {
@@ -95,14 +96,19 @@ func (w *worker) checkInterims(
 			continue
 		}
 
-		errors, err := csaf.ValidateCSAF(doc)
+		bytes := data.Bytes()
+
+		// Check the changed advisory and its signature.
+		mirror, sig, err := w.vet(
+			csaf.PlainAdvisoryFile(url), label, filepath.Base(local),
+			doc, bytes, remoteHash, s512.Sum(nil))
 		if err != nil {
-			return nil, fmt.Errorf("failed to validate %s: %v", url, err)
+			return nil, err
 		}
-
-		// XXX: Should we return an error here?
-		for _, e := range errors {
-			log.Printf("validation error: %s: %v\n", url, e)
+		// Keep the old version and check again next time.
+		if !mirror {
+			notFinalized = append(notFinalized, interim)
+			continue
 		}
 
 		// We need to write the changed content.
@@ -116,16 +122,14 @@ func (w *worker) checkInterims(
 		// Overwrite in the cloned folder.
 		nlocal := filepath.Join(dst, label, interim.path())
 
-		bytes := data.Bytes()
-
 		if err := os.WriteFile(nlocal, bytes, 0644); err != nil {
 			return nil, err
 		}
 
 		name := filepath.Base(nlocal)
 
-		if err := util.WriteHashToFile(
-			nlocal+".sha512", name, sha512.New(), bytes,
+		if err := util.WriteHashSumToFile(
+			nlocal+".sha512", name, s512.Sum(nil),
 		); err != nil {
 			return nil, err
 		}
@@ -135,12 +139,13 @@ func (w *worker) checkInterims(
 			return nil, err
 		}
 
-		// Download the signature
-		sigURL := url + ".asc"
+		// Replace the signature of the old version.
 		ascFile := nlocal + ".asc"
-
-		// Download the signature or sign it our self.
-		if err := w.downloadSignatureOrSign(sigURL, ascFile, bytes); err != nil {
+		if sig != "" {
+			if err := os.WriteFile(ascFile, []byte(sig), 0644); err != nil {
+				return nil, err
+			}
+		} else if err := os.Remove(ascFile); err != nil && !os.IsNotExist(err) {
 			return nil, err
 		}
 
@@ -164,6 +169,9 @@ func (w *worker) setupProviderInterim(provider *provider) {
 
 	w.dir = ""
 	w.provider = provider
+	w.stats = &mirrorStats{}
+	w.metadataProvider = nil
+	w.providerKeys, w.keysLoaded = nil, false
 
 	// Each job needs a separate client.
 	w.client = w.processor.cfg.httpClient(provider)
@@ -171,69 +179,75 @@ func (w *worker) setupProviderInterim(provider *provider) {
 
 func (w *worker) interimWork(wg *sync.WaitGroup, jobs <-chan *interimJob) {
 	defer wg.Done()
+	for j := range jobs {
+		run := &runStatus{kind: interimRun, started: time.Now()}
+		j.err = w.interimProvider(j.provider)
+		run.finished, run.err = time.Now(), j.err
+		w.processor.status.record(j.provider, run, w)
+	}
+}
+
+// interimProvider checks the interim advisories of a provider
+// and updates its mirror if they have changed.
+func (w *worker) interimProvider(provider *provider) error {
+	w.setupProviderInterim(provider)
+
 	path := filepath.Join(w.processor.cfg.Web, ".well-known", "csaf-aggregator")
+	providerPath := filepath.Join(path, provider.Name)
 
 	tooOld := w.processor.cfg.tooOldForInterims()
 
-	for j := range jobs {
-		w.setupProviderInterim(j.provider)
-
-		providerPath := filepath.Join(path, j.provider.Name)
-
-		j.err = func() error {
-			tx := newLazyTransaction(providerPath, w.processor.cfg.Folder)
-			defer tx.rollback()
-
-			// Try all the labels
-			for _, label := range []string{
-				csaf.TLPLabelUnlabeled,
-				csaf.TLPLabelWhite,
-				csaf.TLPLabelGreen,
-				csaf.TLPLabelAmber,
-				csaf.TLPLabelRed,
-			} {
-				label = strings.ToLower(label)
-				labelPath := filepath.Join(providerPath, label)
-
-				interCSV := filepath.Join(labelPath, interimsCSV)
-				interims, olds, err := readInterims(interCSV, tooOld)
-				if err != nil {
-					return err
-				}
-
-				// no interims found -> next label.
-				if len(interims) == 0 {
-					continue
-				}
-
-				// Compare locals against remotes.
-				notFinalized, err := w.checkInterims(tx, label, interims)
-				if err != nil {
-					return err
-				}
-
-				// Nothing has changed.
-				if len(notFinalized) == len(interims) {
-					continue
-				}
-
-				// Simply append the olds. Maybe we got re-configured with
-				// a greater interims interval later.
-				notFinalized = append(notFinalized, olds...)
-
-				// We want to write in the transaction folder.
-				dst, err := tx.Dst()
-				if err != nil {
-					return err
-				}
-				ninterCSV := filepath.Join(dst, label, interimsCSV)
-				if err := writeInterims(ninterCSV, notFinalized); err != nil {
-					return err
-				}
-			}
-			return tx.commit()
-		}()
+	tx := newLazyTransaction(providerPath, w.processor.cfg.Folder)
+	defer tx.rollback()
+
+	// Try all the labels
+	for _, label := range []string{
+		csaf.TLPLabelUnlabeled,
+		csaf.TLPLabelWhite,
+		csaf.TLPLabelGreen,
+		csaf.TLPLabelAmber,
+		csaf.TLPLabelRed,
+	} {
+		label = strings.ToLower(label)
+		labelPath := filepath.Join(providerPath, label)
+
+		interCSV := filepath.Join(labelPath, interimsCSV)
+		interims, olds, err := readInterims(interCSV, tooOld)
+		if err != nil {
+			return err
+		}
+
+		// no interims found -> next label.
+		if len(interims) == 0 {
+			continue
+		}
+
+		// Compare locals against remotes.
+		notFinalized, err := w.checkInterims(tx, label, interims)
+		if err != nil {
+			return err
+		}
+
+		// Nothing has changed.
+		if len(notFinalized) == len(interims) {
+			continue
+		}
+
+		// Simply append the olds. Maybe we got re-configured with
+		// a greater interims interval later.
+		notFinalized = append(notFinalized, olds...)
+
+		// We want to write in the transaction folder.
+		dst, err := tx.Dst()
+		if err != nil {
+			return err
+		}
+		ninterCSV := filepath.Join(dst, label, interimsCSV)
+		if err := writeInterims(ninterCSV, notFinalized); err != nil {
+			return err
+		}
 	}
+	return tx.commit()
 }
 
 // joinErrors creates an aggregated error of the messages
@@ -279,6 +293,8 @@ func (p *processor) interim() error {
 
 	wg.Wait()
 
+	p.status.writeLogged()
+
 	var errs []error
 
 	for i := range jobs {
@@ -67,11 +67,19 @@ func (w *worker) mirrorInternal() (*csaf.AggregatorCSAFProvider, error) {
 	// Collecting the categories per label.
 	w.categories = map[string]util.Set[string]{}
 
+	// Counting the advisories.
+	w.stats = &mirrorStats{}
+
 	base, err := url.Parse(w.loc)
 	if err != nil {
 		return nil, err
 	}
 
+	// Unchanged advisories are taken from the previous mirror.
+	if w.previous, err = w.loadPreviousMirror(); err != nil {
+		return nil, err
+	}
+
 	afp := csaf.NewAdvisoryFileProcessor(
 		w.client,
 		w.expr,
@@ -324,8 +332,7 @@ func (w *worker) createAggregatorProvider() (*csaf.AggregatorCSAFProvider, error
 // doMirrorTransaction performs an atomic directory swap.
 func (w *worker) doMirrorTransaction() error {
 
-	webTarget := filepath.Join(
-		w.processor.cfg.Web, ".well-known", "csaf-aggregator", w.provider.Name)
+	webTarget := w.webTarget()
 
 	var oldWeb string
 
@@ -495,6 +502,21 @@ func (w *worker) mirrorFiles(tlpLabel csaf.TLPLabel, files []csaf.AdvisoryFile)
 
 	yearDirs := make(map[int]string)
 
+	yearDirFor := func(year int) (string, error) {
+		yearDir := yearDirs[year]
+		if yearDir == "" {
+			yearDir = filepath.Join(dir, label, strconv.Itoa(year))
+			if err := os.MkdirAll(yearDir, 0755); err != nil {
+				return "", err
+			}
+			//log.Printf("created %s\n", yearDir)
+			yearDirs[year] = yearDir
+		}
+		return yearDir, nil
+	}
+
+	var reused, fetched int
+
 	for _, file := range files {
 
 		u, err := url.Parse(file.URL())
@@ -518,6 +540,31 @@ func (w *worker) mirrorFiles(tlpLabel csaf.TLPLabel, files []csaf.AdvisoryFile)
 			continue
 		}
 
+		// Take unchanged advisories from the previous mirror.
+		if prev := w.previous.lookup(label, filename); prev != "" {
+			if advisory, sum, ok := w.loadUnchanged(file, prev); ok {
+				if err := w.extractCategories(label, advisory); err != nil {
+					log.Printf("error: %s: %v\n", file, err)
+					continue
+				}
+				yearDir, err := yearDirFor(sum.InitialReleaseDate.Year())
+				if err != nil {
+					return err
+				}
+				fname := filepath.Join(yearDir, filename)
+				if err := linkAdvisory(prev, fname); err != nil {
+					return err
+				}
+				summaries = append(summaries, summary{
+					filename: filename,
+					summary:  sum,
+					url:      file.URL(),
+				})
+				reused++
+				continue
+			}
+		}
+
 		var advisory any
 
 		s256 := sha256.New()
@@ -532,38 +579,27 @@ func (w *worker) mirrorFiles(tlpLabel csaf.TLPLabel, files []csaf.AdvisoryFile)
 
 		if err := downloadJSON(w.client, file.URL(), download); err != nil {
 			log.Printf("error: %v\n", err)
+			w.stats.DownloadFailed++
 			continue
 		}
 
-		// Check against CSAF schema.
-		errors, err := csaf.ValidateCSAF(advisory)
+		data := content.Bytes()
+		hash256, hash512 := s256.Sum(nil), s512.Sum(nil)
+
+		// Check the advisory and its signature.
+		mirror, sig, err := w.vet(
+			file, label, filename, advisory, data, hash256, hash512)
 		if err != nil {
-			log.Printf("error: %s: %v", file, err)
-			continue
+			return err
 		}
-		if len(errors) > 0 {
-			log.Printf("CSAF file %s has %d validation errors.\n",
-				file, len(errors))
+		if !mirror {
 			continue
 		}
 
-		// Check against remote validator.
-		if rmv := w.processor.remoteValidator; rmv != nil {
-			rvr, err := rmv.Validate(advisory)
-			if err != nil {
-				log.Printf("Calling remote validator failed: %s\n", err)
-				continue
-			}
-			if !rvr.Valid {
-				log.Printf(
-					"CSAF file %s does not validate remotely.\n", file)
-				continue
-			}
-		}
-
 		sum, err := csaf.NewAdvisorySummary(w.expr, advisory)
 		if err != nil {
 			log.Printf("error: %s: %v\n", file, err)
+			w.stats.Invalid++
 			continue
 		}
 
@@ -583,57 +619,35 @@ func (w *worker) mirrorFiles(tlpLabel csaf.TLPLabel, files []csaf.AdvisoryFile)
 			url:      file.URL(),
 		})
 
-		year := sum.InitialReleaseDate.Year()
-
-		yearDir := yearDirs[year]
-		if yearDir == "" {
-			yearDir = filepath.Join(dir, label, strconv.Itoa(year))
-			if err := os.MkdirAll(yearDir, 0755); err != nil {
-				return err
-			}
-			//log.Printf("created %s\n", yearDir)
-			yearDirs[year] = yearDir
+		yearDir, err := yearDirFor(sum.InitialReleaseDate.Year())
+		if err != nil {
+			return err
 		}
 
 		fname := filepath.Join(yearDir, filename)
 		//log.Printf("write: %s\n", fname)
-		data := content.Bytes()
 		if err := writeFileHashes(
 			fname, filename,
-			data, s256.Sum(nil), s512.Sum(nil),
+			data, hash256, hash512,
 		); err != nil {
 			return err
 		}
 
-		// Try to fetch signature file.
-		sigURL := file.SignURL()
-		ascFile := fname + ".asc"
-		if err := w.downloadSignatureOrSign(sigURL, ascFile, data); err != nil {
-			return err
+		if sig != "" {
+			if err := os.WriteFile(fname+".asc", []byte(sig), 0644); err != nil {
+				return err
+			}
 		}
+		fetched++
 	}
 	w.summaries[label] = summaries
 
-	return nil
-}
-
-// downloadSignatureOrSign first tries to download a signature.
-// If this fails it creates a signature itself with the configured key.
-func (w *worker) downloadSignatureOrSign(url, fname string, data []byte) error {
-	sig, err := w.downloadSignature(url)
+	log.Printf("%s (%s): %d advisories unchanged, %d fetched\n",
+		w.provider.Name, label, reused, fetched)
 
-	if err != nil {
-		if err != errNotFound {
-			log.Printf("error: %s: %v\n", url, err)
-		}
-		// Sign it our self.
-		if sig, err = w.sign(data); err != nil {
-			return err
-		}
-	}
+	w.stats.Unchanged += reused
+	w.stats.Fetched += fetched
+	w.stats.Mirrored += reused + fetched
 
-	if sig != "" {
-		err = os.WriteFile(fname, []byte(sig), 0644)
-	}
-	return err
+	return nil
 }
@@ -0,0 +1,314 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// policyAction is what happens with an advisory failing a check.
+type policyAction string
+
+const (
+	// policyReject does not mirror the advisory.
+	policyReject policyAction = "reject"
+	// policyQuarantine does not mirror the advisory but
+	// stores it in the quarantine folder.
+	policyQuarantine policyAction = "quarantine"
+	// policyWarn mirrors the advisory with a warning.
+	policyWarn policyAction = "warn"
+)
+
+// policyCheck is a check an advisory has to pass.
+type policyCheck string
+
+const (
+	schemaCheck           policyCheck = "schema"
+	remoteValidationCheck policyCheck = "remote_validation"
+	hashCheck             policyCheck = "hash"
+	signatureCheck        policyCheck = "signature"
+)
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (pa *policyAction) UnmarshalText(text []byte) error {
+	switch a := policyAction(text); a {
+	case policyReject, policyQuarantine, policyWarn:
+		*pa = a
+		return nil
+	}
+	return fmt.Errorf("invalid policy action %q", text)
+}
+
+// policy configures the actions for the advisories failing the checks.
+type policy struct {
+	Schema           policyAction `toml:"schema"`
+	RemoteValidation policyAction `toml:"remote_validation"`
+	Hash             policyAction `toml:"hash"`
+	Signature        policyAction `toml:"signature"`
+}
+
+// action returns the configured action for a check.
+// Returns an empty string if none is configured.
+func (p *policy) action(check policyCheck) policyAction {
+	if p == nil {
+		return ""
+	}
+	switch check {
+	case schemaCheck:
+		return p.Schema
+	case remoteValidationCheck:
+		return p.RemoteValidation
+	case hashCheck:
+		return p.Hash
+	case signatureCheck:
+		return p.Signature
+	}
+	return ""
+}
+
+// policyAction returns the action for the advisories of the provider
+// failing the given check. The provider specific policy has precedence
+// over the global one. Advisories are rejected by default.
+func (p *provider) policyAction(c *config, check policyCheck) policyAction {
+	if a := p.Policy.action(check); a != "" {
+		return a
+	}
+	if a := c.Policy.action(check); a != "" {
+		return a
+	}
+	return policyReject
+}
+
+// policyDecision records the decision about an advisory failing a check.
+type policyDecision struct {
+	File   string       `json:"file"`
+	Check  policyCheck  `json:"check"`
+	Action policyAction `json:"action"`
+	Reason string       `json:"reason"`
+}
+
+// decide records a policy decision in the stats.
+func (ms *mirrorStats) decide(d policyDecision) {
+	if ms == nil {
+		return
+	}
+	switch d.Check {
+	case schemaCheck:
+		ms.Invalid++
+	case remoteValidationCheck:
+		ms.RemoteInvalid++
+	case hashCheck:
+		ms.HashMismatch++
+	case signatureCheck:
+		ms.SignatureFailed++
+	}
+	switch d.Action {
+	case policyReject:
+		ms.Rejected++
+	case policyQuarantine:
+		ms.Quarantined++
+	case policyWarn:
+		ms.Warned++
+	}
+	ms.Decisions = append(ms.Decisions, d)
+}
+
+// quarantine stores an advisory failing a check in the quarantine
+// folder along with a file containing the reason.
+func (w *worker) quarantine(label, filename string, data []byte, reason string) error {
+	dir := filepath.Join(w.processor.cfg.QuarantineFolder, w.provider.Name, label)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	fname := filepath.Join(dir, filename)
+	if err := os.WriteFile(fname, data, 0644); err != nil {
+		return err
+	}
+	return os.WriteFile(fname+".reason", []byte(reason+"\n"), 0644)
+}
+
+// violation applies the policy of the provider to an advisory
+// failing a check. It returns true if the advisory is to be
+// mirrored nevertheless.
+func (w *worker) violation(
+	check policyCheck,
+	label, filename string,
+	data []byte,
+	reason string,
+) bool {
+	action := w.provider.policyAction(w.processor.cfg, check)
+	w.stats.decide(policyDecision{
+		File:   label + "/" + filename,
+		Check:  check,
+		Action: action,
+		Reason: reason,
+	})
+	switch action {
+	case policyWarn:
+		log.Printf("warning: %s: %s check failed: %s. Mirroring nevertheless.\n",
+			filename, check, reason)
+		return true
+	case policyQuarantine:
+		log.Printf("%s: %s check failed: %s. Quarantined.\n",
+			filename, check, reason)
+		if err := w.quarantine(label, filename, data, reason); err != nil {
+			log.Printf("error: quarantining %s failed: %v\n", filename, err)
+		}
+	default:
+		log.Printf("%s: %s check failed: %s. Rejected.\n",
+			filename, check, reason)
+	}
+	return false
+}
+
+// checkHashes compares the hashes published by the provider with
+// the ones of the downloaded advisory. Missing hashes are not an error.
+func (w *worker) checkHashes(file csaf.AdvisoryFile, s256, s512 []byte) error {
+	for _, h := range []struct {
+		name string
+		url  string
+		sum  []byte
+	}{
+		{"SHA256", file.SHA256URL(), s256},
+		{"SHA512", file.SHA512URL(), s512},
+	} {
+		remote, err := w.fetchHash(h.url)
+		if err != nil {
+			if err != errNotFound {
+				log.Printf("error: %s: %v\n", h.url, err)
+			}
+			continue
+		}
+		if !bytes.Equal(remote, h.sum) {
+			return fmt.Errorf("%s hash does not match %s", h.name, h.url)
+		}
+	}
+	return nil
+}
+
+// vet checks a downloaded advisory against the schema, the remote
+// validator, the hashes and the signature published by the provider.
+// The policy of the provider decides about the advisories failing
+// a check. The signature has to match the public OpenPGP keys of
+// the provider. Signatures which cannot be verified as the provider
+// has no usable keys fail the signature check. It returns if the
+// advisory is to be mirrored and its signature. Advisories failing
+// a check are never signed by us.
+func (w *worker) vet(
+	file csaf.AdvisoryFile,
+	label, filename string,
+	advisory any,
+	data, s256, s512 []byte,
+) (bool, string, error) {
+
+	var failed bool
+	fails := func(check policyCheck, reason string) bool {
+		if !w.violation(check, label, filename, data, reason) {
+			return true
+		}
+		failed = true
+		return false
+	}
+
+	// Check against CSAF schema.
+	var reason string
+	errors, err := csaf.ValidateCSAF(advisory)
+	switch {
+	case err != nil:
+		reason = fmt.Sprintf("schema validation failed: %v", err)
+	case len(errors) > 0:
+		reason = fmt.Sprintf("%d schema validation errors", len(errors))
+	}
+	if reason != "" && fails(schemaCheck, reason) {
+		return false, "", nil
+	}
+
+	// Check against remote validator.
+	if rmv := w.processor.remoteValidator; rmv != nil {
+		reason = ""
+		rvr, err := rmv.Validate(advisory)
+		switch {
+		case err != nil:
+			reason = fmt.Sprintf("calling remote validator failed: %v", err)
+		case !rvr.Valid:
+			reason = "does not validate remotely"
+		}
+		if reason != "" && fails(remoteValidationCheck, reason) {
+			return false, "", nil
+		}
+	}
+
+	// Check against the hashes of the provider.
+	if err := w.checkHashes(file, s256, s512); err != nil {
+		if fails(hashCheck, err.Error()) {
+			return false, "", nil
+		}
+	}
+
+	// Check the signature of the provider.
+	fname := label + "/" + filename
+	sig, err := w.downloadSignature(file.SignURL())
+	switch {
+	case err == errNotFound:
+		w.stats.signature(fname, signatureMissing)
+	case err != nil:
+		w.stats.signature(fname, signatureInvalid)
+		if fails(signatureCheck, err.Error()) {
+			return false, "", nil
+		}
+		return true, "", nil
+	default:
+		// Verify it against the keys of the provider.
+		switch result := w.verifySignature(sig, data); result {
+		case signatureVerified:
+			w.stats.signature(fname, result)
+			return true, sig, nil
+		case signatureUnverifiable:
+			// Without keys the signature is not trusted blindly.
+			w.stats.signature(fname, result)
+			if fails(signatureCheck, "provider has no usable public OpenPGP keys") {
+				return false, "", nil
+			}
+			// A signature which is not verified is not published.
+			return true, "", nil
+		}
+		const reason = "signature does not match the keys of the provider"
+		switch w.provider.signatureMismatch(w.processor.cfg) {
+		case mismatchFail:
+			w.stats.signature(fname, signatureMismatch)
+			return false, "", fmt.Errorf("%s: %s", filename, reason)
+		case mismatchResign:
+			w.stats.signature(fname, signatureResigned)
+			log.Printf("%s: %s. Replacing it.\n", filename, reason)
+		default:
+			w.stats.signature(fname, signatureMismatch)
+			if fails(signatureCheck, reason) {
+				return false, "", nil
+			}
+			return true, "", nil
+		}
+	}
+
+	if failed {
+		log.Printf("Not signing %s as it failed checks.\n", filename)
+		return true, "", nil
+	}
+
+	// Sign it our self.
+	if sig, err = w.sign(data); err != nil {
+		return false, "", err
+	}
+	return true, sig, nil
+}
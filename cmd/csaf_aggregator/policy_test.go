@@ -0,0 +1,325 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// vetAdvisory is a minimal advisory passing the schema validation.
+const vetAdvisory = `{
+  "document": {
+    "category": "csaf_base",
+    "csaf_version": "2.0",
+    "publisher": {
+      "category": "vendor",
+      "name": "Example Company",
+      "namespace": "https://example.com"
+    },
+    "title": "Example advisory",
+    "tracking": {
+      "current_release_date": "2023-01-01T10:00:00.000Z",
+      "id": "EXAMPLE-2023-0001",
+      "initial_release_date": "2023-01-01T10:00:00.000Z",
+      "revision_history": [
+        {"date": "2023-01-01T10:00:00.000Z", "number": "1", "summary": "Initial version."}
+      ],
+      "status": "final",
+      "version": "1"
+    }
+  }
+}`
+
+// newTestKeyRing creates a key ring with a new OpenPGP key.
+func newTestKeyRing(t *testing.T) *crypto.KeyRing {
+	t.Helper()
+	key, err := crypto.GenerateKey("test", "test@example.com", "x25519", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring, err := crypto.NewKeyRing(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestPolicyAction(t *testing.T) {
+	warn := &policy{Schema: policyWarn}
+	quarantine := &policy{Schema: policyQuarantine, Hash: policyWarn}
+
+	for _, tc := range []struct {
+		name     string
+		global   *policy
+		provider *policy
+		check    policyCheck
+		want     policyAction
+	}{
+		{"default", nil, nil, schemaCheck, policyReject},
+		{"global", warn, nil, schemaCheck, policyWarn},
+		{"global other check", warn, nil, hashCheck, policyReject},
+		{"provider", nil, quarantine, schemaCheck, policyQuarantine},
+		{"provider overrides global", warn, quarantine, schemaCheck, policyQuarantine},
+		{"provider falls back to global", quarantine, warn, hashCheck, policyWarn},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &provider{Policy: tc.provider}
+			if got := p.policyAction(&config{Policy: tc.global}, tc.check); got != tc.want {
+				t.Errorf("got %q expected %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPolicyActionUnmarshalText(t *testing.T) {
+	for _, a := range []policyAction{policyReject, policyQuarantine, policyWarn} {
+		var pa policyAction
+		if err := pa.UnmarshalText([]byte(a)); err != nil || pa != a {
+			t.Errorf("%q: got %q, %v", a, pa, err)
+		}
+	}
+	var pa policyAction
+	if err := pa.UnmarshalText([]byte("ignore")); err == nil {
+		t.Error("invalid action accepted")
+	}
+}
+
+func TestVet(t *testing.T) {
+	// The aggregator signs with its own key.
+	signRing := newTestKeyRing(t)
+
+	// Without a title the advisory does not pass the schema validation.
+	invalidDoc := strings.Replace(vetAdvisory, `"title": "Example advisory",`, "", 1)
+	// The version not being the latest one in the revision history
+	// is found by the mandatory tests of the validator.
+	wrongVersionDoc := strings.Replace(vetAdvisory, `"version": "1"`, `"version": "2"`, 1)
+
+	s256 := sha256.Sum256([]byte(vetAdvisory))
+	goodHash := hex.EncodeToString(s256[:]) + "  a.json\n"
+	badHash := otherSHA256 + "  a.json\n"
+
+	type decision struct {
+		check  policyCheck
+		action policyAction
+	}
+
+	for _, tc := range []struct {
+		name        string
+		doc         string
+		global      *policy
+		provider    *policy
+		validate    bool
+		files       map[string]string
+		mirrored    bool
+		signed      bool
+		decisions   []decision
+		quarantined bool
+		signature   signatureResult
+	}{
+		{
+			name:      "passes",
+			files:     map[string]string{"/a.json.sha256": goodHash},
+			mirrored:  true,
+			signed:    true,
+			signature: signatureMissing,
+		},
+		{
+			name:      "schema rejected by default",
+			doc:       invalidDoc,
+			decisions: []decision{{schemaCheck, policyReject}},
+		},
+		{
+			name:        "schema quarantined",
+			doc:         invalidDoc,
+			global:      &policy{Schema: policyQuarantine},
+			decisions:   []decision{{schemaCheck, policyQuarantine}},
+			quarantined: true,
+		},
+		{
+			name:      "schema warned",
+			doc:       invalidDoc,
+			global:    &policy{Schema: policyWarn},
+			mirrored:  true,
+			decisions: []decision{{schemaCheck, policyWarn}},
+			signature: signatureMissing,
+		},
+		{
+			name:      "provider policy overrides global",
+			doc:       invalidDoc,
+			global:    &policy{Schema: policyReject},
+			provider:  &policy{Schema: policyWarn},
+			mirrored:  true,
+			decisions: []decision{{schemaCheck, policyWarn}},
+			signature: signatureMissing,
+		},
+		{
+			name:      "validator passes",
+			validate:  true,
+			mirrored:  true,
+			signed:    true,
+			signature: signatureMissing,
+		},
+		{
+			name:      "validator rejected",
+			doc:       wrongVersionDoc,
+			validate:  true,
+			decisions: []decision{{remoteValidationCheck, policyReject}},
+		},
+		{
+			name:      "validator warned",
+			doc:       wrongVersionDoc,
+			validate:  true,
+			global:    &policy{RemoteValidation: policyWarn},
+			mirrored:  true,
+			decisions: []decision{{remoteValidationCheck, policyWarn}},
+			signature: signatureMissing,
+		},
+		{
+			name:      "hash rejected",
+			files:     map[string]string{"/a.json.sha256": badHash},
+			decisions: []decision{{hashCheck, policyReject}},
+		},
+		{
+			name:        "hash quarantined",
+			files:       map[string]string{"/a.json.sha512": badHash},
+			global:      &policy{Hash: policyQuarantine},
+			decisions:   []decision{{hashCheck, policyQuarantine}},
+			quarantined: true,
+		},
+		{
+			name:      "hash warned",
+			files:     map[string]string{"/a.json.sha256": badHash},
+			global:    &policy{Hash: policyWarn},
+			mirrored:  true,
+			decisions: []decision{{hashCheck, policyWarn}},
+			signature: signatureMissing,
+		},
+		{
+			name:      "signature invalid rejected",
+			files:     map[string]string{"/a.json.asc": "no signature"},
+			decisions: []decision{{signatureCheck, policyReject}},
+			signature: signatureInvalid,
+		},
+		{
+			name:      "signature invalid warned",
+			files:     map[string]string{"/a.json.asc": "no signature"},
+			global:    &policy{Signature: policyWarn},
+			mirrored:  true,
+			decisions: []decision{{signatureCheck, policyWarn}},
+			signature: signatureInvalid,
+		},
+		{
+			name:   "warned then rejected",
+			doc:    invalidDoc,
+			files:  map[string]string{"/a.json.sha256": badHash},
+			global: &policy{Schema: policyWarn},
+			decisions: []decision{
+				{schemaCheck, policyWarn},
+				{hashCheck, policyReject},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := tc.doc
+			if doc == "" {
+				doc = vetAdvisory
+			}
+			data := []byte(doc)
+			var advisory any
+			if err := json.Unmarshal(data, &advisory); err != nil {
+				t.Fatal(err)
+			}
+			s256, s512 := sha256.Sum256(data), sha512.Sum512(data)
+
+			cfg := &config{
+				Policy:           tc.global,
+				QuarantineFolder: t.TempDir(),
+			}
+			w, url := newTestWorker(t, cfg, tc.files)
+			w.provider.Policy = tc.provider
+			w.stats = &mirrorStats{}
+			w.signRing = signRing
+			if tc.validate {
+				validator, err := (&csaf.LocalValidatorOptions{}).Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				w.processor.remoteValidator = validator
+			}
+
+			mirrored, sig, err := w.vet(
+				csaf.PlainAdvisoryFile(url+"/a.json"), "white", "a.json",
+				advisory, data, s256[:], s512[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mirrored != tc.mirrored {
+				t.Errorf("got mirrored %t expected %t", mirrored, tc.mirrored)
+			}
+			if tc.signed {
+				signature, err := crypto.NewPGPSignatureFromArmored(sig)
+				if err == nil {
+					err = signRing.VerifyDetached(
+						crypto.NewPlainMessage(data), signature, crypto.GetUnixTime())
+				}
+				if err != nil {
+					t.Errorf("not signed by the aggregator: %v", err)
+				}
+			} else if sig != "" {
+				t.Errorf("unexpected signature %q", sig)
+			}
+
+			var decisions []decision
+			for _, d := range w.stats.Decisions {
+				if d.File != "white/a.json" || d.Reason == "" {
+					t.Errorf("unexpected decision %+v", d)
+				}
+				decisions = append(decisions, decision{d.Check, d.Action})
+			}
+			if !reflect.DeepEqual(decisions, tc.decisions) {
+				t.Errorf("got decisions %v expected %v", decisions, tc.decisions)
+			}
+
+			var results []signatureResult
+			for _, s := range w.stats.Signatures {
+				results = append(results, s.Result)
+			}
+			var want []signatureResult
+			if tc.signature != "" {
+				want = []signatureResult{tc.signature}
+			}
+			if !reflect.DeepEqual(results, want) {
+				t.Errorf("got signature results %v expected %v", results, want)
+			}
+
+			fname := filepath.Join(cfg.QuarantineFolder, "example", "white", "a.json")
+			_, err = os.Stat(fname)
+			if quarantined := err == nil; quarantined != tc.quarantined {
+				t.Errorf("got quarantined %t expected %t", quarantined, tc.quarantined)
+			}
+			if tc.quarantined {
+				if reason, err := os.ReadFile(fname + ".reason"); err != nil || len(reason) == 0 {
+					t.Errorf("no reason stored: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -26,6 +26,9 @@ type processor struct {
 
 	// remoteValidator is a globally configured remote validator.
 	remoteValidator csaf.RemoteValidator
+
+	// status keeps track of the status of the providers.
+	status *statusStore
 }
 
 type summary struct {
@@ -48,6 +51,10 @@ type worker struct {
 	dir              string                      // Directory to store data to.
 	summaries        map[string][]summary        // the summaries of the advisories.
 	categories       map[string]util.Set[string] // the categories per label.
+	previous         *previousMirror             // the mirror built before.
+	stats            *mirrorStats                // the counts of the mirror run.
+	providerKeys     *crypto.KeyRing             // the public OpenPGP keys of the provider.
+	keysLoaded       bool                        // the keys of the provider were loaded.
 }
 
 func newWorker(num int, processor *processor) *worker {
@@ -202,6 +209,12 @@ func (p *processor) process() error {
 		return err
 	}
 
+	p.status = newStatusStore(p.cfg)
+
+	if p.cfg.Schedule {
+		return p.schedule()
+	}
+
 	if p.cfg.Interim {
 		return p.interim()
 	}
@@ -0,0 +1,254 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// scheduleTick is the interval in which the scheduler
+// looks for runs which are due.
+const scheduleTick = time.Minute
+
+// runKind is the kind of a run of a provider.
+type runKind string
+
+const (
+	fullRun    runKind = "full"
+	interimRun runKind = "interim"
+)
+
+// runStatus is the outcome of a run of a provider.
+type runStatus struct {
+	kind     runKind
+	started  time.Time
+	finished time.Time
+	err      error
+}
+
+// schedule is the scheduling state of a provider.
+type schedule struct {
+	provider        *provider
+	fullInterval    time.Duration
+	interimInterval time.Duration
+	nextFull        time.Time
+	nextInterim     time.Time
+	running         bool
+	fullTried       bool
+	result          *csaf.AggregatorCSAFProvider
+}
+
+// scheduler performs the full and interim runs of the
+// providers periodically. There is only one run per
+// provider at a time.
+type scheduler struct {
+	processor *processor
+
+	mu        sync.Mutex
+	schedules []*schedule
+	num       int
+
+	workers chan struct{}
+	wg      sync.WaitGroup
+	aggMu   sync.Mutex
+}
+
+func newScheduler(p *processor) *scheduler {
+	now := time.Now()
+	schedules := make([]*schedule, len(p.cfg.Providers))
+	for i, provider := range p.cfg.Providers {
+		schedules[i] = &schedule{
+			provider:        provider,
+			fullInterval:    provider.fullInterval(p.cfg),
+			interimInterval: provider.interimInterval(p.cfg),
+			nextFull:        now,
+			nextInterim:     now,
+		}
+	}
+	return &scheduler{
+		processor: p,
+		schedules: schedules,
+		workers:   make(chan struct{}, p.cfg.Workers),
+	}
+}
+
+// schedule runs the aggregator as a scheduler until it is interrupted.
+func (p *processor) schedule() error {
+
+	closeValidator, err := p.openValidator()
+	if err != nil {
+		return err
+	}
+	defer closeValidator()
+
+	ctx, stop := signal.NotifyContext(
+		context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if addr := p.cfg.StatusAddress; addr != "" {
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			return err
+		}
+		srv := &http.Server{
+			Handler:           p.status.handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+				log.Printf("error: serving status failed: %v\n", err)
+			}
+		}()
+		defer srv.Close()
+		log.Printf("Serving status at %s\n", ln.Addr())
+	}
+
+	s := newScheduler(p)
+	for _, sc := range s.schedules {
+		log.Printf("Scheduling '%s': full every %s, interim every %s\n",
+			sc.provider.Name, sc.fullInterval, sc.interimInterval)
+	}
+	s.run(ctx)
+	return nil
+}
+
+// run starts the runs which are due until ctx is done.
+// It waits for the started runs to finish before it returns.
+func (s *scheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(scheduleTick)
+	defer ticker.Stop()
+	for {
+		s.startDue(time.Now())
+		select {
+		case <-ctx.Done():
+			log.Println("Stopping scheduler. Waiting for running jobs.")
+			s.wg.Wait()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// startDue starts the runs which are due at the given time.
+// A full run has precedence over an interim run.
+func (s *scheduler) startDue(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sc := range s.schedules {
+		if sc.running {
+			continue
+		}
+		var kind runKind
+		switch {
+		case !now.Before(sc.nextFull):
+			kind = fullRun
+		case sc.interimInterval > 0 && !now.Before(sc.nextInterim):
+			kind = interimRun
+		default:
+			continue
+		}
+		sc.running = true
+		s.num++
+		s.wg.Add(1)
+		go s.execute(sc, kind, newWorker(s.num, s.processor))
+	}
+}
+
+// execute performs a run of a provider and schedules the next one.
+func (s *scheduler) execute(sc *schedule, kind runKind, w *worker) {
+	defer s.wg.Done()
+
+	// Limit the number of concurrent runs.
+	s.workers <- struct{}{}
+	defer func() { <-s.workers }()
+
+	status := &runStatus{kind: kind, started: time.Now()}
+
+	var result *csaf.AggregatorCSAFProvider
+	switch kind {
+	case fullRun:
+		if status.err = w.setupProviderFull(sc.provider); status.err == nil {
+			result, status.err = s.processor.fullWorkFor(sc.provider)(w)
+		}
+	case interimRun:
+		status.err = w.interimProvider(sc.provider)
+	}
+	status.finished = time.Now()
+
+	s.mu.Lock()
+	sc.running = false
+	// A full run covers the interims, too.
+	sc.nextInterim = status.started.Add(sc.interimInterval)
+	if kind == fullRun {
+		sc.nextFull = status.started.Add(sc.fullInterval)
+		sc.fullTried = true
+		if status.err == nil {
+			sc.result = result
+		}
+	}
+	next := sc.nextFull
+	if sc.interimInterval > 0 && sc.nextInterim.Before(next) {
+		next = sc.nextInterim
+	}
+	s.mu.Unlock()
+
+	s.processor.status.record(sc.provider, status, w)
+	s.processor.status.writeLogged()
+
+	if status.err != nil {
+		log.Printf("error: %s run of '%s' failed: %v\n",
+			kind, sc.provider.Name, status.err)
+	}
+	log.Printf("%s run of '%s' finished after %s. Next run at %s.\n",
+		kind, sc.provider.Name,
+		status.finished.Sub(status.started).Round(time.Second),
+		next.Format(time.RFC3339))
+
+	if kind == fullRun && status.err == nil {
+		if err := s.writeAggregator(); err != nil {
+			log.Printf("error: writing aggregator failed: %v\n", err)
+		}
+	}
+}
+
+// writeAggregator writes the aggregator.json from the results of
+// the last successful full runs of the providers. Nothing is written
+// before all providers had their first full run.
+func (s *scheduler) writeAggregator() error {
+	s.aggMu.Lock()
+	defer s.aggMu.Unlock()
+
+	s.mu.Lock()
+	jobs := make([]fullJob, 0, len(s.schedules))
+	for _, sc := range s.schedules {
+		if !sc.fullTried {
+			s.mu.Unlock()
+			return nil
+		}
+		if sc.result != nil {
+			jobs = append(jobs, fullJob{
+				provider:           sc.provider,
+				aggregatorProvider: sc.result,
+			})
+		}
+	}
+	s.mu.Unlock()
+
+	return s.processor.writeAggregator(jobs)
+}
@@ -0,0 +1,161 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// newTestScheduler creates a scheduler for a single mirrored provider
+// whose runs fail early without accessing the network.
+func newTestScheduler(t *testing.T) (*scheduler, *schedule) {
+	t.Helper()
+	category := csaf.AggregatorAggregator
+	full, interim := 24*time.Hour, time.Hour
+	cfg := &config{
+		Web:     t.TempDir(),
+		Workers: 1,
+		Providers: []*provider{{
+			Name:                "example",
+			Domain:              "https://127.0.0.1:1/provider-metadata.json",
+			AggregatoryCategory: &category,
+			FullInterval:        &full,
+			InterimInterval:     &interim,
+		}},
+	}
+	p := &processor{cfg: cfg, status: newStatusStore(cfg)}
+	s := newScheduler(p)
+	return s, s.schedules[0]
+}
+
+func TestStartDue(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	for _, tc := range []struct {
+		name        string
+		nextFull    time.Time
+		nextInterim time.Time
+		noInterims  bool
+		running     bool
+		want        runKind
+	}{
+		{name: "full due", nextFull: past, nextInterim: past, want: fullRun},
+		{name: "full due exactly", nextFull: now, nextInterim: future, want: fullRun},
+		{name: "interim due", nextFull: future, nextInterim: past, want: interimRun},
+		{name: "interim due exactly", nextFull: future, nextInterim: now, want: interimRun},
+		{name: "nothing due", nextFull: future, nextInterim: future},
+		{name: "no interims", nextFull: future, nextInterim: past, noInterims: true},
+		{name: "running", nextFull: past, nextInterim: past, running: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s, sc := newTestScheduler(t)
+			sc.nextFull, sc.nextInterim = tc.nextFull, tc.nextInterim
+			sc.running = tc.running
+			if tc.noInterims {
+				sc.interimInterval = 0
+			}
+
+			s.startDue(now)
+			s.wg.Wait()
+
+			ps := s.processor.status.snapshot().Providers[0]
+			if tc.want == "" {
+				if s.num != 0 || ps.LastRun != nil {
+					t.Fatalf("got %d runs expected none", s.num)
+				}
+				return
+			}
+			if s.num != 1 || ps.LastRun == nil {
+				t.Fatalf("got %d runs expected 1", s.num)
+			}
+			if ps.LastRun.Kind != tc.want {
+				t.Errorf("got %s run expected %s", ps.LastRun.Kind, tc.want)
+			}
+			if sc.running {
+				t.Error("run still marked as running")
+			}
+			started := ps.LastRun.Started
+			if !sc.nextInterim.Equal(started.Add(sc.interimInterval)) {
+				t.Errorf("next interim at %s expected %s after %s",
+					sc.nextInterim, sc.interimInterval, started)
+			}
+			switch tc.want {
+			case fullRun:
+				if !sc.nextFull.Equal(started.Add(sc.fullInterval)) {
+					t.Errorf("next full at %s expected %s after %s",
+						sc.nextFull, sc.fullInterval, started)
+				}
+				// The full runs fail as the provider is not reachable.
+				if ps.LastError == nil {
+					t.Error("failed run not recorded")
+				}
+				if !sc.fullTried || sc.result != nil {
+					t.Errorf("got tried %t and result %v expected tried without result",
+						sc.fullTried, sc.result)
+				}
+			case interimRun:
+				// There are no interims to check.
+				if ps.LastError != nil {
+					t.Errorf("interim run failed: %s", ps.LastError.Error)
+				}
+				if !sc.nextFull.Equal(tc.nextFull) {
+					t.Errorf("interim run moved next full to %s", sc.nextFull)
+				}
+				if sc.fullTried {
+					t.Error("interim run marked full run as tried")
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleNoOverlap(t *testing.T) {
+	s, sc := newTestScheduler(t)
+	now := time.Now()
+
+	// Occupy the only worker so that the started run has to wait.
+	s.workers <- struct{}{}
+	s.startDue(now)
+	if s.num != 1 || !sc.running {
+		t.Fatalf("got %d runs, running %t expected 1 running", s.num, sc.running)
+	}
+
+	// No further run is started while the first one is running,
+	// even if the next ones are due.
+	s.startDue(now)
+	s.startDue(now.Add(48 * time.Hour))
+	if s.num != 1 {
+		t.Fatalf("got %d runs expected 1", s.num)
+	}
+
+	<-s.workers
+	s.wg.Wait()
+	if sc.running {
+		t.Fatal("run still marked as running")
+	}
+
+	// The next runs are scheduled relative to the start of the run.
+	s.startDue(now)
+	s.wg.Wait()
+	if s.num != 1 {
+		t.Fatalf("got %d runs expected 1", s.num)
+	}
+	s.startDue(sc.nextInterim)
+	s.wg.Wait()
+	if s.num != 2 {
+		t.Fatalf("got %d runs expected 2", s.num)
+	}
+	if kind := s.processor.status.snapshot().Providers[0].LastRun.Kind; kind != interimRun {
+		t.Errorf("got %s run expected %s", kind, interimRun)
+	}
+}
@@ -0,0 +1,206 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// mismatchAction is what happens with an advisory whose signature
+// does not match the public OpenPGP keys of the provider.
+type mismatchAction string
+
+const (
+	// mismatchSkip lets the advisory fail the signature check.
+	// The policy decides what happens with it.
+	mismatchSkip mismatchAction = "skip"
+	// mismatchFail fails the run of the provider.
+	mismatchFail mismatchAction = "fail"
+	// mismatchResign replaces the signature by one of the aggregator.
+	mismatchResign mismatchAction = "resign"
+)
+
+// UnmarshalText implements [encoding.TextUnmarshaler].
+func (ma *mismatchAction) UnmarshalText(text []byte) error {
+	switch a := mismatchAction(text); a {
+	case mismatchSkip, mismatchFail, mismatchResign:
+		*ma = a
+		return nil
+	}
+	return fmt.Errorf("invalid signature mismatch action %q", text)
+}
+
+// signatureMismatch returns the action for the advisories of the provider
+// whose signatures do not match the keys of the provider.
+func (p *provider) signatureMismatch(c *config) mismatchAction {
+	if p.SignatureMismatch != nil {
+		return *p.SignatureMismatch
+	}
+	if c.SignatureMismatch != "" {
+		return c.SignatureMismatch
+	}
+	return mismatchSkip
+}
+
+// signatureResult is the outcome of the verification
+// of the signature of an advisory.
+type signatureResult string
+
+const (
+	// signatureVerified means the signature matches a key of the provider.
+	signatureVerified signatureResult = "verified"
+	// signatureMismatch means the signature matches no key of the provider.
+	signatureMismatch signatureResult = "mismatch"
+	// signatureResigned means the signature matches no key of the
+	// provider and was replaced by one of the aggregator.
+	signatureResigned signatureResult = "resigned"
+	// signatureUnverifiable means the provider has no usable keys.
+	signatureUnverifiable signatureResult = "unverifiable"
+	// signatureMissing means the provider has no signature.
+	signatureMissing signatureResult = "missing"
+	// signatureInvalid means the signature could not be loaded.
+	signatureInvalid signatureResult = "invalid"
+)
+
+// signatureRecord records the result of the verification
+// of the signature of an advisory.
+type signatureRecord struct {
+	File   string          `json:"file"`
+	Result signatureResult `json:"result"`
+}
+
+// signature records the result of the verification of a signature.
+func (ms *mirrorStats) signature(file string, result signatureResult) {
+	if ms == nil {
+		return
+	}
+	switch result {
+	case signatureVerified:
+		ms.SignaturesVerified++
+	case signatureMismatch, signatureResigned:
+		ms.SignaturesMismatched++
+	case signatureUnverifiable:
+		ms.SignaturesUnverifiable++
+	}
+	ms.Signatures = append(ms.Signatures, signatureRecord{
+		File:   file,
+		Result: result,
+	})
+}
+
+// providerKeyRing returns the public OpenPGP keys of the provider.
+// They are loaded on first use. Returns nil if there are no usable keys.
+func (w *worker) providerKeyRing() *crypto.KeyRing {
+	if w.keysLoaded {
+		return w.providerKeys
+	}
+	w.keysLoaded = true
+
+	if w.metadataProvider == nil {
+		if err := w.locateProviderMetadata(w.provider.Domain); err != nil {
+			log.Printf("error: loading OpenPGP keys of '%s' failed: %v\n",
+				w.provider.Name, err)
+			return nil
+		}
+	}
+
+	var keys []csaf.PGPKey
+	if err := w.expr.Extract(
+		`$.public_openpgp_keys`,
+		util.ReMarshalMatcher(&keys), false, w.metadataProvider,
+	); err != nil {
+		log.Printf("No public OpenPGP keys found for '%s'.\n", w.provider.Name)
+		return nil
+	}
+
+	base, err := url.Parse(w.loc)
+	if err != nil {
+		log.Printf("error: %v\n", err)
+		return nil
+	}
+
+	for i := range keys {
+		key := &keys[i]
+		if key.URL == nil {
+			log.Printf("ignoring PGP key without URL: %s\n", key.Fingerprint)
+			continue
+		}
+		up, err := url.Parse(*key.URL)
+		if err != nil {
+			log.Printf("error: invalid URL '%s': %v\n", *key.URL, err)
+			continue
+		}
+		u := base.ResolveReference(up).String()
+
+		ckey, err := w.downloadKey(u)
+		if err != nil {
+			log.Printf("error: loading PGP key %s failed: %v\n", u, err)
+			continue
+		}
+		if !strings.EqualFold(ckey.GetFingerprint(), string(key.Fingerprint)) {
+			log.Printf("error: fingerprint of PGP key %s does not match %s\n",
+				u, key.Fingerprint)
+			continue
+		}
+		if w.providerKeys == nil {
+			if w.providerKeys, err = crypto.NewKeyRing(ckey); err != nil {
+				log.Printf("error: %v\n", err)
+			}
+		} else if err := w.providerKeys.AddKey(ckey); err != nil {
+			log.Printf("error: %v\n", err)
+		}
+	}
+
+	if w.providerKeys == nil {
+		log.Printf("No usable public OpenPGP keys for '%s'.\n", w.provider.Name)
+	}
+	return w.providerKeys
+}
+
+// downloadKey downloads a public OpenPGP key.
+func (w *worker) downloadKey(url string) (*crypto.Key, error) {
+	res, err := w.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status code %d (%s)", res.StatusCode, res.Status)
+	}
+	return crypto.NewKeyFromArmoredReader(res.Body)
+}
+
+// verifySignature verifies an armored signature of data
+// against the public OpenPGP keys of the provider.
+// Signatures which cannot be parsed match none of the keys.
+func (w *worker) verifySignature(sig string, data []byte) signatureResult {
+	keys := w.providerKeyRing()
+	if keys == nil {
+		return signatureUnverifiable
+	}
+	signature, err := crypto.NewPGPSignatureFromArmored(sig)
+	if err != nil {
+		return signatureMismatch
+	}
+	if err := keys.VerifyDetached(
+		crypto.NewPlainMessage(data), signature, crypto.GetUnixTime(),
+	); err != nil {
+		return signatureMismatch
+	}
+	return signatureVerified
+}
@@ -0,0 +1,416 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// armoredSignature creates an armored detached signature of data.
+func armoredSignature(t *testing.T, ring *crypto.KeyRing, data []byte) string {
+	t.Helper()
+	sig, err := ring.SignDetached(crypto.NewPlainMessage(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	armored, err := sig.GetArmored()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return armored
+}
+
+func TestSignatureMismatch(t *testing.T) {
+	resign, fail := mismatchResign, mismatchFail
+
+	for _, tc := range []struct {
+		name     string
+		global   mismatchAction
+		provider *mismatchAction
+		want     mismatchAction
+	}{
+		{"default", "", nil, mismatchSkip},
+		{"global", mismatchResign, nil, mismatchResign},
+		{"provider", "", &fail, mismatchFail},
+		{"provider overrides global", mismatchFail, &resign, mismatchResign},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &provider{SignatureMismatch: tc.provider}
+			if got := p.signatureMismatch(&config{SignatureMismatch: tc.global}); got != tc.want {
+				t.Errorf("got %q expected %q", got, tc.want)
+			}
+		})
+	}
+
+	for _, a := range []mismatchAction{mismatchSkip, mismatchFail, mismatchResign} {
+		var ma mismatchAction
+		if err := ma.UnmarshalText([]byte(a)); err != nil || ma != a {
+			t.Errorf("%q: got %q, %v", a, ma, err)
+		}
+	}
+	var ma mismatchAction
+	if err := ma.UnmarshalText([]byte("ignore")); err == nil {
+		t.Error("invalid action accepted")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	providerKeys, otherKeys := newTestKeyRing(t), newTestKeyRing(t)
+	data := []byte(vetAdvisory)
+
+	for _, tc := range []struct {
+		name string
+		keys *crypto.KeyRing
+		sig  string
+		want signatureResult
+	}{
+		{
+			name: "verified",
+			keys: providerKeys,
+			sig:  armoredSignature(t, providerKeys, data),
+			want: signatureVerified,
+		},
+		{
+			name: "other key",
+			keys: providerKeys,
+			sig:  armoredSignature(t, otherKeys, data),
+			want: signatureMismatch,
+		},
+		{
+			name: "other data",
+			keys: providerKeys,
+			sig:  armoredSignature(t, providerKeys, []byte("{}")),
+			want: signatureMismatch,
+		},
+		{
+			name: "broken signature",
+			keys: providerKeys,
+			sig:  "no signature",
+			want: signatureMismatch,
+		},
+		{
+			name: "no keys",
+			sig:  armoredSignature(t, providerKeys, data),
+			want: signatureUnverifiable,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w := newWorker(1, &processor{cfg: &config{}})
+			w.providerKeys, w.keysLoaded = tc.keys, true
+			if got := w.verifySignature(tc.sig, data); got != tc.want {
+				t.Errorf("got %q expected %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestProviderKeyRing(t *testing.T) {
+	providerKeys, otherKeys := newTestKeyRing(t), newTestKeyRing(t)
+	providerKey, otherKey := providerKeys.GetKeys()[0], otherKeys.GetKeys()[0]
+	armored := func(key *crypto.Key) string {
+		pub, err := key.GetArmoredPublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pub
+	}
+	files := map[string]string{
+		"/.well-known/csaf/openpgp/provider.asc": armored(providerKey),
+		"/.well-known/csaf/openpgp/other.asc":    armored(otherKey),
+	}
+	entry := func(url string, key *crypto.Key) any {
+		e := map[string]any{"fingerprint": strings.ToUpper(key.GetFingerprint())}
+		if url != "" {
+			e["url"] = url
+		}
+		return e
+	}
+
+	for _, tc := range []struct {
+		name    string
+		keys    []any
+		trusted []*crypto.KeyRing
+	}{
+		{
+			name:    "relative URL",
+			keys:    []any{entry("openpgp/provider.asc", providerKey)},
+			trusted: []*crypto.KeyRing{providerKeys},
+		},
+		{
+			name:    "absolute URL",
+			keys:    []any{entry("/.well-known/csaf/openpgp/provider.asc", providerKey)},
+			trusted: []*crypto.KeyRing{providerKeys},
+		},
+		{
+			name: "several keys",
+			keys: []any{
+				entry("openpgp/provider.asc", providerKey),
+				entry("openpgp/other.asc", otherKey),
+			},
+			trusted: []*crypto.KeyRing{providerKeys, otherKeys},
+		},
+		{
+			name: "fingerprint mismatch",
+			keys: []any{entry("openpgp/other.asc", providerKey)},
+		},
+		{
+			name: "without URL",
+			keys: []any{entry("", providerKey)},
+		},
+		{
+			name: "not found",
+			keys: []any{entry("openpgp/missing.asc", providerKey)},
+		},
+		{
+			name: "one usable",
+			keys: []any{
+				entry("openpgp/missing.asc", otherKey),
+				entry("openpgp/provider.asc", providerKey),
+			},
+			trusted: []*crypto.KeyRing{providerKeys},
+		},
+		{
+			name: "no keys",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			w, url := newTestWorker(t, &config{}, files)
+			w.loc = url + "/.well-known/csaf/provider-metadata.json"
+			pmd := map[string]any{}
+			if tc.keys != nil {
+				pmd["public_openpgp_keys"] = tc.keys
+			}
+			w.metadataProvider = pmd
+
+			ring := w.providerKeyRing()
+			if len(tc.trusted) == 0 {
+				if ring != nil {
+					t.Fatalf("got %d keys expected none", ring.CountEntities())
+				}
+			} else if ring == nil || ring.CountEntities() != len(tc.trusted) {
+				t.Fatalf("got %v expected %d keys", ring, len(tc.trusted))
+			}
+			data := []byte(vetAdvisory)
+			for _, keys := range tc.trusted {
+				sig := armoredSignature(t, keys, data)
+				if got := w.verifySignature(sig, data); got != signatureVerified {
+					t.Errorf("signature of trusted key: got %q", got)
+				}
+			}
+
+			// The keys are only loaded once.
+			w.metadataProvider = nil
+			if again := w.providerKeyRing(); again != ring {
+				t.Error("keys loaded again")
+			}
+		})
+	}
+}
+
+func TestVetSignature(t *testing.T) {
+	aggregatorKeys := newTestKeyRing(t)
+	providerKeys, otherKeys := newTestKeyRing(t), newTestKeyRing(t)
+
+	data := []byte(vetAdvisory)
+	var advisory any
+	if err := json.Unmarshal(data, &advisory); err != nil {
+		t.Fatal(err)
+	}
+	s256, s512 := sha256.Sum256(data), sha512.Sum512(data)
+	providerSig := armoredSignature(t, providerKeys, data)
+	otherSig := armoredSignature(t, otherKeys, data)
+
+	const (
+		none      = "none"
+		upstream  = "upstream"
+		ourselves = "ourselves"
+	)
+
+	skip, fail, resign := mismatchSkip, mismatchFail, mismatchResign
+
+	for _, tc := range []struct {
+		name     string
+		sig      string
+		noKeys   bool
+		mismatch *mismatchAction
+		policy   *policy
+		badHash  bool
+		mirrored bool
+		signed   string
+		err      bool
+		result   signatureResult
+		decision policyAction
+	}{
+		{
+			name:     "verified",
+			sig:      providerSig,
+			mirrored: true,
+			signed:   upstream,
+			result:   signatureVerified,
+		},
+		{
+			name:     "verified despite failed check",
+			sig:      providerSig,
+			badHash:  true,
+			policy:   &policy{Hash: policyWarn},
+			mirrored: true,
+			signed:   upstream,
+			result:   signatureVerified,
+		},
+		{
+			name:     "missing",
+			mirrored: true,
+			signed:   ourselves,
+			result:   signatureMissing,
+		},
+		{
+			name:     "unverifiable rejected",
+			sig:      providerSig,
+			noKeys:   true,
+			result:   signatureUnverifiable,
+			decision: policyReject,
+		},
+		{
+			name:     "unverifiable warned",
+			sig:      providerSig,
+			noKeys:   true,
+			policy:   &policy{Signature: policyWarn},
+			mirrored: true,
+			signed:   none,
+			result:   signatureUnverifiable,
+			decision: policyWarn,
+		},
+		{
+			name:     "unverifiable quarantined",
+			sig:      providerSig,
+			noKeys:   true,
+			policy:   &policy{Signature: policyQuarantine},
+			result:   signatureUnverifiable,
+			decision: policyQuarantine,
+		},
+		{
+			name:     "mismatch rejected",
+			sig:      otherSig,
+			mismatch: &skip,
+			result:   signatureMismatch,
+			decision: policyReject,
+		},
+		{
+			name:     "mismatch warned",
+			sig:      otherSig,
+			policy:   &policy{Signature: policyWarn},
+			mirrored: true,
+			signed:   none,
+			result:   signatureMismatch,
+			decision: policyWarn,
+		},
+		{
+			name:     "mismatch fails",
+			sig:      otherSig,
+			mismatch: &fail,
+			err:      true,
+			result:   signatureMismatch,
+		},
+		{
+			name:     "mismatch resigned",
+			sig:      otherSig,
+			mismatch: &resign,
+			mirrored: true,
+			signed:   ourselves,
+			result:   signatureResigned,
+		},
+		{
+			name:     "mismatch not resigned after failed check",
+			sig:      otherSig,
+			mismatch: &resign,
+			badHash:  true,
+			policy:   &policy{Hash: policyWarn},
+			mirrored: true,
+			signed:   none,
+			result:   signatureResigned,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			files := map[string]string{}
+			if tc.sig != "" {
+				files["/a.json.asc"] = tc.sig
+			}
+			if tc.badHash {
+				files["/a.json.sha256"] = otherSHA256 + "  a.json\n"
+			}
+			cfg := &config{
+				Policy:           tc.policy,
+				QuarantineFolder: t.TempDir(),
+			}
+			w, url := newTestWorker(t, cfg, files)
+			w.provider.SignatureMismatch = tc.mismatch
+			w.stats = &mirrorStats{}
+			w.signRing = aggregatorKeys
+			if !tc.noKeys {
+				w.providerKeys = providerKeys
+			}
+			w.keysLoaded = true
+
+			mirrored, sig, err := w.vet(
+				csaf.PlainAdvisoryFile(url+"/a.json"), "white", "a.json",
+				advisory, data, s256[:], s512[:])
+			if (err != nil) != tc.err {
+				t.Fatalf("got error %v", err)
+			}
+			if mirrored != tc.mirrored {
+				t.Errorf("got mirrored %t expected %t", mirrored, tc.mirrored)
+			}
+
+			switch {
+			case !tc.mirrored || tc.signed == none:
+				if sig != "" {
+					t.Errorf("got signature %q expected none", sig)
+				}
+			case tc.signed == upstream:
+				if sig != tc.sig {
+					t.Errorf("signature of provider not kept")
+				}
+			case tc.signed == ourselves:
+				signature, err := crypto.NewPGPSignatureFromArmored(sig)
+				if err == nil {
+					err = aggregatorKeys.VerifyDetached(
+						crypto.NewPlainMessage(data), signature, crypto.GetUnixTime())
+				}
+				if err != nil {
+					t.Errorf("not signed by the aggregator: %v", err)
+				}
+			}
+
+			if n := len(w.stats.Signatures); n != 1 {
+				t.Fatalf("got %d signature results expected 1", n)
+			}
+			if got := w.stats.Signatures[0]; got.Result != tc.result || got.File != "white/a.json" {
+				t.Errorf("got signature result %+v expected %q", got, tc.result)
+			}
+
+			var decision policyAction
+			for _, d := range w.stats.Decisions {
+				if d.Check == signatureCheck {
+					decision = d.Action
+				}
+			}
+			if decision != tc.decision {
+				t.Errorf("got signature decision %q expected %q", decision, tc.decision)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"encoding/json"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// mirrorStats are the counts of the advisories of a mirror run.
+type mirrorStats struct {
+	// Mirrored is the number of advisories in the mirror.
+	Mirrored int `json:"mirrored"`
+	// Unchanged is the number of advisories taken from the previous mirror.
+	Unchanged int `json:"unchanged"`
+	// Fetched is the number of advisories downloaded.
+	Fetched int `json:"fetched"`
+	// DownloadFailed is the number of advisories which could not be downloaded.
+	DownloadFailed int `json:"download_failed"`
+	// Invalid is the number of advisories failing the schema
+	// validation or the extraction of their summaries.
+	Invalid int `json:"invalid"`
+	// RemoteInvalid is the number of advisories failing the remote validation.
+	RemoteInvalid int `json:"remote_invalid"`
+	// HashMismatch is the number of advisories not matching
+	// the hashes published by the provider.
+	HashMismatch int `json:"hash_mismatch"`
+	// SignatureFailed is the number of advisories with
+	// unusable signatures published by the provider.
+	SignatureFailed int `json:"signature_failed"`
+	// Rejected, Quarantined and Warned are the numbers of
+	// the decisions of the policy.
+	Rejected    int `json:"rejected"`
+	Quarantined int `json:"quarantined"`
+	Warned      int `json:"warned"`
+	// SignaturesVerified is the number of signatures
+	// matching the public OpenPGP keys of the provider.
+	SignaturesVerified int `json:"signatures_verified"`
+	// SignaturesMismatched is the number of signatures
+	// matching none of the public OpenPGP keys of the provider.
+	SignaturesMismatched int `json:"signatures_mismatched"`
+	// SignaturesUnverifiable is the number of signatures which could
+	// not be verified as the provider has no usable public OpenPGP keys.
+	SignaturesUnverifiable int `json:"signatures_unverifiable"`
+	// Decisions are the decisions of the policy.
+	Decisions []policyDecision `json:"-"`
+	// Signatures are the results of the verification of the signatures.
+	Signatures []signatureRecord `json:"-"`
+}
+
+// runReport describes a run of a provider.
+type runReport struct {
+	Kind     runKind   `json:"kind"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Error    string    `json:"error,omitempty"`
+	// Decisions are the decisions of the policy
+	// about the advisories failing a check.
+	Decisions []policyDecision `json:"decisions,omitempty"`
+	// Signatures are the results of the verification of the
+	// upstream signatures of the advisories downloaded.
+	Signatures []signatureRecord `json:"signatures,omitempty"`
+}
+
+// providerStatus is the status of a provider.
+type providerStatus struct {
+	Name   string `json:"name"`
+	Domain string `json:"domain"`
+	Mirror bool   `json:"mirror"`
+	// LastRun is the last run of any kind.
+	LastRun *runReport `json:"last_run,omitempty"`
+	// LastSuccess is the time the last successful full run finished.
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	// LastError is the last failed run.
+	LastError *runReport `json:"last_error,omitempty"`
+	// Stale indicates that there was no successful
+	// full run for longer than expected.
+	Stale bool `json:"stale"`
+	// Advisories are the counts of the last successful full run.
+	Advisories *mirrorStats `json:"advisories,omitempty"`
+	// MirrorSize is the size of the mirror in bytes.
+	MirrorSize int64 `json:"mirror_size,omitempty"`
+}
+
+// aggregatorStatus is the status document of the aggregator.
+type aggregatorStatus struct {
+	Updated   time.Time         `json:"updated"`
+	Providers []*providerStatus `json:"providers"`
+}
+
+// statusStore keeps track of the status of the providers
+// and writes it to the configured file.
+type statusStore struct {
+	cfg   *config
+	fname string
+
+	mu        sync.Mutex
+	providers map[string]*providerStatus
+
+	writeMu sync.Mutex
+}
+
+// newStatusStore creates a new status store. The status of the
+// configured providers is taken over from an existing status file.
+func newStatusStore(cfg *config) *statusStore {
+	ss := &statusStore{
+		cfg:       cfg,
+		providers: map[string]*providerStatus{},
+	}
+	if cfg.StatusFile != nil {
+		ss.fname = *cfg.StatusFile
+	}
+	for _, p := range cfg.Providers {
+		ss.providers[p.Name] = &providerStatus{Name: p.Name}
+	}
+	if ss.fname == "" {
+		return ss
+	}
+	data, err := os.ReadFile(ss.fname)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("error: loading status failed: %v\n", err)
+		}
+		return ss
+	}
+	var old aggregatorStatus
+	if err := json.Unmarshal(data, &old); err != nil {
+		log.Printf("error: loading status failed: %v\n", err)
+		return ss
+	}
+	for _, ps := range old.Providers {
+		if ps != nil && ss.providers[ps.Name] != nil {
+			ss.providers[ps.Name] = ps
+		}
+	}
+	return ss
+}
+
+// dirSize returns the size of the regular files below dir.
+func dirSize(dir string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// record stores the outcome of a run of a provider performed by w.
+func (ss *statusStore) record(p *provider, run *runStatus, w *worker) {
+	if ss == nil {
+		return
+	}
+	report := &runReport{
+		Kind:     run.kind,
+		Started:  run.started.UTC(),
+		Finished: run.finished.UTC(),
+	}
+	if run.err != nil {
+		report.Error = run.err.Error()
+	}
+	if w.stats != nil {
+		report.Decisions = w.stats.Decisions
+		report.Signatures = w.stats.Signatures
+	}
+
+	var size int64
+	if run.kind == fullRun && run.err == nil && p.runAsMirror(ss.cfg) {
+		var err error
+		if size, err = dirSize(w.webTarget()); err != nil {
+			log.Printf("error: determining size of mirror failed: %v\n", err)
+		}
+	}
+
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ps := ss.providers[p.Name]
+	ps.Domain = p.Domain
+	ps.Mirror = p.runAsMirror(ss.cfg)
+	ps.LastRun = report
+	if run.err != nil {
+		ps.LastError = report
+		return
+	}
+	if run.kind == fullRun {
+		ps.LastSuccess = &report.Finished
+		ps.Advisories = w.stats
+		ps.MirrorSize = size
+	}
+}
+
+// staleAfter returns the duration after which a provider
+// without a successful full run is considered stale.
+func (p *provider) staleAfter(c *config) time.Duration {
+	if c.StaleAfter > 0 {
+		return c.StaleAfter
+	}
+	return 2 * p.fullInterval(c)
+}
+
+// snapshot returns the current status document.
+func (ss *statusStore) snapshot() *aggregatorStatus {
+	now := time.Now().UTC()
+	doc := &aggregatorStatus{Updated: now}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	for _, p := range ss.cfg.Providers {
+		ps := *ss.providers[p.Name]
+		ps.Stale = ps.LastSuccess == nil ||
+			now.Sub(*ps.LastSuccess) > p.staleAfter(ss.cfg)
+		doc.Providers = append(doc.Providers, &ps)
+	}
+	return doc
+}
+
+// write writes the status document to the configured file.
+func (ss *statusStore) write() error {
+	if ss == nil || ss.fname == "" {
+		return nil
+	}
+	ss.writeMu.Lock()
+	defer ss.writeMu.Unlock()
+	data, err := json.MarshalIndent(ss.snapshot(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ss.fname), 0755); err != nil {
+		return err
+	}
+	fname, f, err := util.MakeUniqFile(ss.fname + ".tmp")
+	if err != nil {
+		return err
+	}
+	_, err1 := f.Write(data)
+	err2 := f.Close()
+	if err1 == nil {
+		err1 = err2
+	}
+	if err1 != nil {
+		os.RemoveAll(fname)
+		return err1
+	}
+	return os.Rename(fname, ss.fname)
+}
+
+// writeLogged writes the status document and logs errors.
+func (ss *statusStore) writeLogged() {
+	if err := ss.write(); err != nil {
+		log.Printf("error: writing status failed: %v\n", err)
+	}
+}
+
+// serveStatus serves the status document.
+func (ss *statusStore) serveStatus(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(rw)
+	enc.SetIndent("", "  ")
+	enc.Encode(ss.snapshot())
+}
+
+// serveHealth reports if there are stale providers. It responds with
+// 503 Service Unavailable if there are any.
+func (ss *statusStore) serveHealth(rw http.ResponseWriter, _ *http.Request) {
+	stale := []string{}
+	for _, ps := range ss.snapshot().Providers {
+		if ps.Stale {
+			stale = append(stale, ps.Name)
+		}
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if len(stale) > 0 {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(rw).Encode(struct {
+		Healthy bool     `json:"healthy"`
+		Stale   []string `json:"stale"`
+	}{
+		Healthy: len(stale) == 0,
+		Stale:   stale,
+	})
+}
+
+// handler returns the HTTP handler serving the
+// status at /status and the health at /health.
+func (ss *statusStore) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", ss.serveStatus)
+	mux.HandleFunc("/health", ss.serveHealth)
+	return mux
+}
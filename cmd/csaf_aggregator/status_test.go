@@ -0,0 +1,236 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+func TestStatusRecord(t *testing.T) {
+	category := csaf.AggregatorAggregator
+	lister := &provider{Name: "lister", Domain: "lister.example.com"}
+	mirror := &provider{
+		Name:                "mirror",
+		Domain:              "mirror.example.com",
+		AggregatoryCategory: &category,
+	}
+	cfg := &config{
+		Web:       t.TempDir(),
+		Providers: []*provider{lister, mirror},
+	}
+
+	// The mirror has one advisory of 2 bytes.
+	w := newWorker(1, &processor{cfg: cfg})
+	w.provider = mirror
+	mirrorDir := filepath.Join(w.webTarget(), "white", "2023")
+	if err := os.MkdirAll(mirrorDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(
+		filepath.Join(mirrorDir, "example-2023-0001.json"), []byte("{}"), 0644,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	started := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	run := func(kind runKind, offset time.Duration, err error) *runStatus {
+		return &runStatus{
+			kind:     kind,
+			started:  started.Add(offset),
+			finished: started.Add(offset + time.Minute),
+			err:      err,
+		}
+	}
+
+	// The runs are recorded one after the other.
+	ss := newStatusStore(cfg)
+	for _, tc := range []struct {
+		name        string
+		provider    *provider
+		run         *runStatus
+		lastSuccess time.Time
+		lastError   bool
+		mirrorSize  int64
+	}{
+		{
+			name:        "full run of lister",
+			provider:    lister,
+			run:         run(fullRun, 0, nil),
+			lastSuccess: started.Add(time.Minute),
+		},
+		{
+			name:        "failed full run",
+			provider:    lister,
+			run:         run(fullRun, time.Hour, errors.New("does not work")),
+			lastSuccess: started.Add(time.Minute),
+			lastError:   true,
+		},
+		{
+			name:        "interim run",
+			provider:    lister,
+			run:         run(interimRun, 2*time.Hour, nil),
+			lastSuccess: started.Add(time.Minute),
+			lastError:   true,
+		},
+		{
+			name:        "full run of mirror",
+			provider:    mirror,
+			run:         run(fullRun, 0, nil),
+			lastSuccess: started.Add(time.Minute),
+			mirrorSize:  2,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stats := &mirrorStats{Fetched: 1}
+			w.provider, w.stats = tc.provider, stats
+			ss.record(tc.provider, tc.run, w)
+
+			ps := ss.providers[tc.provider.Name]
+			if ps.Domain != tc.provider.Domain {
+				t.Errorf("got domain %q expected %q", ps.Domain, tc.provider.Domain)
+			}
+			if ps.Mirror != (tc.provider == mirror) {
+				t.Errorf("got mirror %t", ps.Mirror)
+			}
+			if ps.LastRun == nil || ps.LastRun.Kind != tc.run.kind ||
+				!ps.LastRun.Started.Equal(tc.run.started) {
+				t.Fatalf("unexpected last run %+v", ps.LastRun)
+			}
+			if ps.LastSuccess == nil || !ps.LastSuccess.Equal(tc.lastSuccess) {
+				t.Errorf("got last success %v expected %v", ps.LastSuccess, tc.lastSuccess)
+			}
+			if (ps.LastError != nil) != tc.lastError {
+				t.Errorf("got last error %+v", ps.LastError)
+			}
+			if tc.run.kind == fullRun && tc.run.err == nil && ps.Advisories != stats {
+				t.Error("counts of successful full run not recorded")
+			}
+			if ps.MirrorSize != tc.mirrorSize {
+				t.Errorf("got mirror size %d expected %d", ps.MirrorSize, tc.mirrorSize)
+			}
+		})
+	}
+}
+
+func TestStatusSnapshot(t *testing.T) {
+	day := 24 * time.Hour
+	week := 7 * day
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		ts := now.Add(-d).UTC()
+		return &ts
+	}
+
+	for _, tc := range []struct {
+		name         string
+		staleAfter   time.Duration
+		fullInterval *time.Duration
+		lastSuccess  *time.Time
+		stale        bool
+	}{
+		{name: "never succeeded", stale: true},
+		{name: "recent", lastSuccess: ago(time.Hour)},
+		{name: "within two intervals", lastSuccess: ago(47 * time.Hour)},
+		{name: "missed two intervals", lastSuccess: ago(49 * time.Hour), stale: true},
+		{name: "long interval", fullInterval: &week, lastSuccess: ago(3 * day)},
+		{name: "configured", staleAfter: time.Hour, lastSuccess: ago(2 * time.Hour), stale: true},
+		{name: "configured not reached", staleAfter: 3 * day, lastSuccess: ago(49 * time.Hour)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &provider{Name: "example", FullInterval: tc.fullInterval}
+			cfg := &config{StaleAfter: tc.staleAfter, Providers: []*provider{p}}
+			ss := newStatusStore(cfg)
+			ss.providers[p.Name].LastSuccess = tc.lastSuccess
+
+			doc := ss.snapshot()
+			if len(doc.Providers) != 1 {
+				t.Fatalf("got %d providers expected 1", len(doc.Providers))
+			}
+			if doc.Providers[0].Stale != tc.stale {
+				t.Errorf("got stale %t expected %t", doc.Providers[0].Stale, tc.stale)
+			}
+			// The snapshot is a copy.
+			if ss.providers[p.Name].Stale {
+				t.Error("stale flag written into the store")
+			}
+
+			rec := httptest.NewRecorder()
+			ss.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+			want := http.StatusOK
+			if tc.stale {
+				want = http.StatusServiceUnavailable
+			}
+			if rec.Code != want {
+				t.Errorf("health returned %d expected %d", rec.Code, want)
+			}
+		})
+	}
+}
+
+func TestStatusStoreLoad(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "status", "status.json")
+	success := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	cfg := &config{
+		StatusFile: &fname,
+		Providers:  []*provider{{Name: "kept"}, {Name: "new"}},
+	}
+	ss := newStatusStore(cfg)
+	ss.providers["kept"].LastSuccess = &success
+	ss.providers["kept"].Domain = "kept.example.com"
+	if err := ss.write(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A removed provider is dropped, an added one starts empty.
+	cfg.Providers = []*provider{{Name: "kept"}, {Name: "added"}}
+	ss = newStatusStore(cfg)
+	if len(ss.providers) != 2 {
+		t.Fatalf("got %d providers expected 2", len(ss.providers))
+	}
+	kept := ss.providers["kept"]
+	if kept.LastSuccess == nil || !kept.LastSuccess.Equal(success) ||
+		kept.Domain != "kept.example.com" {
+		t.Errorf("status not taken over: %+v", kept)
+	}
+	if added := ss.providers["added"]; added == nil || added.LastSuccess != nil {
+		t.Errorf("unexpected status of added provider: %+v", added)
+	}
+
+	// A broken file is ignored.
+	if err := os.WriteFile(fname, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ss = newStatusStore(cfg)
+	if ss.providers["kept"].LastSuccess != nil {
+		t.Error("status taken over from broken file")
+	}
+
+	var doc aggregatorStatus
+	data, err := json.Marshal(ss.snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Providers) != 2 || doc.Providers[0].Name != "kept" {
+		t.Errorf("unexpected status document %s", data)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/csaf-poc/csaf_distribution/v3/internal/certs"
 	"github.com/csaf-poc/csaf_distribution/v3/internal/filter"
@@ -22,8 +23,10 @@ import (
 type outputFormat string
 
 const (
-	defaultPreset = "mandatory"
-	defaultFormat = "json"
+	defaultPreset       = "mandatory"
+	defaultFormat       = "json"
+	defaultRetryBackoff = time.Second
+	defaultRetryJitter  = 0.1
 )
 
 type config struct {
@@ -36,13 +39,17 @@ type config struct {
 	ClientPassphrase       *string           `long:"client_passphrase" description:"Optional passphrase for the client cert (limited, experimental, see downloader doc)" value-name:"PASSPHRASE" toml:"client_passphrase"`
 	Version                bool              `long:"version" description:"Display version of the binary" toml:"-"`
 	Verbose                bool              `long:"verbose" short:"v" description:"Verbose output" toml:"verbose"`
-	Rate                   *float64          `long:"rate" short:"r" description:"The average upper limit of https operations per second (defaults to unlimited)" toml:"rate"`
+	Rate                   *float64          `long:"rate" short:"r" description:"The average upper limit of https operations per second and host (defaults to unlimited)" toml:"rate"`
 	Range                  *models.TimeRange `long:"time_range" short:"t" description:"RANGE of time from which advisories to download" value-name:"RANGE" toml:"time_range"`
 	IgnorePattern          []string          `long:"ignore_pattern" short:"i" description:"Do not download files if their URLs match any of the given PATTERNs" value-name:"PATTERN" toml:"ignore_pattern"`
 	ExtraHeader            http.Header       `long:"header" short:"H" description:"One or more extra HTTP header fields" toml:"header"`
+	Retries                int               `long:"retries" description:"NUMber of retries of requests failing transiently" value-name:"NUM" toml:"retries"`
+	RetryBackoff           time.Duration     `long:"retry_backoff" description:"INTERVAL before the first retry, doubled with each further retry" value-name:"INTERVAL" toml:"retry_backoff"`
+	RetryJitter            float64           `long:"retry_jitter" description:"FRACTION by which the retry intervals are varied randomly" value-name:"FRACTION" toml:"retry_jitter"`
 	RemoteValidator        string            `long:"validator" description:"URL to validate documents remotely" value-name:"URL" toml:"validator"`
 	RemoteValidatorCache   string            `long:"validator_cache" description:"FILE to cache remote validations" value-name:"FILE" toml:"validator_cache"`
-	RemoteValidatorPresets []string          `long:"validator_preset" description:"One or more presets to validate remotely" toml:"validator_preset"`
+	RemoteValidatorPresets []string          `long:"validator_preset" description:"One or more presets to validate remotely or locally" toml:"validator_preset"`
+	LocalValidator         bool              `long:"local_validator" description:"Validate documents locally with the built-in tests" toml:"local_validator"`
 
 	Config string `short:"c" long:"config" description:"Path to config TOML file" value-name:"TOML-FILE" toml:"-"`
 
@@ -82,6 +89,8 @@ func parseArgsConfig() ([]string, *config, error) {
 		SetDefaults: func(cfg *config) {
 			cfg.Format = defaultFormat
 			cfg.RemoteValidatorPresets = []string{defaultPreset}
+			cfg.RetryBackoff = defaultRetryBackoff
+			cfg.RetryJitter = defaultRetryJitter
 		},
 		// Re-establish default values if not set.
 		EnsureDefaults: func(cfg *config) {
@@ -91,6 +100,9 @@ func parseArgsConfig() ([]string, *config, error) {
 			if cfg.RemoteValidatorPresets == nil {
 				cfg.RemoteValidatorPresets = []string{defaultPreset}
 			}
+			if cfg.RetryBackoff <= 0 {
+				cfg.RetryBackoff = defaultRetryBackoff
+			}
 		},
 	}
 	return p.Parse()
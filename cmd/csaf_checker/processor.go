@@ -169,7 +169,20 @@ func newProcessor(cfg *config) (*processor, error) {
 
 	var validator csaf.RemoteValidator
 
-	if cfg.RemoteValidator != "" {
+	switch {
+	case cfg.RemoteValidator != "" && cfg.LocalValidator:
+		return nil, errors.New(
+			"remote and local validator cannot be used at the same time")
+	case cfg.LocalValidator:
+		validatorOptions := csaf.LocalValidatorOptions{
+			Presets: cfg.RemoteValidatorPresets,
+		}
+		var err error
+		if validator, err = validatorOptions.Open(); err != nil {
+			return nil, fmt.Errorf(
+				"preparing local validator failed: %w", err)
+		}
+	case cfg.RemoteValidator != "":
 		validatorOptions := csaf.RemoteValidatorOptions{
 			URL:     cfg.RemoteValidator,
 			Presets: cfg.RemoteValidatorPresets,
@@ -447,11 +460,24 @@ func (p *processor) fullClient() util.Client {
 		client = &util.LoggingClient{Client: client}
 	}
 
-	// Add optional rate limiting.
+	// Add adaptive rate limiting per host.
+	// The configured rate is the ceiling for each host.
+	var ceiling rate.Limit
 	if p.cfg.Rate != nil {
-		client = &util.LimitingClient{
-			Client:  client,
-			Limiter: rate.NewLimiter(rate.Limit(*p.cfg.Rate), 1),
+		ceiling = rate.Limit(*p.cfg.Rate)
+	}
+	client = &util.HostLimitingClient{
+		Client:  client,
+		Limiter: util.NewHostLimiter(ceiling),
+	}
+
+	// Add optional retrying of transient failures.
+	if p.cfg.Retries > 0 {
+		client = &util.RetryingClient{
+			Client:   client,
+			Attempts: p.cfg.Retries + 1,
+			Backoff:  p.cfg.RetryBackoff,
+			Jitter:   p.cfg.RetryJitter,
 		}
 	}
 	return client
@@ -721,12 +747,14 @@ func (p *processor) integrity(
 			continue
 
 		}
-		// Validate against remote validator.
+		// Validate against remote or local validator.
 		if p.validator != nil {
 			if rvr, err := p.validator.Validate(doc); err != nil {
-				p.invalidAdvisories.error("Calling remote validator on %s failed: %v", u, err)
+				p.invalidAdvisories.error("Calling validator on %s failed: %v", u, err)
 			} else if !rvr.Valid {
-				p.invalidAdvisories.error("Remote validation of %s failed.", u)
+				p.invalidAdvisories.error("Validation of %s failed.", u)
+			} else if n := rvr.Warnings(); n > 0 {
+				p.invalidAdvisories.warn("Validation of %s has %d warnings.", u, n)
 			}
 		}
 
@@ -97,7 +97,7 @@ func containsAny[E comparable](s []E, vs ...E) bool {
 func (r *validReporter) report(p *processor, domain *Domain) {
 	req := r.requirement(domain)
 	if p.validator == nil {
-		req.message(WarnType, "No remote validator configured")
+		req.message(WarnType, "No remote or local validator configured")
 	}
 	switch {
 	case !p.invalidAdvisories.used():
@@ -0,0 +1,133 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+// Package main implements the csaf_convert tool.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+type options struct {
+	Version    bool   `long:"version" description:"Display version of the binary"`
+	Output     string `short:"o" long:"output" description:"Write the converted advisories into DIR instead of stdout" value-name:"DIR"`
+	Summary    string `long:"summary" description:"Summary of the revision added for the conversion" default:"Converted to CSAF 2.1." value-name:"TEXT"`
+	NoRevision bool   `long:"no_revision" description:"Do not add a revision for the conversion"`
+	CWEVersion string `long:"cwe_version" description:"Version of the CWE specification assumed for the CWEs" default:"4.13" value-name:"VERSION"`
+	Strict     bool   `long:"strict" description:"Fail if an advisory cannot be converted losslessly"`
+}
+
+func main() {
+	opts := new(options)
+
+	parser := flags.NewParser(opts, flags.Default)
+	parser.Usage = "[OPTIONS] files..."
+	files, err := parser.Parse()
+	errCheck(err)
+
+	if opts.Version {
+		fmt.Println(util.SemVersion)
+		return
+	}
+
+	if len(files) == 0 {
+		log.Println("No files given.")
+		return
+	}
+
+	errCheck(run(opts, files))
+}
+
+// run converts the advisories in the given files.
+func run(opts *options, files []string) error {
+	if opts.Output != "" {
+		if err := os.MkdirAll(opts.Output, 0755); err != nil {
+			return err
+		}
+	}
+	var failed bool
+	for _, file := range files {
+		if err := convert(opts, file); err != nil {
+			log.Printf("error: %s: %v\n", file, err)
+			failed = true
+		}
+	}
+	if failed {
+		return errors.New("not all advisories were converted")
+	}
+	return nil
+}
+
+// convert converts the advisory in the given file.
+func convert(opts *options, file string) error {
+	adv, err := csaf.LoadAdvisory(file)
+	if err != nil {
+		return fmt.Errorf("loading failed: %w", err)
+	}
+
+	copts := &csaf.ConvertOptions{
+		Summary:    opts.Summary,
+		CWEVersion: opts.CWEVersion,
+	}
+	if !opts.NoRevision {
+		copts.Date = time.Now().UTC()
+	}
+
+	converted, issues, err := adv.ConvertToCSAF21(copts)
+	if err != nil {
+		return err
+	}
+	for _, issue := range issues {
+		log.Printf("warning: %s: %s\n", file, issue)
+	}
+	if opts.Strict && len(issues) > 0 {
+		return errors.New("advisory cannot be converted losslessly")
+	}
+
+	var doc any
+	if err := util.ReMarshalJSON(&doc, converted); err != nil {
+		return err
+	}
+	errs, err := csaf.ValidateCSAF(doc)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		for _, e := range errs {
+			log.Printf("error: %s: %s\n", file, e)
+		}
+		return errors.New("converted advisory is not schema valid")
+	}
+
+	if opts.Output == "" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(converted)
+	}
+	return csaf.SaveAdvisory(converted, filepath.Join(opts.Output, filepath.Base(file)))
+}
+
+func errCheck(err error) {
+	if err != nil {
+		if flags.WroteHelp(err) {
+			os.Exit(0)
+		}
+		log.Fatalf("error: %v\n", err)
+	}
+}
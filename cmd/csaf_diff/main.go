@@ -0,0 +1,213 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+// Package main implements the csaf_diff tool.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jessevdk/go-flags"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+type options struct {
+	Version bool `long:"version" description:"Display version of the binary"`
+	//lint:ignore SA5008 We are using choice twice: text, json.
+	Format string `short:"f" long:"format" choice:"text" choice:"json" default:"text" description:"Format of the output" value-name:"FORMAT"`
+}
+
+func main() {
+	opts := new(options)
+
+	parser := flags.NewParser(opts, flags.Default)
+	parser.Usage = "[OPTIONS] old.json new.json"
+	files, err := parser.Parse()
+	errCheck(err)
+
+	if opts.Version {
+		fmt.Println(util.SemVersion)
+		return
+	}
+
+	if len(files) != 2 {
+		log.Fatalln("error: need exactly two files to compare.")
+	}
+
+	errCheck(run(opts, files[0], files[1]))
+}
+
+// run compares the advisories in the given files.
+func run(opts *options, oldFile, newFile string) error {
+	older, err := csaf.LoadAdvisory(oldFile)
+	if err != nil {
+		return fmt.Errorf("loading %q failed: %w", oldFile, err)
+	}
+	newer, err := csaf.LoadAdvisory(newFile)
+	if err != nil {
+		return fmt.Errorf("loading %q failed: %w", newFile, err)
+	}
+	diff, err := older.Diff(newer)
+	if err != nil {
+		return err
+	}
+	if opts.Format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}
+	writeText(os.Stdout, diff)
+	return nil
+}
+
+// joinProducts joins product ids to a comma separated list.
+func joinProducts(products *csaf.Products) string {
+	if products == nil {
+		return ""
+	}
+	ids := make([]string, 0, len(*products))
+	for _, p := range *products {
+		if p != nil {
+			ids = append(ids, string(*p))
+		}
+	}
+	return strings.Join(ids, ", ")
+}
+
+// joinGroups joins product group ids to a comma separated list.
+func joinGroups(groups *csaf.ProductGroupIDs) string {
+	if groups == nil {
+		return ""
+	}
+	ids := make([]string, 0, len(*groups))
+	for _, g := range *groups {
+		if g != nil {
+			ids = append(ids, string(*g))
+		}
+	}
+	return strings.Join(ids, ", ")
+}
+
+// deref returns the value of a string pointer or "-" if it is nil.
+func deref[S ~string](s *S) string {
+	if s == nil {
+		return "-"
+	}
+	return string(*s)
+}
+
+// formatStatus formats the names of product status lists.
+func formatStatus(status []string) string {
+	if len(status) == 0 {
+		return "-"
+	}
+	return strings.Join(status, ", ")
+}
+
+// formatScore formats a score with its vector.
+func formatScore(score *float64, vector string) string {
+	if score == nil && vector == "" {
+		return "-"
+	}
+	if score == nil {
+		return vector
+	}
+	return fmt.Sprintf("%.1f (%s)", *score, vector)
+}
+
+// writeRemediations writes a list of remediations.
+func writeRemediations(w io.Writer, title, sign string, rems []*csaf.Remediation) {
+	if len(rems) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  %s:\n", title)
+	for _, r := range rems {
+		fmt.Fprintf(w, "    %s %s: %s\n", sign, deref(r.Category), deref(r.Details))
+		if ps := joinProducts(r.ProductIds); ps != "" {
+			fmt.Fprintf(w, "      products: %s\n", ps)
+		}
+		if gs := joinGroups(r.GroupIds); gs != "" {
+			fmt.Fprintf(w, "      groups: %s\n", gs)
+		}
+	}
+}
+
+// writeText writes a human readable form of the differences.
+func writeText(w io.Writer, diff *csaf.AdvisoryDiff) {
+	fmt.Fprintf(w, "Advisory %s: version %s -> %s\n",
+		diff.TrackingID, diff.OldVersion, diff.NewVersion)
+
+	if diff.Empty() {
+		fmt.Fprintln(w, "No differences found.")
+		return
+	}
+
+	writeProducts := func(title, sign string, products []csaf.ProductChange) {
+		if len(products) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s:\n", title)
+		for _, p := range products {
+			fmt.Fprintf(w, "  %s %s %q\n", sign, p.ProductID, p.Name)
+		}
+	}
+	writeProducts("Added products", "+", diff.AddedProducts)
+	writeProducts("Removed products", "-", diff.RemovedProducts)
+
+	for i := range diff.Vulnerabilities {
+		vd := &diff.Vulnerabilities[i]
+		fmt.Fprintf(w, "Vulnerability %s (%s):\n", vd.Key, vd.Change)
+		if len(vd.ProductStatus) > 0 {
+			fmt.Fprintln(w, "  Product status:")
+			for _, ps := range vd.ProductStatus {
+				fmt.Fprintf(w, "    %s: %s -> %s\n",
+					ps.ProductID, formatStatus(ps.Old), formatStatus(ps.New))
+			}
+		}
+		writeRemediations(w, "Added remediations", "+", vd.AddedRemediations)
+		writeRemediations(w, "Removed remediations", "-", vd.RemovedRemediations)
+		if len(vd.Scores) > 0 {
+			fmt.Fprintln(w, "  Scores:")
+			for _, sc := range vd.Scores {
+				fmt.Fprintf(w, "    %s CVSS %s: %s -> %s\n",
+					sc.ProductID, sc.Version,
+					formatScore(sc.OldScore, sc.OldVector),
+					formatScore(sc.NewScore, sc.NewVector))
+			}
+		}
+	}
+
+	writeRevisions := func(title, sign string, revs []*csaf.Revision) {
+		if len(revs) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s:\n", title)
+		for _, r := range revs {
+			fmt.Fprintf(w, "  %s %s (%s): %s\n",
+				sign, deref(r.Number), deref(r.Date), deref(r.Summary))
+		}
+	}
+	writeRevisions("Added revisions", "+", diff.AddedRevisions)
+	writeRevisions("Removed revisions", "-", diff.RemovedRevisions)
+}
+
+func errCheck(err error) {
+	if err != nil {
+		if flags.WroteHelp(err) {
+			os.Exit(0)
+		}
+		log.Fatalf("error: %v\n", err)
+	}
+}
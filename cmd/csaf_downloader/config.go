@@ -15,7 +15,9 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"golang.org/x/exp/slog"
@@ -27,12 +29,16 @@ import (
 )
 
 const (
-	defaultWorker         = 2
-	defaultPreset         = "mandatory"
-	defaultForwardQueue   = 5
-	defaultValidationMode = validationStrict
-	defaultLogFile        = "downloader.log"
-	defaultLogLevel       = slog.LevelInfo
+	defaultWorker                = 2
+	defaultPreset                = "mandatory"
+	defaultForwardQueue          = 5
+	defaultValidationMode        = validationStrict
+	defaultLogFile               = "downloader.log"
+	defaultLogLevel              = slog.LevelInfo
+	defaultWatchInterval         = time.Hour
+	defaultRetryBackoff          = time.Second
+	defaultRetryJitter           = 0.1
+	defaultForwardCommandTimeout = time.Minute
 )
 
 type validationMode string
@@ -51,24 +57,48 @@ type config struct {
 	ClientPassphrase     *string           `long:"client_passphrase" description:"Optional passphrase for the client cert (limited, experimental, see doc)" value-name:"PASSPHRASE" toml:"client_passphrase"`
 	Version              bool              `long:"version" description:"Display version of the binary" toml:"-"`
 	NoStore              bool              `long:"no_store" short:"n" description:"Do not store files" toml:"no_store"`
-	Rate                 *float64          `long:"rate" short:"r" description:"The average upper limit of https operations per second (defaults to unlimited)" toml:"rate"`
+	Rate                 *float64          `long:"rate" short:"r" description:"The average upper limit of https operations per second and host (defaults to unlimited)" toml:"rate"`
 	Worker               int               `long:"worker" short:"w" description:"NUMber of concurrent downloads" value-name:"NUM" toml:"worker"`
 	Range                *models.TimeRange `long:"time_range" short:"t" description:"RANGE of time from which advisories to download" value-name:"RANGE" toml:"time_range"`
 	Folder               string            `long:"folder" short:"f" description:"Download into a given subFOLDER" value-name:"FOLDER" toml:"folder"`
+	SelectTLP            []string          `long:"select_tlp" description:"Only keep advisories with one of the TLP LABELs" value-name:"LABEL" toml:"select_tlp"`
+	SelectStatus         []string          `long:"select_status" description:"Only keep advisories with one of the tracking STATUSes" value-name:"STATUS" toml:"select_status"`
+	SelectCategory       []string          `long:"select_category" description:"Only keep advisories of one of the document CATEGORYs" value-name:"CATEGORY" toml:"select_category"`
+	SelectCVE            []string          `long:"select_cve" description:"Only keep advisories about one of the CVEs" value-name:"CVE" toml:"select_cve"`
+	SelectProduct        []string          `long:"select_product" description:"Only keep advisories with a product PURL or CPE starting with one of the PREFIXes" value-name:"PREFIX" toml:"select_product"`
+	SelectMinCVSS        *float64          `long:"select_min_cvss" description:"Only keep advisories with a CVSS base score of at least SCORE" value-name:"SCORE" toml:"select_min_cvss"`
 	IgnorePattern        []string          `long:"ignore_pattern" short:"i" description:"Do not download files if their URLs match any of the given PATTERNs" value-name:"PATTERN" toml:"ignore_pattern"`
 	ExtraHeader          http.Header       `long:"header" short:"H" description:"One or more extra HTTP header fields" toml:"header"`
+	Retries              int               `long:"retries" description:"NUMber of retries of requests failing transiently" value-name:"NUM" toml:"retries"`
+	RetryBackoff         time.Duration     `long:"retry_backoff" description:"INTERVAL before the first retry, doubled with each further retry" value-name:"INTERVAL" toml:"retry_backoff"`
+	RetryJitter          float64           `long:"retry_jitter" description:"FRACTION by which the retry intervals are varied randomly" value-name:"FRACTION" toml:"retry_jitter"`
+	StateFile            string            `long:"state_file" description:"FILE to keep the state of incremental downloads in" value-name:"FILE" toml:"state_file"`
+	Report               string            `long:"report" description:"FILE to write a JSON report of the run to" value-name:"FILE" toml:"report"`
+	Aggregator           string            `long:"aggregator" description:"URL of an aggregator.json to discover the providers to download from" value-name:"URL" toml:"aggregator"`
+	AggregatorMirrors    bool              `long:"aggregator_mirrors" description:"Download from the mirrors of the aggregator instead of the providers" toml:"aggregator_mirrors"`
+	IncludeProvider      []string          `long:"include_provider" description:"Only download from the discovered providers with one of the NAMEs" value-name:"NAME" toml:"include_provider"`
+	ExcludeProvider      []string          `long:"exclude_provider" description:"Do not download from the discovered providers with one of the NAMEs" value-name:"NAME" toml:"exclude_provider"`
+	Archive              string            `long:"archive" description:"Write the downloads of the run into a tar or zip ARCHIVE instead of the directory tree" value-name:"ARCHIVE" toml:"archive"`
+	Watch                bool              `long:"watch" description:"Keep running and poll the domains for new advisories" toml:"watch"`
+	WatchInterval        time.Duration     `long:"watch_interval" description:"Minimal INTERVAL between two polls of a domain in watch mode" value-name:"INTERVAL" toml:"watch_interval"`
 
 	RemoteValidator        string   `long:"validator" description:"URL to validate documents remotely" value-name:"URL" toml:"validator"`
 	RemoteValidatorCache   string   `long:"validator_cache" description:"FILE to cache remote validations" value-name:"FILE" toml:"validator_cache"`
-	RemoteValidatorPresets []string `long:"validator_preset" description:"One or more PRESETS to validate remotely" value-name:"PRESETS" toml:"validator_preset"`
+	RemoteValidatorPresets []string `long:"validator_preset" description:"One or more PRESETS to validate remotely or locally" value-name:"PRESETS" toml:"validator_preset"`
+	LocalValidator         bool     `long:"local_validator" description:"Validate documents locally with the built-in tests" toml:"local_validator"`
 
 	//lint:ignore SA5008 We are using choice twice: strict, unsafe.
 	ValidationMode validationMode `long:"validation_mode" short:"m" choice:"strict" choice:"unsafe" value-name:"MODE" description:"MODE how strict the validation is" toml:"validation_mode"`
 
-	ForwardURL      string      `long:"forward_url" description:"URL of HTTP endpoint to forward downloads to" value-name:"URL" toml:"forward_url"`
-	ForwardHeader   http.Header `long:"forward_header" description:"One or more extra HTTP header fields used by forwarding" toml:"forward_header"`
-	ForwardQueue    int         `long:"forward_queue" description:"Maximal queue LENGTH before forwarder" value-name:"LENGTH" toml:"forward_queue"`
-	ForwardInsecure bool        `long:"forward_insecure" description:"Do not check TLS certificates from forward endpoint" toml:"forward_insecure"`
+	ForwardURL            string        `long:"forward_url" description:"URL of HTTP endpoint to forward downloads to" value-name:"URL" toml:"forward_url"`
+	ForwardHeader         http.Header   `long:"forward_header" description:"One or more extra HTTP header fields used by forwarding" toml:"forward_header"`
+	ForwardQueue          int           `long:"forward_queue" description:"Maximal queue LENGTH before forwarder" value-name:"LENGTH" toml:"forward_queue"`
+	ForwardInsecure       bool          `long:"forward_insecure" description:"Do not check TLS certificates from forward endpoint" toml:"forward_insecure"`
+	ForwardDir            string        `long:"forward_dir" description:"Spool DIRectory to forward downloads to" value-name:"DIR" toml:"forward_dir"`
+	ForwardStdout         bool          `long:"forward_stdout" description:"Forward downloads as JSON lines to STDOUT" toml:"forward_stdout"`
+	ForwardCommand        string        `long:"forward_command" description:"COMMAND to run for each forwarded download" value-name:"COMMAND" toml:"forward_command"`
+	ForwardCommandTimeout time.Duration `long:"forward_command_timeout" description:"Maximal DURATION a forward command may run" value-name:"DURATION" toml:"forward_command_timeout"`
+	ReplayForwards        bool          `long:"replay_forwards" description:"Forward the advisories which failed forwarding again and exit" toml:"-"`
 
 	LogFile *string `long:"log_file" description:"FILE to log downloading to" value-name:"FILE" toml:"log_file"`
 	//lint:ignore SA5008 We are using choice or than once: debug, info, warn, error
@@ -103,6 +133,10 @@ func parseArgsConfig() ([]string, *config, error) {
 			cfg.RemoteValidatorPresets = []string{defaultPreset}
 			cfg.ValidationMode = defaultValidationMode
 			cfg.ForwardQueue = defaultForwardQueue
+			cfg.WatchInterval = defaultWatchInterval
+			cfg.RetryBackoff = defaultRetryBackoff
+			cfg.RetryJitter = defaultRetryJitter
+			cfg.ForwardCommandTimeout = defaultForwardCommandTimeout
 			cfg.LogFile = &logFile
 			cfg.LogLevel = logLevel
 		},
@@ -119,6 +153,15 @@ func parseArgsConfig() ([]string, *config, error) {
 			default:
 				cfg.ValidationMode = validationStrict
 			}
+			if cfg.WatchInterval <= 0 {
+				cfg.WatchInterval = defaultWatchInterval
+			}
+			if cfg.RetryBackoff <= 0 {
+				cfg.RetryBackoff = defaultRetryBackoff
+			}
+			if cfg.ForwardCommandTimeout <= 0 {
+				cfg.ForwardCommandTimeout = defaultForwardCommandTimeout
+			}
 			if cfg.LogFile == nil {
 				cfg.LogFile = &logFile
 			}
@@ -161,6 +204,43 @@ func (cfg *config) verbose() bool {
 	return cfg.LogLevel.Level <= slog.LevelDebug
 }
 
+// inDirectory considers relative paths to be inside the download directory.
+func (cfg *config) inDirectory(fname string) string {
+	if filepath.IsAbs(fname) {
+		return fname
+	}
+	return filepath.Join(cfg.Directory, fname)
+}
+
+// stateFile returns the path of the file to keep the state
+// of incremental downloads in.
+func (cfg *config) stateFile() string {
+	return cfg.inDirectory(cfg.StateFile)
+}
+
+// reportFile returns the path of the file to write the report to.
+func (cfg *config) reportFile() string {
+	return cfg.inDirectory(cfg.Report)
+}
+
+// archiveFile returns the path of the archive to write the downloads to.
+func (cfg *config) archiveFile() string {
+	return cfg.inDirectory(cfg.Archive)
+}
+
+// forwardDir returns the path of the spool directory to forward to.
+func (cfg *config) forwardDir() string {
+	return cfg.inDirectory(cfg.ForwardDir)
+}
+
+// forwarding returns true if the downloads are to be forwarded.
+func (cfg *config) forwarding() bool {
+	return cfg.ForwardURL != "" ||
+		cfg.ForwardDir != "" ||
+		cfg.ForwardStdout ||
+		cfg.ForwardCommand != ""
+}
+
 // prepareDirectory ensures that the working directory
 // exists and is setup properly.
 func (cfg *config) prepareDirectory() error {
@@ -239,6 +319,24 @@ func (cfg *config) compileIgnorePatterns() error {
 	return nil
 }
 
+// checkForwardCommand checks if the forward command is an executable.
+// It is run without a shell, so it cannot be given with arguments.
+func (cfg *config) checkForwardCommand() error {
+	if cfg.ForwardCommand == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(cfg.ForwardCommand); err != nil {
+		if strings.ContainsAny(cfg.ForwardCommand, " \t") {
+			return fmt.Errorf(
+				"forward command %q is not an executable: "+
+					"arguments are not supported, use a wrapper script",
+				cfg.ForwardCommand)
+		}
+		return fmt.Errorf("forward command is not usable: %w", err)
+	}
+	return nil
+}
+
 // prepareCertificates loads the client side certificates used by the HTTP client.
 func (cfg *config) prepareCertificates() error {
 	cert, err := certs.LoadCertificate(
@@ -257,6 +355,7 @@ func (cfg *config) prepare() error {
 		(*config).prepareLogging,
 		(*config).prepareCertificates,
 		(*config).compileIgnorePatterns,
+		(*config).checkForwardCommand,
 	} {
 		if err := prepare(cfg); err != nil {
 			return err
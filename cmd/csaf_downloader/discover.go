@@ -0,0 +1,128 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+// containsName checks if name is in names ignoring the case.
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// providerSelected returns true if the provider
+// with the given name is to be downloaded from.
+func (cfg *config) providerSelected(name string) bool {
+	if len(cfg.IncludeProvider) > 0 && !containsName(cfg.IncludeProvider, name) {
+		return false
+	}
+	return !containsName(cfg.ExcludeProvider, name)
+}
+
+// loadAggregator loads the aggregator document from the given URL.
+func (d *downloader) loadAggregator(url string) (*csaf.Aggregator, error) {
+	res, err := d.httpClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(
+			"fetching aggregator from '%s' failed: %s (%d)",
+			url, res.Status, res.StatusCode)
+	}
+	var agg csaf.Aggregator
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<26)).Decode(&agg); err != nil {
+		return nil, fmt.Errorf("decoding aggregator failed: %w", err)
+	}
+	if err := agg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid aggregator: %w", err)
+	}
+	return &agg, nil
+}
+
+// discover enumerates the providers and publishers listed
+// in the configured aggregator. Returns the URLs of the provider
+// metadata to download from. These are the ones of the mirrors
+// if configured and available.
+func (d *downloader) discover() ([]string, error) {
+	agg, err := d.loadAggregator(d.cfg.Aggregator)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	add := func(metadata *csaf.AggregatorCSAFProviderMetadata, mirrors []csaf.ProviderURL) {
+		if err := metadata.Validate(); err != nil {
+			slog.Warn("Ignoring invalid entry of aggregator", "error", err)
+			return
+		}
+		name := *metadata.Publisher.Name
+		if !d.cfg.providerSelected(name) {
+			slog.Debug("Provider not selected", "name", name)
+			return
+		}
+		if d.cfg.AggregatorMirrors {
+			if len(mirrors) > 0 {
+				urls = append(urls, string(mirrors[0]))
+				return
+			}
+			slog.Info("No mirror found, using provider",
+				"name", name,
+				"url", *metadata.URL)
+		}
+		urls = append(urls, string(*metadata.URL))
+	}
+
+	for _, p := range agg.CSAFProviders {
+		add(p.Metadata, p.Mirrors)
+	}
+	for _, p := range agg.CSAFPublishers {
+		if p != nil {
+			add(p.Metadata, p.Mirrors)
+		}
+	}
+
+	slog.Info("Discovered providers",
+		"aggregator", d.cfg.Aggregator,
+		"count", len(urls))
+	return urls, nil
+}
+
+// domains returns the given domains together with the
+// ones discovered through the configured aggregator.
+func (d *downloader) domains(given []string) ([]string, error) {
+	if d.cfg.Aggregator == "" {
+		return given, nil
+	}
+	discovered, err := d.discover()
+	if err != nil {
+		return nil, fmt.Errorf("discovering providers failed: %w", err)
+	}
+	domains := append(given, discovered...)
+	if len(domains) == 0 {
+		return nil, errors.New("no domains given and no providers discovered")
+	}
+	return domains, nil
+}
@@ -0,0 +1,183 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/csaf-poc/csaf_distribution/v3/internal/options"
+)
+
+const testAggregator = `{
+  "aggregator": {
+    "category": "aggregator",
+    "name": "Example Aggregator",
+    "namespace": "https://aggregator.example"
+  },
+  "aggregator_version": "2.0",
+  "canonical_url": "https://aggregator.example/.well-known/csaf-aggregator/aggregator.json",
+  "csaf_providers": [
+    {
+      "metadata": {
+        "last_updated": "2023-07-01T12:00:00Z",
+        "publisher": {
+          "category": "vendor",
+          "name": "Alpha",
+          "namespace": "https://alpha.example"
+        },
+        "url": "https://alpha.example/.well-known/csaf/provider-metadata.json"
+      },
+      "mirrors": [
+        "https://aggregator.example/.well-known/csaf-aggregator/alpha/provider-metadata.json"
+      ]
+    },
+    {
+      "metadata": {
+        "last_updated": "2023-07-01T12:00:00Z",
+        "publisher": {
+          "category": "vendor",
+          "name": "Beta",
+          "namespace": "https://beta.example"
+        },
+        "url": "https://beta.example/.well-known/csaf/provider-metadata.json"
+      }
+    }
+  ],
+  "csaf_publishers": [
+    {
+      "metadata": {
+        "last_updated": "2023-07-01T12:00:00Z",
+        "publisher": {
+          "category": "coordinator",
+          "name": "Gamma",
+          "namespace": "https://gamma.example"
+        },
+        "url": "https://gamma.example/.well-known/csaf/provider-metadata.json"
+      },
+      "mirrors": [
+        "https://aggregator.example/.well-known/csaf-aggregator/gamma/provider-metadata.json"
+      ],
+      "update_interval": "daily"
+    }
+  ],
+  "last_updated": "2023-07-01T12:00:00Z"
+}`
+
+func TestDiscover(t *testing.T) {
+	orig := slog.Default()
+	defer slog.SetDefault(orig)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/aggregator.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, testAggregator)
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name     string
+		mirrors  bool
+		include  []string
+		exclude  []string
+		expected []string
+	}{
+		{"providers", false, nil, nil, []string{
+			"given.example",
+			"https://alpha.example/.well-known/csaf/provider-metadata.json",
+			"https://beta.example/.well-known/csaf/provider-metadata.json",
+			"https://gamma.example/.well-known/csaf/provider-metadata.json",
+		}},
+		{"mirrors", true, nil, nil, []string{
+			"given.example",
+			"https://aggregator.example/.well-known/csaf-aggregator/alpha/provider-metadata.json",
+			"https://beta.example/.well-known/csaf/provider-metadata.json",
+			"https://aggregator.example/.well-known/csaf-aggregator/gamma/provider-metadata.json",
+		}},
+		{"include", false, []string{"alpha", "Gamma"}, nil, []string{
+			"given.example",
+			"https://alpha.example/.well-known/csaf/provider-metadata.json",
+			"https://gamma.example/.well-known/csaf/provider-metadata.json",
+		}},
+		{"exclude", true, nil, []string{"Alpha"}, []string{
+			"given.example",
+			"https://beta.example/.well-known/csaf/provider-metadata.json",
+			"https://aggregator.example/.well-known/csaf-aggregator/gamma/provider-metadata.json",
+		}},
+	} {
+		cfg := &config{
+			Aggregator:        server.URL + "/aggregator.json",
+			AggregatorMirrors: tc.mirrors,
+			IncludeProvider:   tc.include,
+			ExcludeProvider:   tc.exclude,
+			LogLevel:          &options.LogLevel{Level: slog.LevelInfo},
+		}
+		d, err := newDownloader(cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := d.domains([]string{"given.example"})
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if !reflect.DeepEqual(got, tc.expected) {
+			t.Errorf("%s: got %v expected %v", tc.name, got, tc.expected)
+		}
+	}
+
+	cfg := &config{
+		Aggregator:      server.URL + "/aggregator.json",
+		IncludeProvider: []string{"delta"},
+		LogLevel:        &options.LogLevel{Level: slog.LevelInfo},
+	}
+	d, err := newDownloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.domains(nil); err == nil {
+		t.Error("no domains left should result in an error")
+	}
+	if err := d.watch(context.Background(), nil); err == nil {
+		t.Error("watching no domains should result in an error")
+	}
+
+	cfg = &config{
+		Aggregator: server.URL + "/missing.json",
+		LogLevel:   &options.LogLevel{Level: slog.LevelInfo},
+	}
+	if d, err = newDownloader(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.domains(nil); err == nil {
+		t.Error("missing aggregator should result in an error")
+	}
+}
+
+func TestArchiveDir(t *testing.T) {
+	for _, x := range [][2]string{
+		{"example.com", "example.com"},
+		{"https://example.com/.well-known/csaf/provider-metadata.json",
+			"example.com/.well-known/csaf"},
+		{"https://example.com", "example.com"},
+	} {
+		if got := archiveDir(x[0]); got != x[1] {
+			t.Errorf("%q: got %q expected %q", x[0], got, x[1])
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"crypto/sha256"
 	"crypto/sha512"
 	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,6 +36,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/internal/archive"
 	"github.com/csaf-poc/csaf_distribution/v3/util"
 )
 
@@ -44,9 +46,16 @@ type downloader struct {
 	eval      *util.PathEval
 	validator csaf.RemoteValidator
 	forwarder *forwarder
-	mkdirMu   sync.Mutex
-	statsMu   sync.Mutex
-	stats     stats
+	limiter   *util.HostLimiter
+	state     *syncState
+	report    *runReport
+	archive   *archive.Writer
+	selection *selection
+	// updateIntervals are the update intervals advertised by the domains.
+	updateIntervals map[string]time.Duration
+	mkdirMu         sync.Mutex
+	statsMu         sync.Mutex
+	stats           stats
 }
 
 // failedValidationDir is the name of the sub folder
@@ -58,7 +67,20 @@ func newDownloader(cfg *config) (*downloader, error) {
 
 	var validator csaf.RemoteValidator
 
-	if cfg.RemoteValidator != "" {
+	switch {
+	case cfg.RemoteValidator != "" && cfg.LocalValidator:
+		return nil, errors.New(
+			"remote and local validator cannot be used at the same time")
+	case cfg.LocalValidator:
+		validatorOptions := csaf.LocalValidatorOptions{
+			Presets: cfg.RemoteValidatorPresets,
+		}
+		var err error
+		if validator, err = validatorOptions.Open(); err != nil {
+			return nil, fmt.Errorf(
+				"preparing local validator failed: %w", err)
+		}
+	case cfg.RemoteValidator != "":
 		validatorOptions := csaf.RemoteValidatorOptions{
 			URL:     cfg.RemoteValidator,
 			Presets: cfg.RemoteValidatorPresets,
@@ -72,10 +94,51 @@ func newDownloader(cfg *config) (*downloader, error) {
 		validator = csaf.SynchronizedRemoteValidator(validator)
 	}
 
+	switch {
+	case cfg.Archive != "" && cfg.Watch:
+		return nil, errors.New("archive cannot be written in watch mode")
+	case cfg.Archive != "" && cfg.NoStore:
+		return nil, errors.New("archive cannot be written without storing")
+	}
+
+	var state *syncState
+	switch {
+	case cfg.StateFile != "":
+		var err error
+		if state, err = loadSyncState(cfg.stateFile()); err != nil {
+			return nil, fmt.Errorf(
+				"loading state of incremental downloads failed: %w", err)
+		}
+	case cfg.Watch:
+		// Only download new advisories in the following polls.
+		state = new(syncState)
+	}
+
+	selection, err := newSelection(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("preparing selection failed: %w", err)
+	}
+
+	var report *runReport
+	if cfg.Report != "" {
+		report = newRunReport()
+	}
+
+	// The configured rate is the ceiling for each host.
+	var ceiling rate.Limit
+	if cfg.Rate != nil {
+		ceiling = rate.Limit(*cfg.Rate)
+	}
+
 	return &downloader{
-		cfg:       cfg,
-		eval:      util.NewPathEval(),
-		validator: validator,
+		cfg:             cfg,
+		eval:            util.NewPathEval(),
+		validator:       validator,
+		state:           state,
+		report:          report,
+		selection:       selection,
+		limiter:         util.NewHostLimiter(ceiling),
+		updateIntervals: map[string]time.Duration{},
 	}, nil
 }
 
@@ -93,6 +156,26 @@ func (d *downloader) addStats(o *stats) {
 	d.stats.add(o)
 }
 
+// unfinished returns the total number of advisories which could
+// not be downloaded or stored. They are retried in the next run.
+func (d *downloader) unfinished() int {
+	d.statsMu.Lock()
+	defer d.statsMu.Unlock()
+	return d.stats.downloadFailed + d.stats.storeFailed
+}
+
+// saveState writes the state of incremental downloads to disk.
+func (d *downloader) saveState() error {
+	if d.state == nil || d.cfg.StateFile == "" {
+		return nil
+	}
+	if err := d.state.save(d.cfg.stateFile()); err != nil {
+		return fmt.Errorf(
+			"saving state of incremental downloads failed: %w", err)
+	}
+	return nil
+}
+
 // logRedirect logs redirects of the http client.
 func logRedirect(req *http.Request, via []*http.Request) error {
 	vs := make([]string, len(via))
@@ -144,17 +227,38 @@ func (d *downloader) httpClient() util.Client {
 		}
 	}
 
-	// Add optional rate limiting.
-	if d.cfg.Rate != nil {
-		client = &util.LimitingClient{
-			Client:  client,
-			Limiter: rate.NewLimiter(rate.Limit(*d.cfg.Rate), 1),
+	// Add adaptive rate limiting per host shared by all workers.
+	client = &util.HostLimitingClient{
+		Client:  client,
+		Limiter: d.limiter,
+	}
+
+	// Add optional retrying of transient failures.
+	if d.cfg.Retries > 0 {
+		client = &util.RetryingClient{
+			Client:   client,
+			Attempts: d.cfg.Retries + 1,
+			Backoff:  d.cfg.RetryBackoff,
+			Jitter:   d.cfg.RetryJitter,
+			Log:      retryLog("downloader"),
 		}
 	}
 
 	return client
 }
 
+// retryLog does structured logging in a [util.RetryingClient].
+func retryLog(who string) func(string, string, int, time.Duration) {
+	return func(method, url string, attempt int, delay time.Duration) {
+		slog.Warn("Retrying request",
+			"who", who,
+			"method", method,
+			"url", url,
+			"attempt", attempt,
+			"delay", delay)
+	}
+}
+
 // httpLog does structured logging in a [util.LoggingClient].
 func httpLog(who string) func(string, string) {
 	return func(method, url string) {
@@ -189,6 +293,23 @@ func (d *downloader) download(ctx context.Context, domain string) error {
 		return fmt.Errorf("invalid URL '%s': %v", lpmd.URL, err)
 	}
 
+	// Keep a snapshot of the provider metadata in the archive.
+	if d.archive != nil {
+		pmd, err := json.MarshalIndent(lpmd.Document, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := d.archive.Add(
+			path.Join(archiveDir(domain), "provider-metadata.json"), "", "", pmd,
+		); err != nil {
+			return fmt.Errorf("archiving provider-metadata.json failed: %w", err)
+		}
+	}
+
+	if interval, ok := d.advertisedUpdateInterval(lpmd.Document); ok {
+		d.updateIntervals[domain] = interval
+	}
+
 	if err := d.loadOpenPGPKeys(
 		client,
 		lpmd.Document,
@@ -210,13 +331,33 @@ func (d *downloader) download(ctx context.Context, domain string) error {
 		afp.AgeAccept = d.cfg.Range.Contains
 	}
 
+	// complete is true if all files of the last source were downloaded.
+	var complete bool
+
+	// Only download the entries changed since the last run.
+	if d.state != nil {
+		afp.Since = func(source string) time.Time {
+			return d.state.since(domain, source)
+		}
+		afp.Seen = func(source string, newest time.Time) {
+			// Retry failed downloads in the next run.
+			if complete {
+				d.state.seen(domain, source, newest)
+			}
+		}
+	}
+
 	return afp.Process(func(label csaf.TLPLabel, files []csaf.AdvisoryFile) error {
-		return d.downloadFiles(ctx, label, files)
+		failed := d.unfinished()
+		err := d.downloadFiles(ctx, domain, label, files)
+		complete = err == nil && ctx.Err() == nil && d.unfinished() == failed
+		return err
 	})
 }
 
 func (d *downloader) downloadFiles(
 	ctx context.Context,
+	domain string,
 	label csaf.TLPLabel,
 	files []csaf.AdvisoryFile,
 ) error {
@@ -244,7 +385,7 @@ func (d *downloader) downloadFiles(
 
 	for i := 0; i < n; i++ {
 		wg.Add(1)
-		go d.downloadWorker(ctx, &wg, label, advisoryCh, errorCh)
+		go d.downloadWorker(ctx, &wg, domain, label, advisoryCh, errorCh)
 	}
 
 allFiles:
@@ -264,12 +405,17 @@ allFiles:
 	return errors.Join(errs...)
 }
 
+// loadOpenPGPKeys replaces the keys to check the signatures with
+// the public OpenPGP keys listed in the provider metadata doc.
+// Keys of previously downloaded domains are not kept.
 func (d *downloader) loadOpenPGPKeys(
 	client util.Client,
 	doc any,
 	base *url.URL,
 ) error {
 
+	d.keys = nil
+
 	src, err := d.eval.Eval("$.public_openpgp_keys", doc)
 	if err != nil {
 		// no keys.
@@ -378,6 +524,7 @@ func (d *downloader) logValidationIssues(url string, errors []string, err error)
 func (d *downloader) downloadWorker(
 	ctx context.Context,
 	wg *sync.WaitGroup,
+	domain string,
 	label csaf.TLPLabel,
 	files <-chan csaf.AdvisoryFile,
 	errorCh chan<- error,
@@ -410,9 +557,12 @@ nextAdvisory:
 			return
 		}
 
+		rep := d.report.advisory(domain, file.URL())
+
 		u, err := url.Parse(file.URL())
 		if err != nil {
 			stats.downloadFailed++
+			rep.fail(outcomeDownloadFailed)
 			slog.Warn("Ignoring invalid URL",
 				"url", file.URL(),
 				"error", err)
@@ -420,6 +570,7 @@ nextAdvisory:
 		}
 
 		if d.cfg.ignoreURL(file.URL()) {
+			rep.succeed(outcomeIgnored, "")
 			slog.Debug("Ignoring URL", "url", file.URL())
 			continue
 		}
@@ -428,22 +579,41 @@ nextAdvisory:
 		filename := filepath.Base(u.Path)
 		if !util.ConformingFileName(filename) {
 			stats.filenameFailed++
+			rep.fail(outcomeFilenameFailed)
 			slog.Warn("Ignoring none conforming filename",
 				"filename", filename)
 			continue
 		}
 
-		resp, err := client.Get(file.URL())
+		// Only download changed advisories if we know them.
+		var known *fileState
+		if d.state != nil {
+			known = d.state.file(domain, file.URL())
+		}
+
+		// Advisories in archives of former runs are not available locally.
+		notLocal := d.cfg.NoStore || d.archive != nil
+		resp, err := conditionalGet(ctx, client, file.URL(), known, notLocal)
 		if err != nil {
 			stats.downloadFailed++
+			rep.fail(outcomeDownloadFailed)
 			slog.Warn("Cannot GET",
 				"url", file.URL(),
 				"error", err)
 			continue
 		}
 
+		if resp.StatusCode == http.StatusNotModified {
+			resp.Body.Close()
+			stats.notModified++
+			rep.succeed(outcomeNotModified, "")
+			slog.Debug("Advisory not modified", "url", file.URL())
+			continue
+		}
+
 		if resp.StatusCode != http.StatusOK {
 			stats.downloadFailed++
+			rep.fail(outcomeDownloadFailed)
 			slog.Warn("Cannot load",
 				"url", file.URL(),
 				"status", resp.Status,
@@ -467,7 +637,7 @@ nextAdvisory:
 		)
 
 		// Only hash when we have a remote counter part we can compare it with.
-		if remoteSHA256, s256Data, err = loadHash(client, file.SHA256URL()); err != nil {
+		if remoteSHA256, s256Data, err = loadHash(ctx, client, file.SHA256URL()); err != nil {
 			slog.Warn("Cannot fetch SHA256",
 				"url", file.SHA256URL(),
 				"error", err)
@@ -476,7 +646,7 @@ nextAdvisory:
 			writers = append(writers, s256)
 		}
 
-		if remoteSHA512, s512Data, err = loadHash(client, file.SHA512URL()); err != nil {
+		if remoteSHA512, s512Data, err = loadHash(ctx, client, file.SHA512URL()); err != nil {
 			slog.Warn("Cannot fetch SHA512",
 				"url", file.SHA512URL(),
 				"error", err)
@@ -500,16 +670,26 @@ nextAdvisory:
 			return json.NewDecoder(tee).Decode(&doc)
 		}(); err != nil {
 			stats.downloadFailed++
+			rep.fail(outcomeDownloadFailed)
 			slog.Warn("Downloading failed",
 				"url", file.URL(),
 				"error", err)
 			continue
 		}
 
+		// Only keep the advisories selected by their content.
+		if !d.selection.selects(doc, label) {
+			stats.notSelected++
+			rep.succeed(outcomeNotSelected, "")
+			slog.Debug("Advisory not selected", "url", file.URL())
+			continue
+		}
+
 		// Compare the checksums.
 		s256Check := func() error {
 			if s256 != nil && !bytes.Equal(s256.Sum(nil), remoteSHA256) {
 				stats.sha256Failed++
+				rep.fail(outcomeSHA256Failed)
 				return fmt.Errorf("SHA256 checksum of %s does not match", file.URL())
 			}
 			return nil
@@ -518,6 +698,7 @@ nextAdvisory:
 		s512Check := func() error {
 			if s512 != nil && !bytes.Equal(s512.Sum(nil), remoteSHA512) {
 				stats.sha512Failed++
+				rep.fail(outcomeSHA512Failed)
 				return fmt.Errorf("SHA512 checksum of %s does not match", file.URL())
 			}
 			return nil
@@ -530,7 +711,7 @@ nextAdvisory:
 				return nil
 			}
 			var sign *crypto.PGPSignature
-			sign, signData, err = loadSignature(client, file.SignURL())
+			sign, signData, err = loadSignature(ctx, client, file.SignURL())
 			if err != nil {
 				slog.Warn("Downloading signature failed",
 					"url", file.SignURL(),
@@ -540,6 +721,7 @@ nextAdvisory:
 				if err := d.checkSignature(data.Bytes(), sign); err != nil {
 					if !d.cfg.IgnoreSignatureCheck {
 						stats.signatureFailed++
+						rep.fail(outcomeSignatureFailed)
 						return fmt.Errorf("cannot verify signature for %s: %v", file.URL(), err)
 					}
 				}
@@ -551,6 +733,7 @@ nextAdvisory:
 		schemaCheck := func() error {
 			if errors, err := csaf.ValidateCSAF(doc); err != nil || len(errors) > 0 {
 				stats.schemaFailed++
+				rep.fail(outcomeSchemaFailed)
 				d.logValidationIssues(file.URL(), errors, err)
 				return fmt.Errorf("schema validation for %q failed", file.URL())
 			}
@@ -561,6 +744,7 @@ nextAdvisory:
 		filenameCheck := func() error {
 			if err := util.IDMatchesFilename(d.eval, doc, filename); err != nil {
 				stats.filenameFailed++
+				rep.fail(outcomeFilenameFailed)
 				return fmt.Errorf("filename not conforming %s: %s", file.URL(), err)
 			}
 			return nil
@@ -580,6 +764,7 @@ nextAdvisory:
 			}
 			if !rvr.Valid {
 				stats.remoteFailed++
+				rep.fail(outcomeRemoteFailed)
 				return fmt.Errorf("remote validation of %q failed", file.URL())
 			}
 			return nil
@@ -611,7 +796,24 @@ nextAdvisory:
 				filename, data.String(),
 				valStatus,
 				string(s256Data),
-				string(s512Data))
+				string(s512Data),
+				rep.forwarded)
+		}
+
+		// remember records the state of the advisory stored at path.
+		remember := func(path string) {
+			if d.state == nil {
+				return
+			}
+			sum256 := sha256.Sum256(data.Bytes())
+			sum512 := sha512.Sum512(data.Bytes())
+			d.state.store(domain, file.URL(), &fileState{
+				ETag:         resp.Header.Get("ETag"),
+				LastModified: resp.Header.Get("Last-Modified"),
+				SHA256:       hex.EncodeToString(sum256[:]),
+				SHA512:       hex.EncodeToString(sum512[:]),
+				Path:         path,
+			})
 		}
 
 		if d.cfg.NoStore {
@@ -619,6 +821,8 @@ nextAdvisory:
 			if valStatus == validValidationStatus {
 				stats.succeeded++
 			}
+			rep.succeed(outcomeDownloaded, "")
+			remember("")
 			continue
 		}
 
@@ -632,22 +836,51 @@ nextAdvisory:
 		initialReleaseDate = initialReleaseDate.UTC()
 
 		// Advisories that failed validation are stored in a special folder.
-		var newDir string
+		var relDir string
 		if valStatus != validValidationStatus {
-			newDir = path.Join(d.cfg.Directory, failedValidationDir)
-		} else {
-			newDir = d.cfg.Directory
+			relDir = failedValidationDir
 		}
 
 		// Do we have a configured destination folder?
 		if d.cfg.Folder != "" {
-			newDir = path.Join(newDir, d.cfg.Folder)
+			relDir = path.Join(relDir, d.cfg.Folder)
 		} else {
-			newDir = path.Join(newDir, lower, strconv.Itoa(initialReleaseDate.Year()))
+			relDir = path.Join(relDir, lower, strconv.Itoa(initialReleaseDate.Year()))
 		}
 
+		// Write into the archive instead of the directory tree.
+		if d.archive != nil {
+			name := path.Join(archiveDir(domain), relDir, filename)
+			for _, x := range []struct {
+				p string
+				d []byte
+			}{
+				{name, data.Bytes()},
+				{name + ".sha256", s256Data},
+				{name + ".sha512", s512Data},
+				{name + ".asc", signData},
+			} {
+				if x.d != nil {
+					if err := d.archive.Add(x.p, string(label), string(valStatus), x.d); err != nil {
+						stats.storeFailed++
+						rep.fail(outcomeStoreFailed)
+						errorCh <- err
+						continue nextAdvisory
+					}
+				}
+			}
+			remember("")
+			rep.succeed(outcomeStored, name)
+			stats.succeeded++
+			slog.Info("Archived advisory", "name", name)
+			continue
+		}
+
+		newDir := path.Join(d.cfg.Directory, relDir)
 		if newDir != lastDir {
 			if err := d.mkdirAll(newDir, 0755); err != nil {
+				stats.storeFailed++
+				rep.fail(outcomeStoreFailed)
 				errorCh <- err
 				continue
 			}
@@ -669,12 +902,16 @@ nextAdvisory:
 		} {
 			if x.d != nil {
 				if err := os.WriteFile(x.p, x.d, 0644); err != nil {
+					stats.storeFailed++
+					rep.fail(outcomeStoreFailed)
 					errorCh <- err
 					continue nextAdvisory
 				}
 			}
 		}
 
+		remember(path)
+		rep.succeed(outcomeStored, path)
 		stats.succeeded++
 		slog.Info("Written advisory", "path", path)
 	}
@@ -692,8 +929,21 @@ func (d *downloader) checkSignature(data []byte, sign *crypto.PGPSignature) erro
 	return d.keys.VerifyDetached(pm, sign, t)
 }
 
-func loadSignature(client util.Client, p string) (*crypto.PGPSignature, []byte, error) {
-	resp, err := client.Get(p)
+// get fetches the given URL. The request and its retries are cancelled with ctx.
+func get(ctx context.Context, client util.Client, p string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+	if err != nil {
+		return nil, err
+	}
+	return client.Do(req)
+}
+
+func loadSignature(
+	ctx context.Context,
+	client util.Client,
+	p string,
+) (*crypto.PGPSignature, []byte, error) {
+	resp, err := get(ctx, client, p)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -713,8 +963,8 @@ func loadSignature(client util.Client, p string) (*crypto.PGPSignature, []byte,
 	return sign, data, nil
 }
 
-func loadHash(client util.Client, p string) ([]byte, []byte, error) {
-	resp, err := client.Get(p)
+func loadHash(ctx context.Context, client util.Client, p string) ([]byte, []byte, error) {
+	resp, err := get(ctx, client, p)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -732,11 +982,91 @@ func loadHash(client util.Client, p string) ([]byte, []byte, error) {
 	return hash, data.Bytes(), nil
 }
 
+// saveReport writes the report of the run after
+// all the advisories are forwarded.
+func (d *downloader) saveReport() error {
+	if d.report == nil {
+		return nil
+	}
+	if d.forwarder != nil {
+		d.forwarder.flush()
+	}
+	if err := d.report.save(d.cfg.reportFile()); err != nil {
+		return fmt.Errorf("writing report failed: %w", err)
+	}
+	return nil
+}
+
+// archiveDir returns the folder in the archive for the given domain.
+// For URLs of provider metadata this is the host and the path
+// without the file name as aggregators serve several mirrors.
+func archiveDir(domain string) string {
+	rest, ok := strings.CutPrefix(domain, "https://")
+	if !ok {
+		return domain
+	}
+	if idx := strings.LastIndexByte(rest, '/'); idx >= 0 {
+		rest = rest[:idx]
+	}
+	return rest
+}
+
+// createArchive creates the archive the downloads are written to.
+func (d *downloader) createArchive() error {
+	if d.cfg.Archive == "" {
+		return nil
+	}
+	a, err := archive.Create(d.cfg.archiveFile())
+	if err != nil {
+		return fmt.Errorf("creating archive failed: %w", err)
+	}
+	d.archive = a
+	return nil
+}
+
+// closeArchive finishes the archive the downloads are written to.
+func (d *downloader) closeArchive() error {
+	if d.archive == nil {
+		return nil
+	}
+	err := d.archive.Close()
+	d.archive = nil
+	if err != nil {
+		return fmt.Errorf("writing archive failed: %w", err)
+	}
+	slog.Info("Written archive", "path", d.cfg.archiveFile())
+	// The archived advisories are only known now.
+	return d.saveState()
+}
+
 // run performs the downloads for all the given domains.
-func (d *downloader) run(ctx context.Context, domains []string) error {
+func (d *downloader) run(ctx context.Context, domains []string) (err error) {
 	defer d.stats.log()
+	if err := d.createArchive(); err != nil {
+		return err
+	}
+	defer func() {
+		if aerr := d.closeArchive(); aerr != nil {
+			err = errors.Join(err, aerr)
+		}
+	}()
+	defer func() {
+		if rerr := d.saveReport(); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+	}()
 	for _, domain := range domains {
-		if err := d.download(ctx, domain); err != nil {
+		dr := d.report.domain(domain)
+		err = d.download(ctx, domain)
+		dr.finish(err)
+		// Keep the progress even if the download failed.
+		// Archived advisories are kept when the archive is written.
+		if d.archive == nil {
+			if serr := d.saveState(); serr != nil {
+				err = errors.Join(err, serr)
+			}
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,88 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ProtonMail/gopenpgp/v2/crypto"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+func TestLoadOpenPGPKeys(t *testing.T) {
+	keys := map[string]*crypto.Key{}
+	armored := map[string]string{}
+	for _, name := range []string{"a", "b"} {
+		key, err := crypto.GenerateKey(name, name+"@example.com", "x25519", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := key.GetArmoredPublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[name], armored["/"+name+".asc"] = key, pub
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pub, ok := armored[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(pub))
+	}))
+	defer server.Close()
+
+	base, err := url.Parse(server.URL + "/provider-metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &downloader{eval: util.NewPathEval()}
+
+	// The domains are loaded one after the other.
+	for _, tc := range []struct {
+		name string
+		key  string
+	}{
+		{"first domain", "a"},
+		{"domain without keys", ""},
+		{"second domain", "b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc := map[string]any{}
+			if tc.key != "" {
+				doc["public_openpgp_keys"] = []any{map[string]any{
+					"fingerprint": keys[tc.key].GetFingerprint(),
+					"url":         tc.key + ".asc",
+				}}
+			}
+			if err := d.loadOpenPGPKeys(server.Client(), doc, base); err != nil {
+				t.Fatal(err)
+			}
+			if tc.key == "" {
+				if d.keys != nil {
+					t.Fatalf("got %d keys expected none", d.keys.CountEntities())
+				}
+				return
+			}
+			if d.keys == nil || d.keys.CountEntities() != 1 {
+				t.Fatal("expected exactly one key")
+			}
+			if got := d.keys.GetKeys()[0].GetFingerprint(); got != keys[tc.key].GetFingerprint() {
+				t.Errorf("got key %s expected key %s", got, keys[tc.key].GetFingerprint())
+			}
+		})
+	}
+}
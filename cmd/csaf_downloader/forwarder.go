@@ -11,6 +11,7 @@ package main
 import (
 	"bytes"
 	"crypto/tls"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -28,6 +29,14 @@ import (
 // where advisories get stored which fail forwarding.
 const failedForwardDir = "failed_forward"
 
+// validationStatusExt is the extension of the files storing the
+// validation status of the advisories which failed forwarding.
+const validationStatusExt = ".validation_status"
+
+// failedSinksExt is the extension of the files listing the names
+// of the sinks to which the advisories failed to be forwarded.
+const failedSinksExt = ".sinks"
+
 // validationStatus represents the validation status
 // known to the HTTP endpoint.
 type validationStatus string
@@ -45,12 +54,30 @@ func (vs *validationStatus) update(status validationStatus) {
 	}
 }
 
-// forwarder forwards downloaded advisories to a given
-// HTTP endpoint.
+// forwardedAdvisory is an advisory handed over to the sinks.
+type forwardedAdvisory struct {
+	filename string
+	doc      string
+	status   validationStatus
+	sha256   string
+	sha512   string
+}
+
+// sink is a destination the forwarder delivers advisories to.
+type sink interface {
+	// name returns the name under which the sink is
+	// recorded for the advisories failing forwarding.
+	name() string
+	// put delivers an advisory. An error signals
+	// that the delivery failed.
+	put(adv *forwardedAdvisory) error
+}
+
+// forwarder forwards downloaded advisories to the configured sinks.
 type forwarder struct {
-	cfg    *config
-	cmds   chan func(*forwarder)
-	client util.Client
+	cfg   *config
+	cmds  chan func(*forwarder)
+	sinks []sink
 
 	failed    int
 	succeeded int
@@ -63,11 +90,33 @@ func newForwarder(cfg *config) *forwarder {
 		queue = 1
 	}
 	return &forwarder{
-		cfg:  cfg,
-		cmds: make(chan func(*forwarder), queue),
+		cfg:   cfg,
+		cmds:  make(chan func(*forwarder), queue),
+		sinks: newSinks(cfg),
 	}
 }
 
+// newSinks creates the sinks configured in cfg.
+func newSinks(cfg *config) []sink {
+	var sinks []sink
+	if cfg.ForwardURL != "" {
+		sinks = append(sinks, &httpSink{cfg: cfg})
+	}
+	if cfg.ForwardDir != "" {
+		sinks = append(sinks, &spoolSink{dir: cfg.forwardDir()})
+	}
+	if cfg.ForwardStdout {
+		sinks = append(sinks, newJSONSink(os.Stdout))
+	}
+	if cfg.ForwardCommand != "" {
+		sinks = append(sinks, &execSink{
+			command: cfg.ForwardCommand,
+			timeout: cfg.ForwardCommandTimeout,
+		})
+	}
+	return sinks
+}
+
 // run runs the forwarder. Meant to be used in a Go routine.
 func (f *forwarder) run() {
 	defer slog.Debug("forwarder done")
@@ -82,6 +131,13 @@ func (f *forwarder) close() {
 	close(f.cmds)
 }
 
+// flush waits till all queued advisories are forwarded.
+func (f *forwarder) flush() {
+	done := make(chan struct{})
+	f.cmds <- func(*forwarder) { close(done) }
+	<-done
+}
+
 // log logs the current statistics.
 func (f *forwarder) log() {
 	f.cmds <- func(f *forwarder) {
@@ -91,17 +147,23 @@ func (f *forwarder) log() {
 	}
 }
 
+// httpSink uploads advisories to the configured HTTP endpoint.
+type httpSink struct {
+	cfg    *config
+	client util.Client
+}
+
 // httpClient returns a cached HTTP client used for uploading
 // the advisories to the configured HTTP endpoint.
-func (f *forwarder) httpClient() util.Client {
-	if f.client != nil {
-		return f.client
+func (hs *httpSink) httpClient() util.Client {
+	if hs.client != nil {
+		return hs.client
 	}
 
 	hClient := http.Client{}
 
 	var tlsConfig tls.Config
-	if f.cfg.ForwardInsecure {
+	if hs.cfg.ForwardInsecure {
 		tlsConfig.InsecureSkipVerify = true
 	}
 
@@ -112,23 +174,23 @@ func (f *forwarder) httpClient() util.Client {
 	client := util.Client(&hClient)
 
 	// Add extra headers.
-	if len(f.cfg.ForwardHeader) > 0 {
+	if len(hs.cfg.ForwardHeader) > 0 {
 		client = &util.HeaderClient{
 			Client: client,
-			Header: f.cfg.ForwardHeader,
+			Header: hs.cfg.ForwardHeader,
 		}
 	}
 
 	// Add optional URL logging.
-	if f.cfg.verbose() {
+	if hs.cfg.verbose() {
 		client = &util.LoggingClient{
 			Client: client,
 			Log:    httpLog("forwarder"),
 		}
 	}
 
-	f.client = client
-	return f.client
+	hs.client = client
+	return hs.client
 }
 
 // replaceExt replaces the extension of a given filename.
@@ -138,7 +200,7 @@ func replaceExt(fname, nExt string) string {
 }
 
 // buildRequest creates an HTTP request suited to forward the given advisory.
-func (f *forwarder) buildRequest(
+func (hs *httpSink) buildRequest(
 	filename, doc string,
 	status validationStatus,
 	sha256, sha512 string,
@@ -179,7 +241,7 @@ func (f *forwarder) buildRequest(
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, f.cfg.ForwardURL, body)
+	req, err := http.NewRequest(http.MethodPost, hs.cfg.ForwardURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -188,9 +250,41 @@ func (f *forwarder) buildRequest(
 	return req, nil
 }
 
+// name implements the respective method of the [sink] interface.
+func (*httpSink) name() string { return "http" }
+
+// put implements the respective method of the [sink] interface.
+func (hs *httpSink) put(adv *forwardedAdvisory) error {
+	req, err := hs.buildRequest(
+		adv.filename, adv.doc, adv.status, adv.sha256, adv.sha512)
+	if err != nil {
+		return fmt.Errorf("building forward request failed: %w", err)
+	}
+	res, err := hs.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("sending forward request failed: %w", err)
+	}
+	if res.StatusCode != http.StatusCreated {
+		defer res.Body.Close()
+		msg, err := limitedString(res.Body, 512)
+		if err != nil {
+			return fmt.Errorf("reading forward result failed: %w", err)
+		}
+		return fmt.Errorf("forward endpoint returned status %d: %s",
+			res.StatusCode, msg)
+	}
+	return nil
+}
+
 // storeFailedAdvisory stores an advisory in a special folder
-// in case the forwarding failed.
-func (f *forwarder) storeFailedAdvisory(filename, doc, sha256, sha512 string) error {
+// in case the forwarding failed together with the names of
+// the sinks it failed for.
+func (f *forwarder) storeFailedAdvisory(
+	filename, doc string,
+	status validationStatus,
+	sha256, sha512 string,
+	sinks []string,
+) error {
 	// Create special folder if it does not exist.
 	dir := filepath.Join(f.cfg.Directory, failedForwardDir)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -204,6 +298,7 @@ func (f *forwarder) storeFailedAdvisory(filename, doc, sha256, sha512 string) er
 		{filename, doc},
 		{filename + ".sha256", sha256},
 		{filename + ".sha512", sha512},
+		{filename + validationStatusExt, string(status)},
 	} {
 		if len(x.d) != 0 {
 			path := filepath.Join(dir, x.p)
@@ -212,13 +307,27 @@ func (f *forwarder) storeFailedAdvisory(filename, doc, sha256, sha512 string) er
 			}
 		}
 	}
-	return nil
+	return writeFailedSinks(dir, filename, sinks)
+}
+
+// writeFailedSinks writes the names of the sinks
+// an advisory failed to be forwarded to.
+func writeFailedSinks(dir, filename string, sinks []string) error {
+	path := filepath.Join(dir, filename+failedSinksExt)
+	return os.WriteFile(path, []byte(strings.Join(sinks, "\n")+"\n"), 0644)
 }
 
 // storeFailed is a logging wrapper around storeFailedAdvisory.
-func (f *forwarder) storeFailed(filename, doc, sha256, sha512 string) {
+func (f *forwarder) storeFailed(
+	filename, doc string,
+	status validationStatus,
+	sha256, sha512 string,
+	sinks []string,
+) {
 	f.failed++
-	if err := f.storeFailedAdvisory(filename, doc, sha256, sha512); err != nil {
+	if err := f.storeFailedAdvisory(
+		filename, doc, status, sha256, sha512, sinks,
+	); err != nil {
 		slog.Error("Storing advisory failed forwarding failed",
 			"error", err)
 	}
@@ -240,44 +349,63 @@ func limitedString(r io.Reader, max int) (string, error) {
 // forward sends a given document with filename, status and
 // checksums to the forwarder. This is async to the degree
 // till the configured queue size is filled.
+// If done is not nil it is called with the result of the forwarding.
 func (f *forwarder) forward(
 	filename, doc string,
 	status validationStatus,
 	sha256, sha512 string,
+	done func(forwarded bool),
 ) {
 	// Run this in the main loop of the forwarder.
 	f.cmds <- func(f *forwarder) {
-		req, err := f.buildRequest(filename, doc, status, sha256, sha512)
-		if err != nil {
-			slog.Error("building forward Request failed",
-				"error", err)
-			f.storeFailed(filename, doc, sha256, sha512)
-			return
+		forwarded := f.send(filename, doc, status, sha256, sha512)
+		if done != nil {
+			done(forwarded)
 		}
-		res, err := f.httpClient().Do(req)
-		if err != nil {
-			slog.Error("sending forward request failed",
-				"error", err)
-			f.storeFailed(filename, doc, sha256, sha512)
-			return
+	}
+}
+
+// deliver hands an advisory over to the sinks. If only is not nil
+// the advisory is only handed over to the sinks named in it.
+// Returns the names of the sinks the delivery failed for.
+func (f *forwarder) deliver(adv *forwardedAdvisory, only util.Set[string]) []string {
+	var failed []string
+	for _, s := range f.sinks {
+		if only != nil && !only.Contains(s.name()) {
+			continue
 		}
-		if res.StatusCode != http.StatusCreated {
-			defer res.Body.Close()
-			if msg, err := limitedString(res.Body, 512); err != nil {
-				slog.Error("reading forward result failed",
-					"error", err)
-			} else {
-				slog.Error("forwarding failed",
-					"filename", filename,
-					"body", msg,
-					"status_code", res.StatusCode)
-			}
-			f.storeFailed(filename, doc, sha256, sha512)
-		} else {
-			f.succeeded++
-			slog.Debug(
-				"forwarding succeeded",
-				"filename", filename)
+		if err := s.put(adv); err != nil {
+			slog.Error("forwarding failed",
+				"filename", adv.filename,
+				"sink", s.name(),
+				"error", err)
+			failed = append(failed, s.name())
 		}
 	}
+	return failed
+}
+
+// send hands a given document over to all the sinks.
+// Returns true if the forwarding succeeded for all of them.
+func (f *forwarder) send(
+	filename, doc string,
+	status validationStatus,
+	sha256, sha512 string,
+) bool {
+	adv := &forwardedAdvisory{
+		filename: filename,
+		doc:      doc,
+		status:   status,
+		sha256:   sha256,
+		sha512:   sha512,
+	}
+	if failed := f.deliver(adv, nil); len(failed) > 0 {
+		f.storeFailed(filename, doc, status, sha256, sha512, failed)
+		return false
+	}
+	f.succeeded++
+	slog.Debug(
+		"forwarding succeeded",
+		"filename", filename)
+	return true
 }
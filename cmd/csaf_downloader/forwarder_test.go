@@ -103,8 +103,8 @@ func TestForwarderHTTPClient(t *testing.T) {
 		},
 		LogLevel: &options.LogLevel{Level: slog.LevelDebug},
 	}
-	fw := newForwarder(cfg)
-	if c1, c2 := fw.httpClient(), fw.httpClient(); c1 != c2 {
+	hs := &httpSink{cfg: cfg}
+	if c1, c2 := hs.httpClient(), hs.httpClient(); c1 != c2 {
 		t.Fatal("expected to return same client twice")
 	}
 }
@@ -128,9 +128,9 @@ func TestForwarderBuildRequest(t *testing.T) {
 	cfg := &config{
 		ForwardURL: "https://example.com",
 	}
-	fw := newForwarder(cfg)
+	hs := &httpSink{cfg: cfg}
 
-	req, err := fw.buildRequest(
+	req, err := hs.buildRequest(
 		"test.json", "{}",
 		invalidValidationStatus,
 		"256",
@@ -208,7 +208,7 @@ func TestForwarderBuildRequest(t *testing.T) {
 	// Bad case ...
 	cfg.ForwardURL = "%"
 
-	if _, err := fw.buildRequest(
+	if _, err := hs.buildRequest(
 		"test.json", "{}",
 		invalidValidationStatus,
 		"256",
@@ -257,7 +257,7 @@ func TestStoreFailedAdvisory(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := fw.storeFailedAdvisory("advisory.json", "{}", "256", "512"); err == nil {
+	if err := fw.storeFailedAdvisory("advisory.json", "{}", invalidValidationStatus, "256", "512", []string{"http"}); err == nil {
 		t.Fatal("if the destination exists as a file an error should occur")
 	}
 
@@ -265,7 +265,7 @@ func TestStoreFailedAdvisory(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := fw.storeFailedAdvisory("advisory.json", "{}", "256", "512"); err != nil {
+	if err := fw.storeFailedAdvisory("advisory.json", "{}", invalidValidationStatus, "256", "512", []string{"http"}); err != nil {
 		t.Fatal(err)
 	}
 
@@ -276,7 +276,7 @@ func TestStoreFailedAdvisory(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if err := fw.storeFailedAdvisory("advisory.json", "{}", "256", "512"); err == nil {
+	if err := fw.storeFailedAdvisory("advisory.json", "{}", invalidValidationStatus, "256", "512", []string{"http"}); err == nil {
 		t.Fatal("expected to fail with an error")
 	}
 
@@ -306,7 +306,7 @@ func TestStoredFailed(t *testing.T) {
 	fw := newForwarder(cfg)
 
 	// An empty filename should lead to an error.
-	fw.storeFailed("", "{}", "256", "512")
+	fw.storeFailed("", "{}", invalidValidationStatus, "256", "512", []string{"http"})
 
 	if fw.failed != 1 {
 		t.Fatalf("got %d expected 1", fw.failed)
@@ -393,7 +393,7 @@ func TestForwarderForward(t *testing.T) {
 	fw := newForwarder(cfg)
 
 	// Use the fact that http client is cached.
-	fw.client = &fakeClient{}
+	fw.sinks[0].(*httpSink).client = &fakeClient{}
 
 	done := make(chan struct{})
 
@@ -408,7 +408,8 @@ func TestForwarderForward(t *testing.T) {
 			"test.json", "{}",
 			invalidValidationStatus,
 			"256",
-			"512")
+			"512",
+			nil)
 	}
 
 	// Make buildRequest fail.
@@ -422,7 +423,8 @@ func TestForwarderForward(t *testing.T) {
 		"test.json", "{}",
 		invalidValidationStatus,
 		"256",
-		"512")
+		"512",
+		nil)
 
 	fw.close()
 
@@ -13,6 +13,7 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"syscall"
 
 	"golang.org/x/exp/slog"
 
@@ -28,10 +29,10 @@ func run(cfg *config, domains []string) error {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	if cfg.ForwardURL != "" {
+	if cfg.forwarding() {
 		f := newForwarder(cfg)
 		go f.run()
 		defer func() {
@@ -41,6 +42,13 @@ func run(cfg *config, domains []string) error {
 		d.forwarder = f
 	}
 
+	if domains, err = d.domains(domains); err != nil {
+		return err
+	}
+
+	if cfg.Watch {
+		return d.watch(ctx, domains)
+	}
 	return d.run(ctx, domains)
 }
 
@@ -50,7 +58,12 @@ func main() {
 	options.ErrorCheck(err)
 	options.ErrorCheck(cfg.prepare())
 
-	if len(domains) == 0 {
+	if cfg.ReplayForwards {
+		options.ErrorCheck(replayFailedForwards(cfg))
+		return
+	}
+
+	if len(domains) == 0 && cfg.Aggregator == "" {
 		slog.Warn("No domains given.")
 		return
 	}
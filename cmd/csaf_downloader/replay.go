@@ -0,0 +1,183 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// readOptional reads a file which may not exist.
+func readOptional(fname string) (string, error) {
+	data, err := os.ReadFile(fname)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	return string(data), err
+}
+
+// loadFailedAdvisory loads an advisory stored by storeFailedAdvisory
+// together with the names of the sinks it failed to be forwarded to.
+// The names are nil if the advisory was stored by versions not
+// recording them.
+func loadFailedAdvisory(dir, filename string) (*forwardedAdvisory, util.Set[string], error) {
+	path := filepath.Join(dir, filename)
+	doc, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	adv := &forwardedAdvisory{filename: filename, doc: string(doc)}
+	if adv.sha256, err = readOptional(path + ".sha256"); err != nil {
+		return nil, nil, err
+	}
+	if adv.sha512, err = readOptional(path + ".sha512"); err != nil {
+		return nil, nil, err
+	}
+	status, err := readOptional(path + validationStatusExt)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch vs := validationStatus(strings.TrimSpace(status)); vs {
+	case validValidationStatus, invalidValidationStatus:
+		adv.status = vs
+	default:
+		// Stored by versions not recording the status.
+		adv.status = notValidatedValidationStatus
+	}
+	names, err := readOptional(path + failedSinksExt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sinks util.Set[string]
+	if names != "" {
+		sinks = util.Set[string]{}
+		for _, name := range strings.Fields(names) {
+			sinks.Add(name)
+		}
+	}
+	return adv, sinks, nil
+}
+
+// removeFailedAdvisory removes an advisory stored by storeFailedAdvisory.
+func removeFailedAdvisory(dir, filename string) error {
+	path := filepath.Join(dir, filename)
+	for _, fname := range []string{
+		path,
+		path + ".sha256",
+		path + ".sha512",
+		path + validationStatusExt,
+		path + failedSinksExt,
+	} {
+		if err := os.Remove(fname); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// replay forwards the advisories stored in the failed forward folder
+// again. Each advisory is only forwarded to the sinks it failed for.
+// The successfully forwarded ones are removed from the folder, for
+// the others the sinks still failing are recorded.
+// Returns the filenames of the advisories remaining in the folder.
+func (f *forwarder) replay() ([]string, error) {
+	dir := filepath.Join(f.cfg.Directory, failedForwardDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var remaining []string
+	for _, entry := range entries {
+		filename := entry.Name()
+		if !entry.Type().IsRegular() || filepath.Ext(filename) != ".json" {
+			continue
+		}
+		adv, only, err := loadFailedAdvisory(dir, filename)
+		if err != nil {
+			slog.Error("Loading failed advisory failed",
+				"filename", filename,
+				"error", err)
+			remaining = append(remaining, filename)
+			continue
+		}
+		failed := f.deliver(adv, only)
+		// Sinks which are not configured any more cannot be replayed to.
+		for _, name := range f.unconfigured(only) {
+			slog.Warn("Sink to replay to is not configured",
+				"filename", filename,
+				"sink", name)
+			failed = append(failed, name)
+		}
+		if len(failed) > 0 {
+			f.failed++
+			remaining = append(remaining, filename)
+			if err := writeFailedSinks(dir, filename, failed); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		f.succeeded++
+		slog.Debug("replaying succeeded", "filename", filename)
+		if err := removeFailedAdvisory(dir, filename); err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(remaining)
+	return remaining, nil
+}
+
+// unconfigured returns the sorted names in names
+// which belong to none of the configured sinks.
+func (f *forwarder) unconfigured(names util.Set[string]) []string {
+	if len(names) == 0 {
+		return nil
+	}
+	configured := util.Set[string]{}
+	for _, s := range f.sinks {
+		configured.Add(s.name())
+	}
+	missing := names.Difference(configured).Keys()
+	sort.Strings(missing)
+	return missing
+}
+
+// replayFailedForwards replays the advisories which failed forwarding.
+func replayFailedForwards(cfg *config) error {
+	if !cfg.forwarding() {
+		return errors.New("replaying failed forwards needs a forward target")
+	}
+	f := newForwarder(cfg)
+	remaining, err := f.replay()
+	if err != nil {
+		return err
+	}
+	slog.Info("Replay statistics",
+		"succeeded", f.succeeded,
+		"failed", f.failed,
+		"remaining", len(remaining))
+	if len(remaining) > 0 {
+		return fmt.Errorf("%d advisories remain in %q: %s",
+			len(remaining),
+			filepath.Join(cfg.Directory, failedForwardDir),
+			strings.Join(remaining, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/slog"
+)
+
+// recordingSink records the advisories put into it.
+// It fails for the advisories named in fail.
+type recordingSink struct {
+	sinkName string
+	fail     map[string]bool
+	got      map[string]*forwardedAdvisory
+}
+
+func newRecordingSink(name string, fail ...string) *recordingSink {
+	rs := &recordingSink{
+		sinkName: name,
+		fail:     map[string]bool{},
+		got:      map[string]*forwardedAdvisory{},
+	}
+	for _, filename := range fail {
+		rs.fail[filename] = true
+	}
+	return rs
+}
+
+func (rs *recordingSink) name() string { return rs.sinkName }
+
+func (rs *recordingSink) put(adv *forwardedAdvisory) error {
+	if rs.fail[adv.filename] {
+		return errors.New("does not work")
+	}
+	rs.got[adv.filename] = adv
+	return nil
+}
+
+func TestForwarderReplay(t *testing.T) {
+	orig := slog.Default()
+	defer slog.SetDefault(orig)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	dir := t.TempDir()
+	cfg := &config{Directory: dir}
+	fw := newForwarder(cfg)
+
+	// Nothing stored yet.
+	if remaining, err := fw.replay(); err != nil || len(remaining) != 0 {
+		t.Fatalf("unexpected result of empty replay: %v %v", remaining, err)
+	}
+
+	for _, x := range []struct {
+		filename string
+		status   validationStatus
+		sinks    []string
+	}{
+		{"a.json", validValidationStatus, []string{"one"}},
+		{"b.json", invalidValidationStatus, []string{"one", "two"}},
+		{"c.json", validValidationStatus, []string{"two"}},
+		{"d.json", validValidationStatus, []string{"gone"}},
+	} {
+		if err := fw.storeFailedAdvisory(
+			x.filename, "{}", x.status, "256", "", x.sinks,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	failedDir := filepath.Join(dir, failedForwardDir)
+	// Simulate an advisory stored by older versions
+	// without a validation status and failed sinks.
+	for _, ext := range []string{validationStatusExt, failedSinksExt} {
+		if err := os.Remove(filepath.Join(failedDir, "c.json"+ext)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	one := newRecordingSink("one", "b.json")
+	two := newRecordingSink("two")
+	fw.sinks = []sink{one, two}
+
+	remaining, err := fw.replay()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"b.json", "d.json"}; !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("got remaining %v expected %v", remaining, expected)
+	}
+	if fw.succeeded != 2 || fw.failed != 2 {
+		t.Errorf("got %d succeeded and %d failed expected 2 and 2",
+			fw.succeeded, fw.failed)
+	}
+
+	for _, x := range []struct {
+		rs  *recordingSink
+		got []string
+	}{
+		{one, []string{"a.json", "c.json"}},
+		{two, []string{"b.json", "c.json"}},
+	} {
+		var got []string
+		for filename, adv := range x.rs.got {
+			got = append(got, filename)
+			status := validValidationStatus
+			switch filename {
+			case "b.json":
+				status = invalidValidationStatus
+			case "c.json":
+				status = notValidatedValidationStatus
+			}
+			if adv.status != status || adv.doc != "{}" ||
+				adv.sha256 != "256" || adv.sha512 != "" {
+				t.Errorf("%s: unexpected replayed advisory %+v", filename, adv)
+			}
+		}
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, x.got) {
+			t.Errorf("sink %s: got %v expected %v", x.rs.name(), got, x.got)
+		}
+	}
+
+	entries, err := os.ReadDir(failedDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	expected := []string{
+		"b.json", "b.json.sha256", "b.json" + failedSinksExt, "b.json" + validationStatusExt,
+		"d.json", "d.json.sha256", "d.json" + failedSinksExt, "d.json" + validationStatusExt,
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got files %v expected %v", names, expected)
+	}
+
+	// Only the sinks still failing are recorded.
+	for filename, sinks := range map[string]string{
+		"b.json": "one\n",
+		"d.json": "gone\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(failedDir, filename+failedSinksExt))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != sinks {
+			t.Errorf("%s: got failed sinks %q expected %q", filename, data, sinks)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// outcome is the result of processing an advisory.
+type outcome string
+
+const (
+	outcomeStored          = outcome("stored")
+	outcomeDownloaded      = outcome("downloaded")
+	outcomeNotModified     = outcome("not_modified")
+	outcomeIgnored         = outcome("ignored")
+	outcomeNotSelected     = outcome("not_selected")
+	outcomeDownloadFailed  = outcome("download_failed")
+	outcomeFilenameFailed  = outcome("filename_failed")
+	outcomeSHA256Failed    = outcome("sha256_failed")
+	outcomeSHA512Failed    = outcome("sha512_failed")
+	outcomeSignatureFailed = outcome("signature_failed")
+	outcomeSchemaFailed    = outcome("schema_failed")
+	outcomeRemoteFailed    = outcome("remote_failed")
+	outcomeStoreFailed     = outcome("store_failed")
+	outcomeForwarded       = outcome("forwarded")
+	outcomeForwardFailed   = outcome("forward_failed")
+)
+
+// runReport is the machine-readable report of a download run.
+// A nil report records nothing.
+type runReport struct {
+	mu       sync.Mutex
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Duration float64         `json:"duration_seconds"`
+	Totals   map[outcome]int `json:"totals"`
+	Domains  []*domainReport `json:"domains"`
+	domains  map[string]*domainReport
+}
+
+// domainReport is the report of the downloads from a single domain.
+type domainReport struct {
+	report     *runReport
+	Domain     string            `json:"domain"`
+	Start      time.Time         `json:"start"`
+	End        time.Time         `json:"end"`
+	Duration   float64           `json:"duration_seconds"`
+	Error      string            `json:"error,omitempty"`
+	Totals     map[outcome]int   `json:"totals"`
+	Advisories []*advisoryReport `json:"advisories"`
+}
+
+// advisoryReport is the report of a single advisory.
+type advisoryReport struct {
+	report *runReport
+	URL    string `json:"url"`
+	// Outcome is the final result. If the advisory was not
+	// stored it is the first failure.
+	Outcome outcome `json:"outcome"`
+	// Failures are all the failed checks. In unsafe validation
+	// mode an advisory may be stored nevertheless.
+	Failures []outcome `json:"failures,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	// Forward is the result of forwarding the advisory.
+	Forward  outcome `json:"forward,omitempty"`
+	Duration float64 `json:"duration_seconds"`
+	start    time.Time
+}
+
+// newRunReport starts a new report.
+func newRunReport() *runReport {
+	return &runReport{
+		Start:   time.Now().UTC(),
+		domains: map[string]*domainReport{},
+	}
+}
+
+// domain starts the report of the given domain.
+func (r *runReport) domain(name string) *domainReport {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dr := &domainReport{
+		report: r,
+		Domain: name,
+		Start:  time.Now().UTC(),
+	}
+	r.Domains = append(r.Domains, dr)
+	r.domains[name] = dr
+	return dr
+}
+
+// advisory starts the report of an advisory from the given domain.
+func (r *runReport) advisory(domain, url string) *advisoryReport {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ar := &advisoryReport{
+		report: r,
+		URL:    url,
+		start:  time.Now(),
+	}
+	if dr := r.domains[domain]; dr != nil {
+		dr.Advisories = append(dr.Advisories, ar)
+	}
+	return ar
+}
+
+// save ends the report and writes it to the given file.
+func (r *runReport) save(fname string) error {
+	r.finish()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return writeJSONFile(fname, r)
+}
+
+// finish ends the report and sums up the totals.
+func (r *runReport) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.End = time.Now().UTC()
+	r.Duration = r.End.Sub(r.Start).Seconds()
+	r.Totals = map[outcome]int{}
+	for _, dr := range r.Domains {
+		dr.Totals = map[outcome]int{}
+		for _, ar := range dr.Advisories {
+			for _, o := range []outcome{ar.Outcome, ar.Forward} {
+				if o != "" {
+					dr.Totals[o]++
+					r.Totals[o]++
+				}
+			}
+		}
+	}
+}
+
+// finish ends the report of the domain.
+func (dr *domainReport) finish(err error) {
+	if dr == nil {
+		return
+	}
+	dr.report.mu.Lock()
+	defer dr.report.mu.Unlock()
+	dr.End = time.Now().UTC()
+	dr.Duration = dr.End.Sub(dr.Start).Seconds()
+	if err != nil {
+		dr.Error = err.Error()
+	}
+}
+
+// done records the time needed to process the advisory.
+// ar.report.mu has to be locked.
+func (ar *advisoryReport) done() {
+	ar.Duration = time.Since(ar.start).Seconds()
+}
+
+// fail records a failure.
+func (ar *advisoryReport) fail(o outcome) {
+	if ar == nil {
+		return
+	}
+	ar.report.mu.Lock()
+	defer ar.report.mu.Unlock()
+	ar.Failures = append(ar.Failures, o)
+	if ar.Outcome == "" {
+		ar.Outcome = o
+	}
+	ar.done()
+}
+
+// succeed records the final result of a processed advisory
+// which is stored at path.
+func (ar *advisoryReport) succeed(o outcome, path string) {
+	if ar == nil {
+		return
+	}
+	ar.report.mu.Lock()
+	defer ar.report.mu.Unlock()
+	ar.Outcome = o
+	ar.Path = path
+	ar.done()
+}
+
+// forwarded records the result of forwarding the advisory.
+func (ar *advisoryReport) forwarded(ok bool) {
+	if ar == nil {
+		return
+	}
+	ar.report.mu.Lock()
+	defer ar.report.mu.Unlock()
+	if ok {
+		ar.Forward = outcomeForwarded
+	} else {
+		ar.Forward = outcomeForwardFailed
+	}
+}
@@ -0,0 +1,107 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunReport(t *testing.T) {
+	r := newRunReport()
+	dr := r.domain("example.com")
+
+	stored := r.advisory("example.com", "https://example.com/a.json")
+	stored.succeed(outcomeStored, "white/2023/a.json")
+	stored.forwarded(true)
+
+	unsafe := r.advisory("example.com", "https://example.com/b.json")
+	unsafe.fail(outcomeSHA256Failed)
+	unsafe.fail(outcomeSchemaFailed)
+	unsafe.succeed(outcomeStored, "failed_validation/white/2023/b.json")
+	unsafe.forwarded(false)
+
+	failed := r.advisory("example.com", "https://example.com/c.json")
+	failed.fail(outcomeSignatureFailed)
+	failed.fail(outcomeRemoteFailed)
+
+	dr.finish(errors.New("changes.csv not found"))
+
+	fname := filepath.Join(t.TempDir(), "report.json")
+	if err := r.save(fname); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(fname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Totals  map[outcome]int `json:"totals"`
+		Domains []struct {
+			Domain     string          `json:"domain"`
+			Error      string          `json:"error"`
+			Totals     map[outcome]int `json:"totals"`
+			Advisories []struct {
+				URL      string    `json:"url"`
+				Outcome  outcome   `json:"outcome"`
+				Failures []outcome `json:"failures"`
+				Forward  outcome   `json:"forward"`
+			} `json:"advisories"`
+		} `json:"domains"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	totals := map[outcome]int{
+		outcomeStored:          2,
+		outcomeSignatureFailed: 1,
+		outcomeForwarded:       1,
+		outcomeForwardFailed:   1,
+	}
+	if !reflect.DeepEqual(got.Totals, totals) {
+		t.Errorf("got totals %v expected %v", got.Totals, totals)
+	}
+	if len(got.Domains) != 1 {
+		t.Fatalf("got %d domains expected 1", len(got.Domains))
+	}
+	domain := got.Domains[0]
+	if domain.Domain != "example.com" || domain.Error != "changes.csv not found" {
+		t.Errorf("unexpected domain %q with error %q", domain.Domain, domain.Error)
+	}
+	if !reflect.DeepEqual(domain.Totals, totals) {
+		t.Errorf("got domain totals %v expected %v", domain.Totals, totals)
+	}
+	if len(domain.Advisories) != 3 {
+		t.Fatalf("got %d advisories expected 3", len(domain.Advisories))
+	}
+	b := domain.Advisories[1]
+	if b.Outcome != outcomeStored || b.Forward != outcomeForwardFailed ||
+		!reflect.DeepEqual(b.Failures, []outcome{outcomeSHA256Failed, outcomeSchemaFailed}) {
+		t.Errorf("unexpected advisory report %+v", b)
+	}
+	if c := domain.Advisories[2]; c.Outcome != outcomeSignatureFailed {
+		t.Errorf("got outcome %q expected %q", c.Outcome, outcomeSignatureFailed)
+	}
+}
+
+func TestNilRunReport(t *testing.T) {
+	var r *runReport
+	dr := r.domain("example.com")
+	ar := r.advisory("example.com", "https://example.com/a.json")
+	ar.fail(outcomeDownloadFailed)
+	ar.succeed(outcomeStored, "a.json")
+	ar.forwarded(true)
+	dr.finish(nil)
+}
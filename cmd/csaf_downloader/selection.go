@@ -0,0 +1,195 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Intevation/gval"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// selection selects advisories by their content.
+// All the configured criteria have to match. A criterion
+// with several values matches if one of them does.
+type selection struct {
+	tlps       []string
+	statuses   []string
+	categories []string
+	cves       []string
+	products   []string
+	minCVSS    *float64
+
+	tlpExpr      gval.Evaluable
+	statusExpr   gval.Evaluable
+	categoryExpr gval.Evaluable
+	cveExpr      gval.Evaluable
+	productExprs []gval.Evaluable
+	scoreExprs   []gval.Evaluable
+}
+
+// newSelection creates the selection configured in cfg.
+// Returns nil if no criteria are configured.
+func newSelection(cfg *config) (*selection, error) {
+	if len(cfg.SelectTLP) == 0 &&
+		len(cfg.SelectStatus) == 0 &&
+		len(cfg.SelectCategory) == 0 &&
+		len(cfg.SelectCVE) == 0 &&
+		len(cfg.SelectProduct) == 0 &&
+		cfg.SelectMinCVSS == nil {
+		return nil, nil
+	}
+
+	s := &selection{
+		tlps:       cfg.SelectTLP,
+		statuses:   cfg.SelectStatus,
+		categories: cfg.SelectCategory,
+		cves:       cfg.SelectCVE,
+		minCVSS:    cfg.SelectMinCVSS,
+	}
+	for _, prefix := range cfg.SelectProduct {
+		s.products = append(s.products, strings.ToLower(prefix))
+	}
+
+	// The expressions are compiled here as the workers
+	// evaluate them concurrently.
+	pe := util.NewPathEval()
+	var err error
+	compile := func(expr string) gval.Evaluable {
+		if err != nil {
+			return nil
+		}
+		var eval gval.Evaluable
+		eval, err = pe.Compile(expr)
+		return eval
+	}
+	s.tlpExpr = compile(`$.document.distribution.tlp.label`)
+	s.statusExpr = compile(`$.document.tracking.status`)
+	s.categoryExpr = compile(`$.document.category`)
+	s.cveExpr = compile(`$.vulnerabilities[*].cve`)
+	s.productExprs = []gval.Evaluable{
+		compile(`$.product_tree..purl`),
+		compile(`$.product_tree..purls[*]`),
+		compile(`$.product_tree..cpe`),
+	}
+	s.scoreExprs = []gval.Evaluable{
+		compile(`$.vulnerabilities[*].scores[*].cvss_v4.baseScore`),
+		compile(`$.vulnerabilities[*].scores[*].cvss_v3.baseScore`),
+		compile(`$.vulnerabilities[*].scores[*].cvss_v2.baseScore`),
+		// CSAF 2.1
+		compile(`$.vulnerabilities[*].metrics[*].content.cvss_v4.baseScore`),
+		compile(`$.vulnerabilities[*].metrics[*].content.cvss_v3.baseScore`),
+		compile(`$.vulnerabilities[*].metrics[*].content.cvss_v2.baseScore`),
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// values returns the values found by an expression in a document.
+// Missing values are not considered an error.
+func values(eval gval.Evaluable, doc any) []any {
+	v, err := eval(context.Background(), doc)
+	if err != nil || v == nil {
+		return nil
+	}
+	if list, ok := v.([]any); ok {
+		return list
+	}
+	return []any{v}
+}
+
+// strs returns the strings found by an expression in a document.
+func strs(eval gval.Evaluable, doc any) []string {
+	var result []string
+	for _, v := range values(eval, doc) {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// containsFold checks if one of the candidates is in the
+// list of accepted values ignoring the case.
+func containsFold(accepted, candidates []string) bool {
+	for _, c := range candidates {
+		for _, a := range accepted {
+			if strings.EqualFold(a, c) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// selects returns true if the advisory is selected. label is the TLP
+// label of the feed used if the advisory does not have one.
+func (s *selection) selects(doc any, label csaf.TLPLabel) bool {
+	if s == nil {
+		return true
+	}
+	if len(s.tlps) > 0 {
+		tlps := strs(s.tlpExpr, doc)
+		if len(tlps) == 0 {
+			tlps = []string{string(label)}
+		}
+		if !containsFold(s.tlps, tlps) {
+			return false
+		}
+	}
+	if len(s.statuses) > 0 && !containsFold(s.statuses, strs(s.statusExpr, doc)) {
+		return false
+	}
+	if len(s.categories) > 0 && !containsFold(s.categories, strs(s.categoryExpr, doc)) {
+		return false
+	}
+	if len(s.cves) > 0 && !containsFold(s.cves, strs(s.cveExpr, doc)) {
+		return false
+	}
+	if len(s.products) > 0 && !s.hasProduct(doc) {
+		return false
+	}
+	if s.minCVSS != nil && !s.hasScore(doc) {
+		return false
+	}
+	return true
+}
+
+// hasProduct checks if a PURL or CPE in the product tree
+// starts with one of the configured prefixes.
+func (s *selection) hasProduct(doc any) bool {
+	for _, eval := range s.productExprs {
+		for _, id := range strs(eval, doc) {
+			id = strings.ToLower(id)
+			for _, prefix := range s.products {
+				if strings.HasPrefix(id, prefix) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasScore checks if a CVSS base score reaches the configured minimum.
+func (s *selection) hasScore(doc any) bool {
+	for _, eval := range s.scoreExprs {
+		for _, v := range values(eval, doc) {
+			if score, ok := v.(float64); ok && score >= *s.minCVSS {
+				return true
+			}
+		}
+	}
+	return false
+}
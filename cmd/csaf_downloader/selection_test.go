@@ -0,0 +1,163 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+)
+
+const selectionAdvisory = `{
+  "document": {
+    "category": "csaf_security_advisory",
+    "distribution": {"tlp": {"label": "GREEN"}},
+    "tracking": {"status": "final"}
+  },
+  "product_tree": {
+    "branches": [{
+      "branches": [{
+        "product": {
+          "product_id": "P1",
+          "product_identification_helper": {
+            "purl": "pkg:golang/github.com/example/lib@v1.2.3"
+          }
+        }
+      }]
+    }],
+    "full_product_names": [{
+      "product_id": "P2",
+      "product_identification_helper": {
+        "cpe": "cpe:2.3:a:example:server:2.0:*:*:*:*:*:*:*"
+      }
+    }]
+  },
+  "vulnerabilities": [
+    {"cve": "CVE-2023-0001", "scores": [{"cvss_v3": {"baseScore": 5.3}}]},
+    {"cve": "CVE-2023-0002", "scores": [{"cvss_v2": {"baseScore": 7.5}}]}
+  ]
+}`
+
+const selectionAdvisory21 = `{
+  "document": {
+    "category": "csaf_security_advisory",
+    "csaf_version": "2.1"
+  },
+  "product_tree": {
+    "full_product_names": [{
+      "product_id": "P1",
+      "product_identification_helper": {
+        "purls": [
+          "pkg:golang/github.com/example/lib@v1.2.3",
+          "pkg:deb/debian/example-lib@1.2.3"
+        ]
+      }
+    }]
+  },
+  "vulnerabilities": [
+    {"cve": "CVE-2024-0001", "metrics": [{"content": {"cvss_v3": {"baseScore": 5.3}}}]},
+    {"cve": "CVE-2024-0002", "metrics": [{"content": {"cvss_v4": {"baseScore": 8.7}}}]}
+  ]
+}`
+
+func TestSelection(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(selectionAdvisory), &doc); err != nil {
+		t.Fatal(err)
+	}
+	score := func(s float64) *float64 { return &s }
+
+	for _, tc := range []struct {
+		name     string
+		cfg      config
+		selected bool
+	}{
+		{"no criteria", config{}, true},
+		{"tlp", config{SelectTLP: []string{"white", "green"}}, true},
+		{"other tlp", config{SelectTLP: []string{"RED"}}, false},
+		{"status", config{SelectStatus: []string{"final"}}, true},
+		{"other status", config{SelectStatus: []string{"interim"}}, false},
+		{"category", config{SelectCategory: []string{"csaf_security_advisory"}}, true},
+		{"other category", config{SelectCategory: []string{"csaf_vex"}}, false},
+		{"cve", config{SelectCVE: []string{"cve-2023-0002"}}, true},
+		{"other cve", config{SelectCVE: []string{"CVE-2023-0003"}}, false},
+		{"purl", config{SelectProduct: []string{"pkg:golang/github.com/example/"}}, true},
+		{"cpe", config{SelectProduct: []string{"cpe:2.3:a:Example:server"}}, true},
+		{"other product", config{SelectProduct: []string{"pkg:npm/"}}, false},
+		{"cvss v2", config{SelectMinCVSS: score(7.5)}, true},
+		{"cvss too low", config{SelectMinCVSS: score(8)}, false},
+		{"combined", config{
+			SelectTLP:     []string{"GREEN"},
+			SelectCVE:     []string{"CVE-2023-0001"},
+			SelectMinCVSS: score(5),
+		}, true},
+		{"combined mismatch", config{
+			SelectTLP:    []string{"GREEN"},
+			SelectStatus: []string{"draft"},
+		}, false},
+	} {
+		s, err := newSelection(&tc.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := s.selects(doc, csaf.TLPLabelWhite); got != tc.selected {
+			t.Errorf("%s: got %t expected %t", tc.name, got, tc.selected)
+		}
+	}
+
+	// Fall back to the TLP label of the feed.
+	s, err := newSelection(&config{SelectTLP: []string{"WHITE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.selects(map[string]any{}, csaf.TLPLabelWhite) {
+		t.Error("advisory without TLP label not selected by feed label")
+	}
+	if s.selects(map[string]any{}, csaf.TLPLabelRed) {
+		t.Error("advisory without TLP label selected despite feed label")
+	}
+}
+
+func TestSelectionCSAF21(t *testing.T) {
+	var doc21, doc20 any
+	if err := json.Unmarshal([]byte(selectionAdvisory21), &doc21); err != nil {
+		t.Fatal(err)
+	}
+	// A CSAF 2.0 advisory only scored with CVSS v4.
+	if err := json.Unmarshal([]byte(`{
+  "vulnerabilities": [{"scores": [{"cvss_v4": {"baseScore": 9.3}}]}]
+}`), &doc20); err != nil {
+		t.Fatal(err)
+	}
+	score := func(s float64) *float64 { return &s }
+
+	for _, tc := range []struct {
+		name     string
+		doc      any
+		cfg      config
+		selected bool
+	}{
+		{"purls", doc21, config{SelectProduct: []string{"pkg:deb/debian/"}}, true},
+		{"other product", doc21, config{SelectProduct: []string{"pkg:npm/"}}, false},
+		{"metrics cvss v3", doc21, config{SelectMinCVSS: score(5)}, true},
+		{"metrics cvss v4", doc21, config{SelectMinCVSS: score(8.5)}, true},
+		{"metrics too low", doc21, config{SelectMinCVSS: score(9)}, false},
+		{"scores cvss v4", doc20, config{SelectMinCVSS: score(9)}, true},
+		{"scores too low", doc20, config{SelectMinCVSS: score(9.5)}, false},
+	} {
+		s, err := newSelection(&tc.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if got := s.selects(tc.doc, csaf.TLPLabelWhite); got != tc.selected {
+			t.Errorf("%s: got %t expected %t", tc.name, got, tc.selected)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// spoolTmpDir is the sub folder of the spool directory
+	// in which the advisories are prepared.
+	spoolTmpDir = "tmp"
+	// spoolNewDir is the sub folder of the spool directory
+	// into which the complete advisories are moved.
+	spoolNewDir = "new"
+	// spoolStatusFile is the name of the file containing
+	// the validation status of a spooled advisory.
+	spoolStatusFile = "validation_status"
+)
+
+// spoolSink writes the advisories into a spool directory.
+// Each advisory is prepared in its own folder below 'tmp'
+// which is then renamed into 'new' as a whole. The names
+// of these folders sort in the order of forwarding.
+type spoolSink struct {
+	dir string
+	seq uint64
+}
+
+// name implements the respective method of the [sink] interface.
+func (*spoolSink) name() string { return "spool" }
+
+// put implements the respective method of the [sink] interface.
+func (ss *spoolSink) put(adv *forwardedAdvisory) error {
+	ss.seq++
+	name := fmt.Sprintf("%s-%06d",
+		time.Now().UTC().Format("20060102T150405.000000000Z"), ss.seq)
+
+	tmp := filepath.Join(ss.dir, spoolTmpDir, name)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return err
+	}
+	newDir := filepath.Join(ss.dir, spoolNewDir)
+	if err := os.MkdirAll(newDir, 0755); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+
+	base := filepath.Base(adv.filename)
+	for _, x := range []struct {
+		p string
+		d string
+	}{
+		{base, adv.doc},
+		{base + ".sha256", adv.sha256},
+		{base + ".sha512", adv.sha512},
+		{spoolStatusFile, string(adv.status) + "\n"},
+	} {
+		if len(x.d) != 0 {
+			path := filepath.Join(tmp, x.p)
+			if err := os.WriteFile(path, []byte(x.d), 0644); err != nil {
+				os.RemoveAll(tmp)
+				return err
+			}
+		}
+	}
+	if err := os.Rename(tmp, filepath.Join(newDir, name)); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+// jsonAdvisory is an advisory as written by the jsonSink.
+type jsonAdvisory struct {
+	Filename         string           `json:"filename"`
+	ValidationStatus validationStatus `json:"validation_status"`
+	SHA256           string           `json:"sha256,omitempty"`
+	SHA512           string           `json:"sha512,omitempty"`
+	Advisory         json.RawMessage  `json:"advisory"`
+}
+
+// jsonSink writes the advisories as newline delimited JSON.
+type jsonSink struct {
+	enc *json.Encoder
+}
+
+// newJSONSink creates a new jsonSink writing to w.
+func newJSONSink(w io.Writer) *jsonSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonSink{enc: enc}
+}
+
+// name implements the respective method of the [sink] interface.
+func (*jsonSink) name() string { return "stdout" }
+
+// put implements the respective method of the [sink] interface.
+func (js *jsonSink) put(adv *forwardedAdvisory) error {
+	return js.enc.Encode(&jsonAdvisory{
+		Filename:         filepath.Base(adv.filename),
+		ValidationStatus: adv.status,
+		SHA256:           adv.sha256,
+		SHA512:           adv.sha512,
+		Advisory:         json.RawMessage(adv.doc),
+	})
+}
+
+// execSink runs a command for each advisory. The advisory is passed
+// on STDIN, the filename, the validation status and the checksums
+// in the environment. Commands running longer than timeout are killed.
+type execSink struct {
+	command string
+	timeout time.Duration
+}
+
+// name implements the respective method of the [sink] interface.
+func (*execSink) name() string { return "exec" }
+
+// put implements the respective method of the [sink] interface.
+func (es *execSink) put(adv *forwardedAdvisory) error {
+	ctx := context.Background()
+	if es.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, es.timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, es.command)
+	cmd.Stdin = strings.NewReader(adv.doc)
+	cmd.Env = append(os.Environ(),
+		"CSAF_FILENAME="+filepath.Base(adv.filename),
+		"CSAF_VALIDATION_STATUS="+string(adv.status),
+		"CSAF_SHA256="+adv.sha256,
+		"CSAF_SHA512="+adv.sha512,
+	)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// Do not wait for children of the command keeping STDERR open.
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("running %q timed out after %s", es.command, es.timeout)
+		}
+		msg, _ := limitedString(&stderr, 512)
+		if msg = strings.TrimSpace(msg); msg != "" {
+			return fmt.Errorf("running %q failed: %w: %s", es.command, err, msg)
+		}
+		return fmt.Errorf("running %q failed: %w", es.command, err)
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+var testAdvisory = &forwardedAdvisory{
+	filename: "white/2023/test.json",
+	doc:      `{"document": {}}`,
+	status:   validValidationStatus,
+	sha256:   "256",
+	sha512:   "512",
+}
+
+func TestSpoolSink(t *testing.T) {
+	dir := t.TempDir()
+	ss := &spoolSink{dir: dir}
+	for i := 0; i < 2; i++ {
+		if err := ss.put(testAdvisory); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if entries, err := os.ReadDir(filepath.Join(dir, spoolTmpDir)); err != nil || len(entries) != 0 {
+		t.Fatalf("expected empty tmp folder: %v %v", entries, err)
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, spoolNewDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d spooled advisories expected 2", len(entries))
+	}
+	if entries[0].Name() >= entries[1].Name() {
+		t.Errorf("spooled advisories %q and %q not in order",
+			entries[0].Name(), entries[1].Name())
+	}
+
+	spooled := filepath.Join(dir, spoolNewDir, entries[1].Name())
+	for name, expected := range map[string]string{
+		"test.json":        testAdvisory.doc,
+		"test.json.sha256": "256",
+		"test.json.sha512": "512",
+		spoolStatusFile:    "valid\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(spooled, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expected {
+			t.Errorf("%s: got %q expected %q", name, data, expected)
+		}
+	}
+
+	// Spooling into a file should fail.
+	bad := filepath.Join(dir, "file")
+	if err := os.WriteFile(bad, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&spoolSink{dir: bad}).put(testAdvisory); err == nil {
+		t.Fatal("expected to fail with an error")
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	var buf bytes.Buffer
+	js := newJSONSink(&buf)
+	for i := 0; i < 2; i++ {
+		if err := js.put(testAdvisory); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines expected 2", len(lines))
+	}
+	var got jsonAdvisory
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Filename != "test.json" ||
+		got.ValidationStatus != validValidationStatus ||
+		got.SHA256 != "256" || got.SHA512 != "512" ||
+		string(got.Advisory) != `{"document":{}}` {
+		t.Errorf("unexpected JSON line %q", lines[0])
+	}
+
+	if err := js.put(&forwardedAdvisory{doc: "{"}); err == nil {
+		t.Fatal("broken advisory should result in an error")
+	}
+}
+
+func TestExecSink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "ingest.sh")
+	if err := os.WriteFile(script, []byte(`#!/bin/sh
+cat > "`+out+`"
+echo "$CSAF_FILENAME $CSAF_VALIDATION_STATUS $CSAF_SHA256 $CSAF_SHA512" >> "`+out+`"
+`), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := (&execSink{command: script}).put(testAdvisory); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := testAdvisory.doc + "test.json valid 256 512\n"; string(data) != expected {
+		t.Errorf("got %q expected %q", data, expected)
+	}
+
+	failing := filepath.Join(dir, "fail.sh")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho broken >&2\nexit 1\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	err = (&execSink{command: failing}).put(testAdvisory)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("expected error with message of command, got %v", err)
+	}
+
+	hanging := filepath.Join(dir, "hang.sh")
+	if err := os.WriteFile(hanging, []byte("#!/bin/sh\nsleep 60\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	err = (&execSink{command: hanging, timeout: 100 * time.Millisecond}).put(testAdvisory)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("hanging command took %s to be stopped", d)
+	}
+}
+
+func TestCheckForwardCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := filepath.Join(dir, "ingest.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\ncat > /dev/null\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	spaced := filepath.Join(dir, "my ingest.sh")
+	if err := os.WriteFile(spaced, []byte("#!/bin/sh\ncat > /dev/null\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		command string
+		err     string
+	}{
+		{"none", "", ""},
+		{"executable", script, ""},
+		{"executable with space", spaced, ""},
+		{"with arguments", script + " -T -", "arguments are not supported"},
+		{"missing", filepath.Join(dir, "missing.sh"), "not usable"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &config{ForwardCommand: tc.command}
+			err := cfg.checkForwardCommand()
+			switch {
+			case tc.err == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Errorf("expected error with %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+type failingSink struct{ puts int }
+
+func (*failingSink) name() string { return "failing" }
+
+func (fs *failingSink) put(*forwardedAdvisory) error {
+	fs.puts++
+	return errors.New("does not work")
+}
+
+func TestForwarderSinks(t *testing.T) {
+	orig := slog.Default()
+	defer slog.SetDefault(orig)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	dir := t.TempDir()
+	cfg := &config{
+		Directory:  dir,
+		ForwardDir: "spool",
+	}
+	fw := newForwarder(cfg)
+	if len(fw.sinks) != 1 {
+		t.Fatalf("got %d sinks expected 1", len(fw.sinks))
+	}
+	if ss, ok := fw.sinks[0].(*spoolSink); !ok || ss.dir != filepath.Join(dir, "spool") {
+		t.Fatalf("unexpected sink %v", fw.sinks[0])
+	}
+
+	if !fw.send("test.json", "{}", validValidationStatus, "256", "512") {
+		t.Fatal("forwarding to spool directory failed")
+	}
+
+	fs := &failingSink{}
+	fw.sinks = append(fw.sinks, fs)
+	if fw.send("test.json", "{}", validValidationStatus, "256", "512") {
+		t.Fatal("forwarding to failing sink succeeded")
+	}
+	if fs.puts != 1 || fw.succeeded != 1 || fw.failed != 1 {
+		t.Errorf("got %d puts, %d succeeded and %d failed expected 1 each",
+			fs.puts, fw.succeeded, fw.failed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, failedForwardDir, "test.json")); err != nil {
+		t.Errorf("failed advisory not stored: %v", err)
+	}
+	// Only the failing sink is recorded.
+	sinks, err := os.ReadFile(filepath.Join(dir, failedForwardDir, "test.json"+failedSinksExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(sinks) != "failing\n" {
+		t.Errorf("got failed sinks %q expected \"failing\\n\"", sinks)
+	}
+}
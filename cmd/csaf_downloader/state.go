@@ -0,0 +1,195 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// syncState is the persistent state of incremental downloads.
+type syncState struct {
+	mu      sync.Mutex
+	Domains map[string]*domainState `json:"domains,omitempty"`
+}
+
+// domainState is the state of the downloads from a single domain.
+type domainState struct {
+	// Sources maps the URLs of changes.csv files and ROLIE feeds
+	// to the time of their newest entry downloaded.
+	Sources map[string]time.Time `json:"sources,omitempty"`
+	// Files maps the URLs of the downloaded advisories to their state.
+	Files map[string]*fileState `json:"files,omitempty"`
+}
+
+// fileState is the state of a downloaded advisory.
+type fileState struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	SHA256       string `json:"sha256,omitempty"`
+	SHA512       string `json:"sha512,omitempty"`
+	Path         string `json:"path,omitempty"`
+}
+
+// loadSyncState loads the state from the given file.
+// If the file does not exist an empty state is returned.
+func loadSyncState(fname string) (*syncState, error) {
+	state := new(syncState)
+	f, err := os.Open(fname)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return state, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	if err := json.NewDecoder(f).Decode(state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// save writes the state to the given file.
+func (s *syncState) save(fname string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return writeJSONFile(fname, s)
+}
+
+// writeJSONFile writes v JSON encoded to the given file.
+// The file is replaced atomically.
+func writeJSONFile(fname string, v any) error {
+	f, err := os.CreateTemp(filepath.Dir(fname), filepath.Base(fname)+".*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, fname)
+}
+
+// domain returns the state of the given domain.
+// s.mu has to be locked.
+func (s *syncState) domain(name string) *domainState {
+	if s.Domains == nil {
+		s.Domains = map[string]*domainState{}
+	}
+	ds := s.Domains[name]
+	if ds == nil {
+		ds = new(domainState)
+		s.Domains[name] = ds
+	}
+	return ds
+}
+
+// since returns the time of the newest entry downloaded
+// from the given source of the domain.
+func (s *syncState) since(domain, source string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.domain(domain).Sources[source]
+}
+
+// seen records the time of the newest entry downloaded
+// from the given source of the domain.
+func (s *syncState) seen(domain, source string, newest time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds := s.domain(domain)
+	if ds.Sources == nil {
+		ds.Sources = map[string]time.Time{}
+	}
+	if newest.After(ds.Sources[source]) {
+		ds.Sources[source] = newest.UTC()
+	}
+}
+
+// file returns a copy of the state of the advisory with
+// the given URL of the domain. Returns nil if it is unknown.
+func (s *syncState) file(domain, url string) *fileState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fs := s.domain(domain).Files[url]; fs != nil {
+		c := *fs
+		return &c
+	}
+	return nil
+}
+
+// store records the state of the advisory with the given URL of the domain.
+func (s *syncState) store(domain, url string, fs *fileState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ds := s.domain(domain)
+	if ds.Files == nil {
+		ds.Files = map[string]*fileState{}
+	}
+	ds.Files[url] = fs
+}
+
+// conditional returns true if a request for the advisory
+// can be made conditional on its change.
+// If the advisory is stored the stored file has to exist.
+func (fs *fileState) conditional(noStore bool) bool {
+	if fs == nil || (fs.ETag == "" && fs.LastModified == "") {
+		return false
+	}
+	if noStore {
+		return true
+	}
+	if fs.Path == "" {
+		return false
+	}
+	_, err := os.Stat(fs.Path)
+	return err == nil
+}
+
+// conditionalGet fetches the given URL. If the state of the advisory
+// allows it the request is made conditional on its change.
+// The request and its retries are cancelled with ctx.
+func conditionalGet(
+	ctx context.Context,
+	client util.Client,
+	url string,
+	fs *fileState,
+	noStore bool,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !fs.conditional(noStore) {
+		return client.Do(req)
+	}
+	if fs.ETag != "" {
+		req.Header.Set("If-None-Match", fs.ETag)
+	}
+	if fs.LastModified != "" {
+		req.Header.Set("If-Modified-Since", fs.LastModified)
+	}
+	return client.Do(req)
+}
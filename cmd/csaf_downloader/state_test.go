@@ -0,0 +1,106 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSyncStateSaveLoad(t *testing.T) {
+	fname := filepath.Join(t.TempDir(), "state.json")
+
+	state, err := loadSyncState(fname)
+	if err != nil {
+		t.Fatalf("loading missing state failed: %v", err)
+	}
+
+	const (
+		domain = "example.com"
+		source = "https://example.com/.well-known/csaf/white/changes.csv"
+		file   = "https://example.com/.well-known/csaf/white/2023/a.json"
+	)
+	newest := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	state.seen(domain, source, newest)
+	// Older entries must not move the state back.
+	state.seen(domain, source, newest.Add(-time.Hour))
+	state.store(domain, file, &fileState{ETag: `"abc"`, Path: "a.json"})
+
+	if err := state.save(fname); err != nil {
+		t.Fatalf("saving state failed: %v", err)
+	}
+	if state, err = loadSyncState(fname); err != nil {
+		t.Fatalf("loading state failed: %v", err)
+	}
+	if got := state.since(domain, source); !got.Equal(newest) {
+		t.Errorf("got %v expected %v", got, newest)
+	}
+	if got := state.since("other.com", source); !got.IsZero() {
+		t.Errorf("expected no time for unknown domain, got %v", got)
+	}
+	fs := state.file(domain, file)
+	if fs == nil || fs.ETag != `"abc"` || fs.Path != "a.json" {
+		t.Errorf("unexpected file state %+v", fs)
+	}
+	if state.file(domain, file+".unknown") != nil {
+		t.Error("expected no state for unknown file")
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	const (
+		etag         = `"v1"`
+		lastModified = "Sat, 01 Jul 2023 12:00:00 GMT"
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag ||
+			r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	stored := filepath.Join(t.TempDir(), "a.json")
+	if err := os.WriteFile(stored, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		fs      *fileState
+		noStore bool
+		status  int
+	}{
+		{"unknown", nil, false, http.StatusOK},
+		{"etag", &fileState{ETag: etag, Path: stored}, false, http.StatusNotModified},
+		{"last modified", &fileState{LastModified: lastModified, Path: stored}, false, http.StatusNotModified},
+		{"changed", &fileState{ETag: `"v0"`, Path: stored}, false, http.StatusOK},
+		{"missing file", &fileState{ETag: etag, Path: stored + ".missing"}, false, http.StatusOK},
+		{"no store", &fileState{ETag: etag}, true, http.StatusNotModified},
+		{"no validators", &fileState{Path: stored}, false, http.StatusOK},
+	} {
+		resp, err := conditionalGet(context.Background(), server.Client(), server.URL, tc.fs, tc.noStore)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: got status %d expected %d", tc.name, resp.StatusCode, tc.status)
+		}
+	}
+}
@@ -13,6 +13,7 @@ import "golang.org/x/exp/slog"
 // stats contains counters of the downloads.
 type stats struct {
 	downloadFailed  int
+	storeFailed     int
 	filenameFailed  int
 	schemaFailed    int
 	remoteFailed    int
@@ -20,11 +21,14 @@ type stats struct {
 	sha512Failed    int
 	signatureFailed int
 	succeeded       int
+	notModified     int
+	notSelected     int
 }
 
 // add adds other stats to this.
 func (st *stats) add(o *stats) {
 	st.downloadFailed += o.downloadFailed
+	st.storeFailed += o.storeFailed
 	st.filenameFailed += o.filenameFailed
 	st.schemaFailed += o.schemaFailed
 	st.remoteFailed += o.remoteFailed
@@ -32,10 +36,13 @@ func (st *stats) add(o *stats) {
 	st.sha512Failed += o.sha512Failed
 	st.signatureFailed += o.signatureFailed
 	st.succeeded += o.succeeded
+	st.notModified += o.notModified
+	st.notSelected += o.notSelected
 }
 
 func (st *stats) totalFailed() int {
 	return st.downloadFailed +
+		st.storeFailed +
 		st.filenameFailed +
 		st.schemaFailed +
 		st.remoteFailed +
@@ -48,9 +55,12 @@ func (st *stats) totalFailed() int {
 func (st *stats) log() {
 	slog.Info("Download statistics",
 		"succeeded", st.succeeded,
+		"not_modified", st.notModified,
+		"not_selected", st.notSelected,
 		"total_failed", st.totalFailed(),
 		"filename_failed", st.filenameFailed,
 		"download_failed", st.downloadFailed,
+		"store_failed", st.storeFailed,
 		"schema_failed", st.schemaFailed,
 		"remote_failed", st.remoteFailed,
 		"sha256_failed", st.sha256Failed,
@@ -19,6 +19,7 @@ import (
 func TestStatsAdd(t *testing.T) {
 	a := stats{
 		downloadFailed:  2,
+		storeFailed:     31,
 		filenameFailed:  3,
 		schemaFailed:    5,
 		remoteFailed:    7,
@@ -26,10 +27,13 @@ func TestStatsAdd(t *testing.T) {
 		sha512Failed:    13,
 		signatureFailed: 17,
 		succeeded:       19,
+		notModified:     23,
+		notSelected:     29,
 	}
 	b := a
 	a.add(&b)
 	b.downloadFailed *= 2
+	b.storeFailed *= 2
 	b.filenameFailed *= 2
 	b.schemaFailed *= 2
 	b.remoteFailed *= 2
@@ -37,6 +41,8 @@ func TestStatsAdd(t *testing.T) {
 	b.sha512Failed *= 2
 	b.signatureFailed *= 2
 	b.succeeded *= 2
+	b.notModified *= 2
+	b.notSelected *= 2
 	if a != b {
 		t.Fatalf("%v != %v", a, b)
 	}
@@ -45,6 +51,7 @@ func TestStatsAdd(t *testing.T) {
 func TestStatsTotalFailed(t *testing.T) {
 	a := stats{
 		downloadFailed:  2,
+		storeFailed:     31,
 		filenameFailed:  3,
 		schemaFailed:    5,
 		remoteFailed:    7,
@@ -53,6 +60,7 @@ func TestStatsTotalFailed(t *testing.T) {
 		signatureFailed: 17,
 	}
 	sum := a.downloadFailed +
+		a.storeFailed +
 		a.filenameFailed +
 		a.schemaFailed +
 		a.remoteFailed +
@@ -72,6 +80,7 @@ func TestStatsLog(t *testing.T) {
 	slog.SetDefault(slog.New(h))
 	a := stats{
 		downloadFailed:  2,
+		storeFailed:     31,
 		filenameFailed:  3,
 		schemaFailed:    5,
 		remoteFailed:    7,
@@ -79,13 +88,18 @@ func TestStatsLog(t *testing.T) {
 		sha512Failed:    13,
 		signatureFailed: 17,
 		succeeded:       19,
+		notModified:     23,
+		notSelected:     29,
 	}
 	a.log()
 	type result struct {
 		Succeeded       int `json:"succeeded"`
+		NotModified     int `json:"not_modified"`
+		NotSelected     int `json:"not_selected"`
 		TotalFailed     int `json:"total_failed"`
 		FilenameFailed  int `json:"filename_failed"`
 		DownloadFailed  int `json:"download_failed"`
+		StoreFailed     int `json:"store_failed"`
 		SchemaFailed    int `json:"schema_failed"`
 		RemoteFailed    int `json:"remote_failed"`
 		SHA256Failed    int `json:"sha256_failed"`
@@ -98,9 +112,12 @@ func TestStatsLog(t *testing.T) {
 	}
 	want := result{
 		Succeeded:       a.succeeded,
+		NotModified:     a.notModified,
+		NotSelected:     a.notSelected,
 		TotalFailed:     a.totalFailed(),
 		FilenameFailed:  a.filenameFailed,
 		DownloadFailed:  a.downloadFailed,
+		StoreFailed:     a.storeFailed,
 		SchemaFailed:    a.schemaFailed,
 		RemoteFailed:    a.remoteFailed,
 		SHA256Failed:    a.sha256Failed,
@@ -111,3 +128,12 @@ func TestStatsLog(t *testing.T) {
 		t.Fatalf("%v != %v", got, want)
 	}
 }
+
+func TestUnfinished(t *testing.T) {
+	d := &downloader{}
+	d.addStats(&stats{downloadFailed: 2, schemaFailed: 3})
+	d.addStats(&stats{storeFailed: 5, succeeded: 7})
+	if got := d.unfinished(); got != 7 {
+		t.Fatalf("got %d expected 7", got)
+	}
+}
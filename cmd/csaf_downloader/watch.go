@@ -0,0 +1,156 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// maxBackoff is the maximal delay between two polls
+// of a domain which failed repeatedly.
+const maxBackoff = 24 * time.Hour
+
+// watchedDomain is the polling state of a domain in watch mode.
+type watchedDomain struct {
+	name     string
+	next     time.Time
+	failures int
+}
+
+// advertisedUpdateInterval extracts the update interval
+// from a provider metadata document if it has one.
+func (d *downloader) advertisedUpdateInterval(doc any) (time.Duration, bool) {
+	var interval string
+	if err := d.eval.Extract(
+		`$.update_interval`, util.StringMatcher(&interval), false, doc,
+	); err != nil {
+		return 0, false
+	}
+	return util.ParseUpdateInterval(interval)
+}
+
+// pollInterval returns the interval between two polls of a domain.
+// Domains are not polled more often than they advertise to be updated.
+func (d *downloader) pollInterval(domain string) time.Duration {
+	interval := d.cfg.WatchInterval
+	if advertised := d.updateIntervals[domain]; advertised > interval {
+		interval = advertised
+	}
+	return interval
+}
+
+// backoff returns the delay before the next poll of a domain
+// after the given number of consecutive failures.
+func backoff(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 0; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff && interval < maxBackoff {
+		delay = maxBackoff
+	}
+	return delay
+}
+
+// schedule plans the next poll of the domain depending on
+// the result of the last one.
+func (wd *watchedDomain) schedule(now time.Time, interval time.Duration, err error) {
+	if err != nil {
+		wd.failures++
+	} else {
+		wd.failures = 0
+	}
+	wd.next = now.Add(backoff(interval, wd.failures))
+}
+
+// poll downloads the advisories of the domains which are due.
+// No further domain is polled after ctx is done. The download in
+// progress is not bound to ctx so that it is finished and its state
+// is saved. Returns true if at least one domain was polled.
+func (d *downloader) poll(ctx context.Context, watched []*watchedDomain) bool {
+	var polled bool
+	for _, wd := range watched {
+		if ctx.Err() != nil {
+			break
+		}
+		if time.Now().Before(wd.next) {
+			continue
+		}
+		polled = true
+		dr := d.report.domain(wd.name)
+		err := d.download(context.Background(), wd.name)
+		dr.finish(err)
+		if err != nil {
+			slog.Error("Downloading failed",
+				"domain", wd.name,
+				"error", err)
+		}
+		if err := d.saveState(); err != nil {
+			slog.Error("Saving state failed", "error", err)
+		}
+		wd.schedule(time.Now(), d.pollInterval(wd.name), err)
+		slog.Info("Next poll scheduled",
+			"domain", wd.name,
+			"failures", wd.failures,
+			"next", wd.next)
+	}
+	return polled
+}
+
+// watch polls the given domains for new advisories until
+// the context is cancelled. A download in progress is finished
+// before it returns.
+func (d *downloader) watch(ctx context.Context, domains []string) error {
+	if len(domains) == 0 {
+		return errors.New("no domains to watch")
+	}
+	defer d.stats.log()
+
+	watched := make([]*watchedDomain, len(domains))
+	for i, domain := range domains {
+		watched[i] = &watchedDomain{name: domain}
+	}
+
+	for {
+		if d.poll(ctx, watched) {
+			d.stats.log()
+			// Each round of polls gets its own report.
+			if err := d.saveReport(); err != nil {
+				slog.Error("Saving report failed", "error", err)
+			}
+			if d.report != nil {
+				d.report = newRunReport()
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		next := watched[0].next
+		for _, wd := range watched[1:] {
+			if wd.next.Before(next) {
+				next = wd.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"github.com/csaf-poc/csaf_distribution/v3/internal/options"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+func TestBackoff(t *testing.T) {
+	for _, tc := range []struct {
+		interval time.Duration
+		failures int
+		expected time.Duration
+	}{
+		{time.Hour, 0, time.Hour},
+		{time.Hour, 1, 2 * time.Hour},
+		{time.Hour, 3, 8 * time.Hour},
+		{time.Hour, 10, maxBackoff},
+		{48 * time.Hour, 2, 48 * time.Hour},
+	} {
+		if got := backoff(tc.interval, tc.failures); got != tc.expected {
+			t.Errorf("%v, %d: got %v expected %v",
+				tc.interval, tc.failures, got, tc.expected)
+		}
+	}
+}
+
+func TestWatchedDomainSchedule(t *testing.T) {
+	now := time.Now()
+	wd := watchedDomain{name: "example.com"}
+	wd.schedule(now, time.Hour, errors.New("failed"))
+	wd.schedule(now, time.Hour, errors.New("failed"))
+	if wd.failures != 2 || !wd.next.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("unexpected schedule after failures: %+v", wd)
+	}
+	wd.schedule(now, time.Hour, nil)
+	if wd.failures != 0 || !wd.next.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected schedule after success: %+v", wd)
+	}
+}
+
+func TestPollInterval(t *testing.T) {
+	d := &downloader{
+		cfg:             &config{WatchInterval: 2 * time.Hour},
+		eval:            util.NewPathEval(),
+		updateIntervals: map[string]time.Duration{},
+	}
+	doc := map[string]any{"update_interval": "daily"}
+	if interval, ok := d.advertisedUpdateInterval(doc); ok {
+		d.updateIntervals["slow.example.com"] = interval
+	}
+	d.updateIntervals["fast.example.com"] = time.Minute
+
+	for _, tc := range []struct {
+		domain   string
+		expected time.Duration
+	}{
+		{"slow.example.com", 24 * time.Hour},
+		{"fast.example.com", 2 * time.Hour},
+		{"unknown.example.com", 2 * time.Hour},
+	} {
+		if got := d.pollInterval(tc.domain); got != tc.expected {
+			t.Errorf("%s: got %v expected %v", tc.domain, got, tc.expected)
+		}
+	}
+}
+
+func TestWatchFinishesDownload(t *testing.T) {
+	orig := slog.Default()
+	defer slog.SetDefault(orig)
+	slog.SetDefault(slog.New(slog.NewJSONHandler(io.Discard, nil)))
+
+	const advisory = `{
+  "document": {
+    "category": "csaf_base",
+    "csaf_version": "2.0",
+    "publisher": {
+      "category": "vendor",
+      "name": "Example Company",
+      "namespace": "https://example.com"
+    },
+    "title": "Example advisory",
+    "tracking": {
+      "current_release_date": "2023-01-01T10:00:00.000Z",
+      "id": "EXAMPLE-2023-0001",
+      "initial_release_date": "2023-01-01T10:00:00.000Z",
+      "revision_history": [
+        {"date": "2023-01-01T10:00:00.000Z", "number": "1", "summary": "Initial version."}
+      ],
+      "status": "final",
+      "version": "1"
+    }
+  }
+}`
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/provider-metadata.json":
+			io.WriteString(w, strings.ReplaceAll(`{
+  "canonical_url": "URL/provider-metadata.json",
+  "distributions": [{"directory_url": "URL/white/"}],
+  "last_updated": "2023-01-01T10:00:00.000Z",
+  "list_on_CSAF_aggregators": false,
+  "metadata_version": "2.0",
+  "mirror_on_CSAF_aggregators": false,
+  "publisher": {
+    "category": "vendor",
+    "name": "Example Company",
+    "namespace": "https://example.com"
+  },
+  "role": "csaf_provider"
+}`, "URL", server.URL))
+		case "/white/changes.csv":
+			io.WriteString(w, `"2023/example-2023-0001.json","2023-01-01T10:00:00Z"`+"\n")
+		case "/white/2023/example-2023-0001.json":
+			// The watch is interrupted while the advisory is downloaded.
+			cancel()
+			time.Sleep(50 * time.Millisecond)
+			io.WriteString(w, advisory)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	cfg := &config{
+		Directory:      t.TempDir(),
+		Insecure:       true,
+		Worker:         1,
+		WatchInterval:  time.Hour,
+		ValidationMode: validationUnsafe,
+		LogLevel:       &options.LogLevel{Level: slog.LevelInfo},
+	}
+	d, err := newDownloader(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.close()
+
+	if err := d.watch(ctx, []string{server.URL + "/provider-metadata.json"}); err != nil {
+		t.Fatal(err)
+	}
+	fname := filepath.Join(cfg.Directory, "white", "2023", "example-2023-0001.json")
+	if _, err := os.Stat(fname); err != nil {
+		t.Errorf("download in progress not finished: %v", err)
+	}
+}
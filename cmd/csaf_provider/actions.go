@@ -189,6 +189,22 @@ func (c *controller) upload(r *http.Request) (any, error) {
 		}
 	}
 
+	// Validate against local validator.
+	if c.cfg.LocalValidator != nil {
+		validator, err := c.cfg.LocalValidator.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer validator.Close()
+		rvr, err := validator.Validate(content)
+		if err != nil {
+			return nil, err
+		}
+		if !rvr.Valid {
+			return nil, errors.New("does not validate against local validator")
+		}
+	}
+
 	// Extract informations from the document.
 	pe := util.NewPathEval()
 
@@ -226,7 +242,7 @@ func (c *controller) upload(r *http.Request) (any, error) {
 
 	// Extract real TLP from document.
 	if t == tlpCSAF {
-		if t = tlp(strings.ToLower(ex.TLPLabel)); !t.valid() || t == tlpCSAF {
+		if t = documentTLP(ex.TLPLabel); !t.valid() || t == tlpCSAF {
 			return nil, fmt.Errorf(
 				"valid TLP label missing in document (found '%s')", t)
 		}
@@ -60,6 +60,7 @@ type config struct {
 	UploadLimit             *int64                       `toml:"upload_limit"`
 	Issuer                  *string                      `toml:"issuer"`
 	RemoteValidator         *csaf.RemoteValidatorOptions `toml:"remote_validator"`
+	LocalValidator          *csaf.LocalValidatorOptions  `toml:"local_validator"`
 	Categories              *[]string                    `toml:"categories"`
 	ServiceDocument         bool                         `toml:"create_service_document"`
 	WriteIndices            bool                         `toml:"write_indices"`
@@ -91,6 +92,20 @@ const (
 	tlpRed   tlp = "red"
 )
 
+// documentTLP returns the TLP of a TLP label found in a document.
+// The TLP v2.0 labels used by CSAF 2.1 are mapped to the
+// TLP v1 labels the provider folders are named after.
+func documentTLP(label string) tlp {
+	switch label {
+	case csaf.TLPLabelClear:
+		return tlpWhite
+	case csaf.TLPLabelAmberStrict:
+		return tlpAmber
+	default:
+		return tlp(strings.ToLower(label))
+	}
+}
+
 // valid returns true if the checked tlp matches one of the defined tlps.
 func (t tlp) valid() bool {
 	switch t {
@@ -137,10 +137,7 @@ func (c *controller) extendROLIE(
 		{Rel: "hash", HRef: csafURL + ".sha512"},
 		{Rel: "signature", HRef: csafURL + ".asc"},
 	}
-	e.Format = csaf.Format{
-		Schema:  "https://docs.oasis-open.org/csaf/csaf/v2.0/csaf_json_schema.json",
-		Version: "2.0",
-	}
+	e.Format = csaf.CSAFFormat(ex.CSAFVersion)
 	e.Content = csaf.Content{
 		Type: "application/json",
 		Src:  csafURL,
@@ -30,8 +30,8 @@ const (
 
 // The supported flag config of the uploader command line
 type config struct {
-	//lint:ignore SA5008 We are using choice twice: upload, create.
-	Action string `short:"a" long:"action" choice:"upload" choice:"create" description:"Action to perform" toml:"action"`
+	//lint:ignore SA5008 We are using choice three times: upload, create, import.
+	Action string `short:"a" long:"action" choice:"upload" choice:"create" choice:"import" description:"Action to perform" toml:"action"`
 	URL    string `short:"u" long:"url" description:"URL of the CSAF provider" value-name:"URL" toml:"url"`
 	//lint:ignore SA5008 We are using choice many times: csaf, white, green, amber, red.
 	TLP            string `short:"t" long:"tlp" choice:"csaf" choice:"white" choice:"green" choice:"amber" choice:"red" description:"TLP of the feed" toml:"tlp"`
@@ -70,7 +70,7 @@ func parseArgsConfig() ([]string, *config, error) {
 	p := options.Parser[config]{
 		DefaultConfigLocations: configPaths,
 		ConfigLocation:         func(cfg *config) string { return cfg.Config },
-		Usage:                  "[OPTIONS] advisories...",
+		Usage:                  "[OPTIONS] advisories...|archives...",
 		HasVersion:             func(cfg *config) bool { return cfg.Version },
 		SetDefaults: func(cfg *config) {
 			cfg.URL = defaultURL
@@ -143,7 +143,7 @@ func loadOpenPGPKey(filename string) (*crypto.Key, error) {
 
 // prepareOpenPGPKey loads the configured OpenPGP key.
 func (cfg *config) prepareOpenPGPKey() error {
-	if cfg.Action != "upload" || cfg.Key == nil {
+	if (cfg.Action != "upload" && cfg.Action != "import") || cfg.Key == nil {
 		return nil
 	}
 	if cfg.ExternalSigned {
@@ -0,0 +1,84 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/csaf-poc/csaf_distribution/v3/csaf"
+	"github.com/csaf-poc/csaf_distribution/v3/internal/archive"
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// uploadTLP maps the TLP label of an archived advisory
+// to the TLP of the feed to upload it to.
+func uploadTLP(label string) (string, error) {
+	switch l := csaf.TLPLabel(strings.ToUpper(label)); l {
+	case csaf.TLPLabelUnlabeled:
+		return "csaf", nil
+	case csaf.TLPLabelClear:
+		return "white", nil
+	case csaf.TLPLabelWhite, csaf.TLPLabelGreen, csaf.TLPLabelAmber, csaf.TLPLabelRed:
+		return strings.ToLower(string(l)), nil
+	}
+	return "", fmt.Errorf("unsupported TLP label %q", label)
+}
+
+// importArchive uploads the advisories of an archive written by
+// the csaf_downloader. The archive is verified against its manifest
+// before anything is uploaded. Advisories which did not pass the
+// validation of the downloader are not uploaded.
+func (p *processor) importArchive(fname string) error {
+	contents, err := archive.Read(fname)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := range contents.Manifest.Files {
+		file := &contents.Manifest.Files[i]
+		// Only the advisories have a TLP label.
+		filename := path.Base(file.Name)
+		if file.TLP == "" || !util.ConformingFileName(filename) {
+			continue
+		}
+		if file.Validation != archive.ValidationValid {
+			fmt.Printf("Skipping %s: validation status is %q\n",
+				file.Name, file.Validation)
+			continue
+		}
+		fmt.Printf("Importing %s\n", file.Name)
+
+		if err := func() error {
+			tlp, err := uploadTLP(file.TLP)
+			if err != nil {
+				return err
+			}
+			var signature []byte
+			if p.cfg.ExternalSigned {
+				var ok bool
+				if signature, ok = contents.Files[file.Name+".asc"]; !ok {
+					return errors.New("no signature in archive")
+				}
+			}
+			req, err := p.buildUploadRequest(
+				filename, contents.Files[file.Name], tlp, signature)
+			if err != nil {
+				return err
+			}
+			return p.upload(req)
+		}(); err != nil {
+			errs = append(errs, fmt.Errorf("importing %q failed: %v", file.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -112,6 +112,25 @@ func (p *processor) uploadRequest(filename string) (*http.Request, error) {
 		return nil, err
 	}
 
+	var signature []byte
+	if p.cfg.ExternalSigned {
+		if signature, err = os.ReadFile(filename + ".asc"); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.buildUploadRequest(filepath.Base(filename), data, p.cfg.TLP, signature)
+}
+
+// buildUploadRequest creates the request for uploading the given csaf document
+// with the given TLP. The signature is only used for external signed documents.
+func (p *processor) buildUploadRequest(
+	filename string,
+	data []byte,
+	tlp string,
+	signature []byte,
+) (*http.Request, error) {
+
 	if !p.cfg.NoSchemaCheck {
 		var doc any
 		if err := json.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
@@ -127,7 +146,7 @@ func (p *processor) uploadRequest(filename string) (*http.Request, error) {
 		}
 
 		eval := util.NewPathEval()
-		if err := util.IDMatchesFilename(eval, doc, filepath.Base(filename)); err != nil {
+		if err := util.IDMatchesFilename(eval, doc, filename); err != nil {
 			return nil, err
 		}
 	}
@@ -138,7 +157,7 @@ func (p *processor) uploadRequest(filename string) (*http.Request, error) {
 	// As the csaf_provider only accepts uploads with mime type
 	// "application/json" we have to set this.
 	part, err := misc.CreateFormFile(
-		writer, "csaf", filepath.Base(filename), "application/json")
+		writer, "csaf", filename, "application/json")
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +166,7 @@ func (p *processor) uploadRequest(filename string) (*http.Request, error) {
 		return nil, err
 	}
 
-	if err := writer.WriteField("tlp", p.cfg.TLP); err != nil {
+	if err := writer.WriteField("tlp", tlp); err != nil {
 		return nil, err
 	}
 
@@ -173,10 +192,6 @@ func (p *processor) uploadRequest(filename string) (*http.Request, error) {
 	}
 
 	if p.cfg.ExternalSigned {
-		signature, err := os.ReadFile(filename + ".asc")
-		if err != nil {
-			return nil, err
-		}
 		if err := writer.WriteField("signature", string(signature)); err != nil {
 			return nil, err
 		}
@@ -210,6 +225,13 @@ func (p *processor) process(filename string) error {
 		return err
 	}
 
+	return p.upload(req)
+}
+
+// upload sends an upload request to the server.
+// It prints the response messages.
+func (p *processor) upload(req *http.Request) error {
+
 	resp, err := p.httpClient().Do(req)
 	if err != nil {
 		return err
@@ -267,8 +289,13 @@ func (p *processor) run(args []string) error {
 		log.Println("No CSAF files given.")
 	}
 
+	process := p.process
+	if p.cfg.Action == "import" {
+		process = p.importArchive
+	}
+
 	for _, arg := range args {
-		if err := p.process(arg); err != nil {
+		if err := process(arg); err != nil {
 			return fmt.Errorf("processing %q failed: %v", arg, err)
 		}
 	}
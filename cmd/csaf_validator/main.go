@@ -11,6 +11,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -26,8 +27,9 @@ type options struct {
 	Version                bool     `long:"version" description:"Display version of the binary"`
 	RemoteValidator        string   `long:"validator" description:"URL to validate documents remotely" value-name:"URL"`
 	RemoteValidatorCache   string   `long:"validator_cache" description:"FILE to cache remote validations" value-name:"FILE"`
-	RemoteValidatorPresets []string `long:"validator_preset" description:"One or more presets to validate remotely" default:"mandatory"`
-	Output                 string   `short:"o" long:"output" description:"If a remote validator was used, display AMOUNT ('all', 'important' or 'short') results" value-name:"AMOUNT"`
+	RemoteValidatorPresets []string `long:"validator_preset" description:"One or more presets to validate remotely or locally" default:"mandatory"`
+	LocalValidator         bool     `long:"local_validator" description:"Validate documents locally with the built-in tests"`
+	Output                 string   `short:"o" long:"output" description:"If a remote or local validator was used, display AMOUNT ('all', 'important' or 'short') results" value-name:"AMOUNT"`
 }
 
 func main() {
@@ -54,10 +56,30 @@ func main() {
 // run validates the given files.
 func run(opts *options, files []string) error {
 
-	var validator csaf.RemoteValidator
+	var (
+		validator csaf.RemoteValidator
+		kind      string
+	)
 	eval := util.NewPathEval()
 
-	if opts.RemoteValidator != "" {
+	if opts.RemoteValidator != "" && opts.LocalValidator {
+		return errors.New(
+			"remote and local validator cannot be used at the same time")
+	}
+
+	switch {
+	case opts.LocalValidator:
+		validatorOptions := csaf.LocalValidatorOptions{
+			Presets: opts.RemoteValidatorPresets,
+		}
+		var err error
+		if validator, err = validatorOptions.Open(); err != nil {
+			return fmt.Errorf(
+				"preparing local validator failed: %w", err)
+		}
+		defer validator.Close()
+		kind = "local"
+	case opts.RemoteValidator != "":
 		validatorOptions := csaf.RemoteValidatorOptions{
 			URL:     opts.RemoteValidator,
 			Presets: opts.RemoteValidatorPresets,
@@ -69,6 +91,7 @@ func run(opts *options, files []string) error {
 				"preparing remote validator failed: %w", err)
 		}
 		defer validator.Close()
+		kind = "remote"
 	}
 
 	// Select amount level of output for remote validation.
@@ -118,12 +141,12 @@ func run(opts *options, files []string) error {
 			continue
 		}
 
-		// Validate against remote validator.
+		// Validate against remote or local validator.
 		if validator != nil {
 			rvr, err := validator.Validate(doc)
 			if err != nil {
-				return fmt.Errorf("remote validation of %q failed: %w",
-					file, err)
+				return fmt.Errorf("%s validation of %q failed: %w",
+					kind, file, err)
 			}
 			printResult(rvr)
 			var passes string
@@ -132,7 +155,7 @@ func run(opts *options, files []string) error {
 			} else {
 				passes = "does not pass"
 			}
-			fmt.Printf("%q %s remote validation.\n", file, passes)
+			fmt.Printf("%q %s %s validation.\n", file, passes, kind)
 		}
 	}
 
@@ -71,15 +71,43 @@ func (haf HashedAdvisoryFile) SHA512URL() string { return haf.name(2, ".sha512")
 // SignURL returns the URL of signature file of this advisory.
 func (haf HashedAdvisoryFile) SignURL() string { return haf.name(3, ".asc") }
 
+// UpdatedAdvisoryFile is an advisory file along with the time of
+// its last update as stated by the changes.csv or ROLIE feed
+// it is listed in.
+type UpdatedAdvisoryFile struct {
+	AdvisoryFile
+	Updated time.Time
+}
+
+// String returns the representation of the wrapped file.
+func (uaf UpdatedAdvisoryFile) String() string { return fmt.Sprint(uaf.AdvisoryFile) }
+
+// FileUpdated returns the time of the last update of an advisory
+// file as stated by its listing. Returns the zero time if unknown.
+func FileUpdated(file AdvisoryFile) time.Time {
+	if uaf, ok := file.(UpdatedAdvisoryFile); ok {
+		return uaf.Updated
+	}
+	return time.Time{}
+}
+
 // AdvisoryFileProcessor implements the extraction of
 // advisory file names from a given provider metadata.
 type AdvisoryFileProcessor struct {
 	AgeAccept func(time.Time) bool
 	Log       func(format string, args ...any)
-	client    util.Client
-	expr      *util.PathEval
-	doc       any
-	base      *url.URL
+	// Since returns for the URL of a changes.csv or a ROLIE feed
+	// the time of the newest entry processed before.
+	// If set only entries changed after this time are passed on.
+	Since func(source string) time.Time
+	// Seen is called with the URL of a changes.csv or a ROLIE feed
+	// and the time of the newest entry passed on after the
+	// entries are processed successfully.
+	Seen   func(source string, newest time.Time)
+	client util.Client
+	expr   *util.PathEval
+	doc    any
+	base   *url.URL
 }
 
 // NewAdvisoryFileProcessor constructs an filename extractor
@@ -108,6 +136,21 @@ func empty(arr []string) bool {
 	return true
 }
 
+// since returns the time the entries of source have to be newer than.
+func (afp *AdvisoryFileProcessor) since(source string) time.Time {
+	if afp.Since == nil {
+		return time.Time{}
+	}
+	return afp.Since(source)
+}
+
+// seen reports the time of the newest entry of source if there is one.
+func (afp *AdvisoryFileProcessor) seen(source string, newest time.Time) {
+	if afp.Seen != nil && !newest.IsZero() {
+		afp.Seen(source, newest)
+	}
+}
+
 // Process extracts the adivisory filenames and passes them with
 // the corresponding label to fn.
 func (afp *AdvisoryFileProcessor) Process(
@@ -176,12 +219,7 @@ func (afp *AdvisoryFileProcessor) Process(
 			}
 
 			// Use changes.csv to be able to filter by age.
-			files, err := afp.loadChanges(base, lg)
-			if err != nil {
-				return err
-			}
-			// XXX: Is treating as white okay? better look into the advisories?
-			if err := fn(TLPLabelWhite, files); err != nil {
+			if err := afp.processChanges(base, fn, lg); err != nil {
 				return err
 			}
 		}
@@ -189,31 +227,54 @@ func (afp *AdvisoryFileProcessor) Process(
 	return nil
 }
 
-// loadChanges loads baseURL/changes.csv and returns a list of files
-// prefixed by baseURL/.
-func (afp *AdvisoryFileProcessor) loadChanges(
+// processChanges passes the files listed in baseURL/changes.csv to fn.
+func (afp *AdvisoryFileProcessor) processChanges(
 	baseURL string,
+	fn func(TLPLabel, []AdvisoryFile) error,
 	lg func(string, ...any),
-) ([]AdvisoryFile, error) {
-
+) error {
 	base, err := url.Parse(baseURL)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	changesURL := base.JoinPath("changes.csv").String()
 
+	files, newest, err := afp.loadChanges(base, changesURL, lg)
+	if err != nil {
+		return err
+	}
+	// XXX: Is treating as white okay? better look into the advisories?
+	if err := fn(TLPLabelWhite, files); err != nil {
+		return err
+	}
+	afp.seen(changesURL, newest)
+	return nil
+}
+
+// loadChanges loads changesURL and returns a list of files
+// prefixed by base and the time of the newest of them.
+func (afp *AdvisoryFileProcessor) loadChanges(
+	base *url.URL,
+	changesURL string,
+	lg func(string, ...any),
+) ([]AdvisoryFile, time.Time, error) {
+
 	resp, err := afp.client.Get(changesURL)
 	if err != nil {
-		return nil, err
+		return nil, time.Time{}, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("fetching %s failed. Status code %d (%s)",
+		return nil, time.Time{}, fmt.Errorf("fetching %s failed. Status code %d (%s)",
 			changesURL, resp.StatusCode, resp.Status)
 	}
 
 	defer resp.Body.Close()
-	var files []AdvisoryFile
+	var (
+		files  []AdvisoryFile
+		since  = afp.since(changesURL)
+		newest time.Time
+	)
 	c := csv.NewReader(resp.Body)
 	const (
 		pathColumn = 0
@@ -225,7 +286,7 @@ func (afp *AdvisoryFileProcessor) loadChanges(
 			break
 		}
 		if err != nil {
-			return nil, err
+			return nil, time.Time{}, err
 		}
 		if len(r) < 2 {
 			lg("%q has not enough columns in line %d", line)
@@ -240,15 +301,25 @@ func (afp *AdvisoryFileProcessor) loadChanges(
 		if afp.AgeAccept != nil && !afp.AgeAccept(t) {
 			continue
 		}
+		// Skip the entries already processed. Entries as old as the
+		// newest one processed may have been added after it.
+		if t.Before(since) {
+			continue
+		}
 		path := r[pathColumn]
 		if _, err := url.Parse(path); err != nil {
 			lg("%q contains an invalid URL %q in line %d", changesURL, path, line)
 			continue
 		}
-		files = append(files,
-			PlainAdvisoryFile(base.JoinPath(path).String()))
+		files = append(files, UpdatedAdvisoryFile{
+			AdvisoryFile: PlainAdvisoryFile(base.JoinPath(path).String()),
+			Updated:      t,
+		})
+		if t.After(newest) {
+			newest = t
+		}
 	}
-	return files, nil
+	return files, newest, nil
 }
 
 func (afp *AdvisoryFileProcessor) processROLIE(
@@ -298,7 +369,11 @@ func (afp *AdvisoryFileProcessor) processROLIE(
 			continue
 		}
 
-		var files []AdvisoryFile
+		var (
+			files  []AdvisoryFile
+			since  = afp.since(feedURL.String())
+			newest time.Time
+		)
 
 		resolve := func(u string) string {
 			if u == "" {
@@ -315,11 +390,17 @@ func (afp *AdvisoryFileProcessor) processROLIE(
 		rfeed.Entries(func(entry *Entry) {
 
 			// Filter if we have date checking.
+			t := time.Time(entry.Updated)
 			if afp.AgeAccept != nil {
-				if t := time.Time(entry.Updated); !t.IsZero() && !afp.AgeAccept(t) {
+				if !t.IsZero() && !afp.AgeAccept(t) {
 					return
 				}
 			}
+			// Skip the entries already processed. Entries as old as the
+			// newest one processed may have been added after it.
+			if !t.IsZero() && t.Before(since) {
+				return
+			}
 
 			var self, sha256, sha512, sign string
 
@@ -352,8 +433,14 @@ func (afp *AdvisoryFileProcessor) processROLIE(
 			} else {
 				file = PlainAdvisoryFile(self)
 			}
+			if !t.IsZero() {
+				file = UpdatedAdvisoryFile{AdvisoryFile: file, Updated: t}
+			}
 
 			files = append(files, file)
+			if t.After(newest) {
+				newest = t
+			}
 		})
 
 		var label TLPLabel
@@ -366,6 +453,7 @@ func (afp *AdvisoryFileProcessor) processROLIE(
 		if err := fn(label, files); err != nil {
 			return err
 		}
+		afp.seen(feedURL.String(), newest)
 	}
 	return nil
 }
@@ -0,0 +1,89 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+func TestAdvisoryFileProcessorSince(t *testing.T) {
+	const changes = `"2023/b.json","2023-07-02T10:00:00Z"
+"2023/a.json","2023-07-01T10:00:00Z"
+"2022/c.json","2022-12-01T10:00:00Z"
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/changes.csv" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(changes))
+	}))
+	defer server.Close()
+
+	base, err := url.Parse(server.URL + "/provider-metadata.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := map[string]any{
+		"distributions": []any{
+			map[string]any{"directory_url": server.URL},
+		},
+	}
+	changesURL := server.URL + "/changes.csv"
+
+	for _, tc := range []struct {
+		name   string
+		since  time.Time
+		files  int
+		newest time.Time
+	}{
+		{"all", time.Time{}, 3, time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC)},
+		{"newer", time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC), 1, time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC)},
+		// Entries as old as the newest one seen are passed on again.
+		{"same time", time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC), 2, time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC)},
+		{"newest", time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC), 1, time.Date(2023, 7, 2, 10, 0, 0, 0, time.UTC)},
+		{"none", time.Date(2023, 7, 3, 10, 0, 0, 0, time.UTC), 0, time.Time{}},
+	} {
+		afp := NewAdvisoryFileProcessor(server.Client(), util.NewPathEval(), doc, base)
+		afp.Log = t.Logf
+		afp.Since = func(source string) time.Time {
+			if source != changesURL {
+				t.Errorf("%s: unexpected source %q", tc.name, source)
+			}
+			return tc.since
+		}
+		var newest time.Time
+		afp.Seen = func(_ string, n time.Time) { newest = n }
+
+		var files int
+		if err := afp.Process(func(_ TLPLabel, fs []AdvisoryFile) error {
+			files += len(fs)
+			for _, f := range fs {
+				if FileUpdated(f).IsZero() {
+					t.Errorf("%s: no update time for %s", tc.name, f)
+				}
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if files != tc.files {
+			t.Errorf("%s: got %d files expected %d", tc.name, files, tc.files)
+		}
+		if !newest.Equal(tc.newest) {
+			t.Errorf("%s: got newest %v expected %v", tc.name, newest, tc.newest)
+		}
+	}
+}
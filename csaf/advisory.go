@@ -124,6 +124,7 @@ type ProductIdentificationHelper struct {
 	Hashes        *Hashes      `json:"hashes,omitempty"`
 	ModelNumbers  []*string    `json:"model_numbers,omitempty"` // unique elements
 	PURL          *PURL        `json:"purl,omitempty"`
+	PURLs         []*PURL      `json:"purls,omitempty"` // CSAF 2.1, unique elements
 	SBOMURLs      []*string    `json:"sbom_urls,omitempty"`
 	SerialNumbers []*string    `json:"serial_numbers,omitempty"` // unique elements
 	SKUs          []*string    `json:"skus,omitempty"`
@@ -228,10 +229,16 @@ var documentCategoryPattern = patternUnmarshal("^[^\\s\\-_\\.](.*[^\\s\\-_\\.])?
 // Version is the version of a document.
 type Version string
 
-// CSAFVersion20 is the current version of CSAF.
-const CSAFVersion20 Version = "2.0"
+const (
+	// CSAFVersion20 is the version 2.0 of CSAF.
+	CSAFVersion20 Version = "2.0"
+	// CSAFVersion21 is the version 2.1 of CSAF.
+	CSAFVersion21 Version = "2.1"
+)
 
-var csafVersionPattern = alternativesUnmarshal(string(CSAFVersion20))
+var csafVersionPattern = alternativesUnmarshal(
+	string(CSAFVersion20),
+	string(CSAFVersion21))
 
 // TLP provides details about the TLP classification of the document.
 type TLP struct {
@@ -239,10 +246,18 @@ type TLP struct {
 	URL              *string   `json:"url,omitempty"`
 }
 
+// SharingGroup defines a set of recipients which are
+// allowed to receive the document (CSAF 2.1).
+type SharingGroup struct {
+	ID   *string `json:"id"` // required
+	Name *string `json:"name,omitempty"`
+}
+
 // DocumentDistribution describes rules for sharing a document.
 type DocumentDistribution struct {
-	Text *string `json:"text,omitempty"`
-	TLP  *TLP    `json:"tlp,omitempty"`
+	SharingGroup *SharingGroup `json:"sharing_group,omitempty"` // CSAF 2.1
+	Text         *string       `json:"text,omitempty"`
+	TLP          *TLP          `json:"tlp,omitempty"`
 }
 
 // DocumentPublisher provides information about the publishing entity.
@@ -310,7 +325,7 @@ type Revisions []*Revision
 type Tracking struct {
 	Aliases            []*string       `json:"aliases,omitempty"`    // unique elements
 	CurrentReleaseDate *string         `json:"current_release_date"` // required
-	Generator          *Generator      `json:"generator"`
+	Generator          *Generator      `json:"generator,omitempty"`
 	ID                 *TrackingID     `json:"id"`                   // required
 	InitialReleaseDate *string         `json:"initial_release_date"` // required
 	RevisionHistory    Revisions       `json:"revision_history"`     // required
@@ -331,6 +346,7 @@ type Document struct {
 	CSAFVersion       *Version              `json:"csaf_version"` // required
 	Distribution      *DocumentDistribution `json:"distribution,omitempty"`
 	Lang              *Lang                 `json:"lang,omitempty"`
+	LicenseExpression *string               `json:"license_expression,omitempty"` // CSAF 2.1
 	Notes             Notes                 `json:"notes,omitempty"`
 	Publisher         *DocumentPublisher    `json:"publisher"` // required
 	References        References            `json:"references,omitempty"`
@@ -342,17 +358,18 @@ type Document struct {
 // ProductGroupID is a reference token for product group instances.
 type ProductGroupID string
 
+// ProductGroupIDs is a list of ProductGroupID elements.
+type ProductGroupIDs []*ProductGroupID // unique elements
+
 // ProductGroup is a group of products in the document that belong to one group.
 type ProductGroup struct {
-	GroupID    *string   `json:"group_id"`    // required
-	ProductIDs *Products `json:"product_ids"` // required, two or more unique elements
-	Summary    *string   `json:"summary,omitempty"`
+	GroupID    *ProductGroupID `json:"group_id"`    // required
+	ProductIDs *Products       `json:"product_ids"` // required, two or more unique elements
+	Summary    *string         `json:"summary,omitempty"`
 }
 
-// ProductGroups is a list of ProductGroupIDs
-type ProductGroups struct {
-	ProductGroupIDs []*ProductGroupID `json:"product_group_ids"` // unique elements
-}
+// ProductGroups is a list of ProductGroup elements.
+type ProductGroups []*ProductGroup
 
 // RelationshipCategory is the category of a relationship.
 type RelationshipCategory string
@@ -396,7 +413,7 @@ type Branches []*Branch
 type ProductTree struct {
 	Branches         Branches          `json:"branches,omitempty"`
 	FullProductNames *FullProductNames `json:"full_product_names,omitempty"`
-	ProductGroups    *ProductGroups    `json:"product_groups,omitempty"`
+	ProductGroups    ProductGroups     `json:"product_groups,omitempty"`
 	RelationShips    *Relationships    `json:"relationships,omitempty"`
 }
 
@@ -412,10 +429,14 @@ var weaknessIDPattern = patternUnmarshal("^CWE-[1-9]\\d{0,5}$")
 
 // CWE holds the MITRE standard Common Weakness Enumeration (CWE) for the weakness associated.
 type CWE struct {
-	ID   *WeaknessID `json:"id"`   // required
-	Name *string     `json:"name"` // required
+	ID      *WeaknessID `json:"id"`                // required
+	Name    *string     `json:"name"`              // required
+	Version *string     `json:"version,omitempty"` // CSAF 2.1, required there
 }
 
+// CWEs is a list of CWE elements.
+type CWEs []*CWE
+
 // FlagLabel is the label of a flag for a vulnerability.
 type FlagLabel string
 
@@ -443,10 +464,10 @@ var csafFlagLabelPattern = alternativesUnmarshal(
 // machine readable flag. For example, this could be a machine readable justification
 // code why a product is not affected.
 type Flag struct {
-	Date       *string        `json:"date,omitempty"`
-	GroupIDs   *ProductGroups `json:"group_ids,omitempty"`
-	Label      *FlagLabel     `json:"label"` // required
-	ProductIds *Products      `json:"product_ids,omitempty"`
+	Date       *string          `json:"date,omitempty"`
+	GroupIDs   *ProductGroupIDs `json:"group_ids,omitempty"`
+	Label      *FlagLabel       `json:"label"` // required
+	ProductIds *Products        `json:"product_ids,omitempty"`
 }
 
 // Flags is a list if Flag elements.
@@ -542,12 +563,16 @@ type ProductStatus struct {
 type RemediationCategory string
 
 const (
+	// CSAFRemediationCategoryFixPlanned is the "fix_planned" category of CSAF 2.1.
+	CSAFRemediationCategoryFixPlanned RemediationCategory = "fix_planned"
 	// CSAFRemediationCategoryMitigation is the "mitigation" category.
 	CSAFRemediationCategoryMitigation RemediationCategory = "mitigation"
 	// CSAFRemediationCategoryNoFixPlanned is the "no_fix_planned" category.
 	CSAFRemediationCategoryNoFixPlanned RemediationCategory = "no_fix_planned"
 	// CSAFRemediationCategoryNoneAvailable is the "none_available" category.
 	CSAFRemediationCategoryNoneAvailable RemediationCategory = "none_available"
+	// CSAFRemediationCategoryOptionalPatch is the "optional_patch" category of CSAF 2.1.
+	CSAFRemediationCategoryOptionalPatch RemediationCategory = "optional_patch"
 	// CSAFRemediationCategoryVendorFix is the "vendor_fix" category.
 	CSAFRemediationCategoryVendorFix RemediationCategory = "vendor_fix"
 	// CSAFRemediationCategoryWorkaround is the "workaround" category.
@@ -555,9 +580,11 @@ const (
 )
 
 var csafRemediationCategoryPattern = alternativesUnmarshal(
+	string(CSAFRemediationCategoryFixPlanned),
 	string(CSAFRemediationCategoryMitigation),
 	string(CSAFRemediationCategoryNoFixPlanned),
 	string(CSAFRemediationCategoryNoneAvailable),
+	string(CSAFRemediationCategoryOptionalPatch),
 	string(CSAFRemediationCategoryVendorFix),
 	string(CSAFRemediationCategoryWorkaround))
 
@@ -609,7 +636,7 @@ type Remediation struct {
 	Date            *string              `json:"date,omitempty"`
 	Details         *string              `json:"details"` // required
 	Entitlements    []*string            `json:"entitlements,omitempty"`
-	GroupIds        *ProductGroups       `json:"group_ids,omitempty"`
+	GroupIds        *ProductGroupIDs     `json:"group_ids,omitempty"`
 	ProductIds      *Products            `json:"product_ids,omitempty"`
 	RestartRequired *RestartRequired     `json:"restart_required,omitempty"`
 	URL             *string              `json:"url,omitempty"`
@@ -651,6 +678,19 @@ type CVSS3VectorString string
 // and CVSS 3.1 since the only difference is the number directly after the first dot.
 var cvss3VectorStringPattern = patternUnmarshal(`^CVSS:3[.][01]/((AV:[NALP]|AC:[LH]|PR:[NLH]|UI:[NR]|S:[UC]|[CIA]:[NLH]|E:[XUPFH]|RL:[XOTWU]|RC:[XURC]|[CIA]R:[XLMH]|MAV:[XNALP]|MAC:[XLH]|MPR:[XNLH]|MUI:[XNR]|MS:[XUC]|M[CIA]:[XNLH])/)*(AV:[NALP]|AC:[LH]|PR:[NLH]|UI:[NR]|S:[UC]|[CIA]:[NLH]|E:[XUPFH]|RL:[XOTWU]|RC:[XURC]|[CIA]R:[XLMH]|MAV:[XNALP]|MAC:[XLH]|MPR:[XNLH]|MUI:[XNR]|MS:[XUC]|M[CIA]:[XNLH])$`)
 
+// CVSSVersion4 is the version of a CVSS4 item.
+type CVSSVersion4 string
+
+// CVSSVersion40 is version 4.0 of a CVSS4 item.
+const CVSSVersion40 CVSSVersion4 = "4.0"
+
+var cvss4VersionPattern = alternativesUnmarshal(string(CVSSVersion40))
+
+// CVSS4VectorString is the VectorString of a CVSS4 item with version 4.0.
+type CVSS4VectorString string
+
+var cvss4VectorStringPattern = patternUnmarshal(`^CVSS:4[.]0/AV:[NALP]/AC:[LH]/AT:[NP]/PR:[NLH]/UI:[NPA]/VC:[HLN]/VI:[HLN]/VA:[HLN]/SC:[HLN]/SI:[HLN]/SA:[HLN](/E:[XAPU])?(/CR:[XHML])?(/IR:[XHML])?(/AR:[XHML])?(/MAV:[XNALP])?(/MAC:[XLH])?(/MAT:[XNP])?(/MPR:[XNLH])?(/MUI:[XNPA])?(/MVC:[XNLH])?(/MVI:[XNLH])?(/MVA:[XNLH])?(/MSC:[XNLH])?(/MSI:[XNLHS])?(/MSA:[XNLHS])?(/S:[XNP])?(/AU:[XNY])?(/R:[XAUI])?(/V:[XDC])?(/RE:[XLMH])?(/U:(X|Clear|Green|Amber|Red))?$`)
+
 // CVSS2 holding a CVSS v2.0 value
 type CVSS2 struct {
 	Version                    *CVSSVersion2                    `json:"version"`      // required
@@ -708,17 +748,101 @@ type CVSS3 struct {
 	EnvironmentalSeverity         *CVSS3Severity                   `json:"environmentalSeverity,omitempty"`
 }
 
+// CVSS4 holding a CVSS v4.0 value
+type CVSS4 struct {
+	Version                           *CVSSVersion4                      `json:"version"`      // required
+	VectorString                      *CVSS4VectorString                 `json:"vectorString"` // required
+	AttackVector                      *CVSS40AttackVector                `json:"attackVector,omitempty"`
+	AttackComplexity                  *CVSS40AttackComplexity            `json:"attackComplexity,omitempty"`
+	AttackRequirements                *CVSS40AttackRequirements          `json:"attackRequirements,omitempty"`
+	PrivilegesRequired                *CVSS40PrivilegesRequired          `json:"privilegesRequired,omitempty"`
+	UserInteraction                   *CVSS40UserInteraction             `json:"userInteraction,omitempty"`
+	VulnConfidentialityImpact         *CVSS40VulnCia                     `json:"vulnConfidentialityImpact,omitempty"`
+	VulnIntegrityImpact               *CVSS40VulnCia                     `json:"vulnIntegrityImpact,omitempty"`
+	VulnAvailabilityImpact            *CVSS40VulnCia                     `json:"vulnAvailabilityImpact,omitempty"`
+	SubConfidentialityImpact          *CVSS40SubCia                      `json:"subConfidentialityImpact,omitempty"`
+	SubIntegrityImpact                *CVSS40SubCia                      `json:"subIntegrityImpact,omitempty"`
+	SubAvailabilityImpact             *CVSS40SubCia                      `json:"subAvailabilityImpact,omitempty"`
+	ExploitMaturity                   *CVSS40ExploitMaturity             `json:"exploitMaturity,omitempty"`
+	ConfidentialityRequirement        *CVSS40CiaRequirement              `json:"confidentialityRequirement,omitempty"`
+	IntegrityRequirement              *CVSS40CiaRequirement              `json:"integrityRequirement,omitempty"`
+	AvailabilityRequirement           *CVSS40CiaRequirement              `json:"availabilityRequirement,omitempty"`
+	ModifiedAttackVector              *CVSS40ModifiedAttackVector        `json:"modifiedAttackVector,omitempty"`
+	ModifiedAttackComplexity          *CVSS40ModifiedAttackComplexity    `json:"modifiedAttackComplexity,omitempty"`
+	ModifiedAttackRequirements        *CVSS40ModifiedAttackRequirements  `json:"modifiedAttackRequirements,omitempty"`
+	ModifiedPrivilegesRequired        *CVSS40ModifiedPrivilegesRequired  `json:"modifiedPrivilegesRequired,omitempty"`
+	ModifiedUserInteraction           *CVSS40ModifiedUserInteraction     `json:"modifiedUserInteraction,omitempty"`
+	ModifiedVulnConfidentialityImpact *CVSS40ModifiedVulnCia             `json:"modifiedVulnConfidentialityImpact,omitempty"`
+	ModifiedVulnIntegrityImpact       *CVSS40ModifiedVulnCia             `json:"modifiedVulnIntegrityImpact,omitempty"`
+	ModifiedVulnAvailabilityImpact    *CVSS40ModifiedVulnCia             `json:"modifiedVulnAvailabilityImpact,omitempty"`
+	ModifiedSubConfidentialityImpact  *CVSS40ModifiedSubC                `json:"modifiedSubConfidentialityImpact,omitempty"`
+	ModifiedSubIntegrityImpact        *CVSS40ModifiedSubIa               `json:"modifiedSubIntegrityImpact,omitempty"`
+	ModifiedSubAvailabilityImpact     *CVSS40ModifiedSubIa               `json:"modifiedSubAvailabilityImpact,omitempty"`
+	Safety                            *CVSS40Safety                      `json:"Safety,omitempty"`
+	Automatable                       *CVSS40Automatable                 `json:"Automatable,omitempty"`
+	Recovery                          *CVSS40Recovery                    `json:"Recovery,omitempty"`
+	ValueDensity                      *CVSS40ValueDensity                `json:"valueDensity,omitempty"`
+	VulnerabilityResponseEffort       *CVSS40VulnerabilityResponseEffort `json:"vulnerabilityResponseEffort,omitempty"`
+	ProviderUrgency                   *CVSS40ProviderUrgency             `json:"providerUrgency,omitempty"`
+	BaseScore                         *float64                           `json:"baseScore"`    // required
+	BaseSeverity                      *CVSS40Severity                    `json:"baseSeverity"` // required
+	ThreatScore                       *float64                           `json:"threatScore,omitempty"`
+	ThreatSeverity                    *CVSS40Severity                    `json:"threatSeverity,omitempty"`
+	EnvironmentalScore                *float64                           `json:"environmentalScore,omitempty"`
+	EnvironmentalSeverity             *CVSS40Severity                    `json:"environmentalSeverity,omitempty"`
+}
+
 // Score specifies information about (at least one) score of the vulnerability and for which
 // products the given value applies. A Score item has at least 2 properties.
 type Score struct {
 	CVSS2    *CVSS2    `json:"cvss_v2,omitempty"`
 	CVSS3    *CVSS3    `json:"cvss_v3,omitempty"`
+	CVSS4    *CVSS4    `json:"cvss_v4,omitempty"`
 	Products *Products `json:"products"` // required
 }
 
 // Scores is a list of Score elements.
 type Scores []*Score
 
+// EPSS holds the Exploit Prediction Scoring System data of a metric (CSAF 2.1).
+type EPSS struct {
+	Percentile  *string `json:"percentile"`  // required
+	Probability *string `json:"probability"` // required
+	Timestamp   *string `json:"timestamp"`   // required
+}
+
+// MetricContent holds the (at least one) metric or score of a vulnerability (CSAF 2.1).
+type MetricContent struct {
+	CVSS2  *CVSS2          `json:"cvss_v2,omitempty"`
+	CVSS3  *CVSS3          `json:"cvss_v3,omitempty"`
+	CVSS4  *CVSS4          `json:"cvss_v4,omitempty"`
+	EPSS   *EPSS           `json:"epss,omitempty"`
+	SSVCv1 json.RawMessage `json:"ssvc_v1,omitempty"`
+}
+
+// Metric specifies a metric of the vulnerability and for which
+// products the given value applies. It replaces Score in CSAF 2.1.
+type Metric struct {
+	Content  *MetricContent `json:"content"`  // required
+	Products *Products      `json:"products"` // required
+	Source   *string        `json:"source,omitempty"`
+}
+
+// Metrics is a list of Metric elements.
+type Metrics []*Metric
+
+// FirstKnownExploitationDate tells when a vulnerability was first
+// known to be exploited in the specified products (CSAF 2.1).
+type FirstKnownExploitationDate struct {
+	Date             *string          `json:"date"`              // required
+	ExploitationDate *string          `json:"exploitation_date"` // required
+	GroupIDs         *ProductGroupIDs `json:"group_ids,omitempty"`
+	ProductIDs       *Products        `json:"product_ids,omitempty"`
+}
+
+// FirstKnownExploitationDates is a list of FirstKnownExploitationDate elements.
+type FirstKnownExploitationDates []*FirstKnownExploitationDate
+
 // ThreatCategory is the category of a threat.
 type ThreatCategory string
 
@@ -738,11 +862,11 @@ var csafThreatCategoryPattern = alternativesUnmarshal(
 
 // Threat contains information about a vulnerability that can change with time.
 type Threat struct {
-	Category   *ThreatCategory `json:"category"` // required
-	Date       *string         `json:"date,omitempty"`
-	Details    *string         `json:"details"` // required
-	GroupIds   *ProductGroups  `json:"group_ids,omitempty"`
-	ProductIds *Products       `json:"product_ids,omitempty"`
+	Category   *ThreatCategory  `json:"category"` // required
+	Date       *string          `json:"date,omitempty"`
+	Details    *string          `json:"details"` // required
+	GroupIds   *ProductGroupIDs `json:"group_ids,omitempty"`
+	ProductIds *Products        `json:"product_ids,omitempty"`
 }
 
 // Threats is a list of Threat elements.
@@ -755,22 +879,28 @@ type Notes []*Note
 type References []*Reference
 
 // Vulnerability contains all fields that are related to a single vulnerability in the document.
+// The fields CWEs, DisclosureDate, FirstKnownExploitationDates and
+// Metrics are used by CSAF 2.1 instead of CWE, ReleaseDate and Scores.
 type Vulnerability struct {
-	Acknowledgements Acknowledgements `json:"acknowledgements,omitempty"`
-	CVE              *CVE             `json:"cve,omitempty"`
-	CWE              *CWE             `json:"cwe,omitempty"`
-	DiscoveryDate    *string          `json:"discovery_date,omitempty"`
-	Flags            Flags            `json:"flags,omitempty"`
-	IDs              VulnerabilityIDs `json:"ids,omitempty"` // unique ID elements
-	Involvements     Involvements     `json:"involvements,omitempty"`
-	Notes            Notes            `json:"notes,omitempty"`
-	ProductStatus    *ProductStatus   `json:"product_status,omitempty"`
-	References       References       `json:"references,omitempty"`
-	ReleaseDate      *string          `json:"release_date,omitempty"`
-	Remediations     Remediations     `json:"remediations,omitempty"`
-	Scores           Scores           `json:"scores,omitempty"`
-	Threats          Threats          `json:"threats,omitempty"`
-	Title            *string          `json:"title,omitempty"`
+	Acknowledgements            Acknowledgements            `json:"acknowledgements,omitempty"`
+	CVE                         *CVE                        `json:"cve,omitempty"`
+	CWE                         *CWE                        `json:"cwe,omitempty"`
+	CWEs                        CWEs                        `json:"cwes,omitempty"` // unique elements
+	DisclosureDate              *string                     `json:"disclosure_date,omitempty"`
+	DiscoveryDate               *string                     `json:"discovery_date,omitempty"`
+	FirstKnownExploitationDates FirstKnownExploitationDates `json:"first_known_exploitation_dates,omitempty"`
+	Flags                       Flags                       `json:"flags,omitempty"`
+	IDs                         VulnerabilityIDs            `json:"ids,omitempty"` // unique ID elements
+	Involvements                Involvements                `json:"involvements,omitempty"`
+	Metrics                     Metrics                     `json:"metrics,omitempty"`
+	Notes                       Notes                       `json:"notes,omitempty"`
+	ProductStatus               *ProductStatus              `json:"product_status,omitempty"`
+	References                  References                  `json:"references,omitempty"`
+	ReleaseDate                 *string                     `json:"release_date,omitempty"`
+	Remediations                Remediations                `json:"remediations,omitempty"`
+	Scores                      Scores                      `json:"scores,omitempty"`
+	Threats                     Threats                     `json:"threats,omitempty"`
+	Title                       *string                     `json:"title,omitempty"`
 }
 
 // Vulnerabilities is a list of Vulnerability
@@ -778,7 +908,8 @@ type Vulnerabilities []*Vulnerability
 
 // Advisory represents a CSAF advisory.
 type Advisory struct {
-	Document        *Document       `json:"document"` // required
+	Schema          *string         `json:"$schema,omitempty"` // required since CSAF 2.1
+	Document        *Document       `json:"document"`          // required
 	ProductTree     *ProductTree    `json:"product_tree,omitempty"`
 	Vulnerabilities Vulnerabilities `json:"vulnerabilities,omitempty"`
 }
@@ -793,9 +924,12 @@ func (as *AggregateSeverity) Validate() error {
 
 // Validate validates a DocumentDistribution.
 func (dd *DocumentDistribution) Validate() error {
-	if dd.Text == nil && dd.TLP == nil {
+	if dd.Text == nil && dd.TLP == nil && dd.SharingGroup == nil {
 		return errors.New("needs at least properties 'text' or 'tlp'")
 	}
+	if dd.SharingGroup != nil && dd.SharingGroup.ID == nil {
+		return errors.New("'sharing_group' is invalid: 'id' is missing")
+	}
 	return nil
 }
 
@@ -1079,6 +1213,27 @@ func (b *Branch) Validate() error {
 	return b.Branches.Validate()
 }
 
+// Validate validates a single ProductGroup.
+func (pg *ProductGroup) Validate() error {
+	switch {
+	case pg.GroupID == nil:
+		return errors.New("'group_id' is missing")
+	case pg.ProductIDs == nil:
+		return errors.New("'product_ids' is missing")
+	}
+	return nil
+}
+
+// Validate validates a list of ProductGroup elements.
+func (pgs ProductGroups) Validate() error {
+	for i, pg := range pgs {
+		if err := pg.Validate(); err != nil {
+			return fmt.Errorf("%d. product group is invalid: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
 // Validate validates a single Relationship.
 func (r *Relationship) Validate() error {
 	switch {
@@ -1127,6 +1282,9 @@ func (pt *ProductTree) Validate() error {
 			return fmt.Errorf("'full_product_names is invalid: %w", err)
 		}
 	}
+	if err := pt.ProductGroups.Validate(); err != nil {
+		return fmt.Errorf("'product_groups' is invalid: %w", err)
+	}
 	if pt.RelationShips != nil {
 		if err := pt.RelationShips.Validate(); err != nil {
 			return fmt.Errorf("'relationships' is invalid: %w", err)
@@ -1242,6 +1400,21 @@ func (c *CVSS3) Validate() error {
 	return nil
 }
 
+// Validate validates a CVSS4
+func (c *CVSS4) Validate() error {
+	switch {
+	case c.Version == nil:
+		return errors.New("'version' is missing")
+	case c.VectorString == nil:
+		return errors.New("'vectorString' is missing")
+	case c.BaseScore == nil:
+		return errors.New("'baseScore' is missing")
+	case c.BaseSeverity == nil:
+		return errors.New("'baseSeverity' is missing")
+	}
+	return nil
+}
+
 // Validate validates a single Score.
 func (s *Score) Validate() error {
 	if s.Products == nil {
@@ -1257,6 +1430,11 @@ func (s *Score) Validate() error {
 			return fmt.Errorf("'cvss_v3' is invalid: %w", err)
 		}
 	}
+	if s.CVSS4 != nil {
+		if err := s.CVSS4.Validate(); err != nil {
+			return fmt.Errorf("'cvss_v4' is invalid: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -1270,6 +1448,100 @@ func (ss Scores) Validate() error {
 	return nil
 }
 
+// Validate validates a single EPSS.
+func (e *EPSS) Validate() error {
+	switch {
+	case e.Percentile == nil:
+		return errors.New("'percentile' is missing")
+	case e.Probability == nil:
+		return errors.New("'probability' is missing")
+	case e.Timestamp == nil:
+		return errors.New("'timestamp' is missing")
+	}
+	return nil
+}
+
+// Score returns the CVSS part of the metric as a Score.
+func (m *Metric) Score() *Score {
+	s := &Score{Products: m.Products}
+	if m.Content != nil {
+		s.CVSS2 = m.Content.CVSS2
+		s.CVSS3 = m.Content.CVSS3
+		s.CVSS4 = m.Content.CVSS4
+	}
+	return s
+}
+
+// Validate validates a single Metric.
+func (m *Metric) Validate() error {
+	switch {
+	case m.Content == nil:
+		return errors.New("'content' is missing")
+	case m.Products == nil:
+		return errors.New("'products' is missing")
+	}
+	if err := m.Score().Validate(); err != nil {
+		return fmt.Errorf("'content' is invalid: %w", err)
+	}
+	if m.Content.EPSS != nil {
+		if err := m.Content.EPSS.Validate(); err != nil {
+			return fmt.Errorf("'epss' is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+// Validate validates a list of Metric elements.
+func (ms Metrics) Validate() error {
+	for i, m := range ms {
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("%d. metric is invalid: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Scores returns the CVSS parts of the metrics as scores.
+func (ms Metrics) Scores() Scores {
+	ss := make(Scores, 0, len(ms))
+	for _, m := range ms {
+		if m != nil {
+			ss = append(ss, m.Score())
+		}
+	}
+	return ss
+}
+
+// AllScores returns the scores of the vulnerability followed by
+// the CVSS parts of the CSAF 2.1 metrics as scores.
+func (v *Vulnerability) AllScores() Scores {
+	if len(v.Metrics) == 0 {
+		return v.Scores
+	}
+	return append(append(Scores{}, v.Scores...), v.Metrics.Scores()...)
+}
+
+// Validate validates a single FirstKnownExploitationDate.
+func (f *FirstKnownExploitationDate) Validate() error {
+	switch {
+	case f.Date == nil:
+		return errors.New("'date' is missing")
+	case f.ExploitationDate == nil:
+		return errors.New("'exploitation_date' is missing")
+	}
+	return nil
+}
+
+// Validate validates a list of FirstKnownExploitationDate elements.
+func (fs FirstKnownExploitationDates) Validate() error {
+	for i, f := range fs {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("%d. first known exploitation date is invalid: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
 // Validate validates a single Remediation.
 func (r *Remediation) Validate() error {
 	switch {
@@ -1324,6 +1596,14 @@ func (v *Vulnerability) Validate() error {
 			return fmt.Errorf("'cwe' is invalid: %w", err)
 		}
 	}
+	for i, cwe := range v.CWEs {
+		if err := cwe.Validate(); err != nil {
+			return fmt.Errorf("%d. cwe is invalid: %w", i+1, err)
+		}
+	}
+	if err := v.FirstKnownExploitationDates.Validate(); err != nil {
+		return fmt.Errorf("'first_known_exploitation_dates' is invalid: %w", err)
+	}
 	if err := v.Flags.Validate(); err != nil {
 		return fmt.Errorf("'flags' is invalid: %w", err)
 	}
@@ -1342,6 +1622,9 @@ func (v *Vulnerability) Validate() error {
 	if err := v.Remediations.Validate(); err != nil {
 		return fmt.Errorf("'remediations' is invalid: %w", err)
 	}
+	if err := v.Metrics.Validate(); err != nil {
+		return fmt.Errorf("'metrics' is invalid: %w", err)
+	}
 	if err := v.Scores.Validate(); err != nil {
 		return fmt.Errorf("'scores' is invalid: %w", err)
 	}
@@ -1370,6 +1653,9 @@ func (adv *Advisory) Validate() error {
 	if err := adv.Document.Validate(); err != nil {
 		return fmt.Errorf("'document' is invalid: %w", err)
 	}
+	if adv.Schema == nil && *adv.Document.CSAFVersion == CSAFVersion21 {
+		return errors.New("'$schema' is missing")
+	}
 	if adv.ProductTree != nil {
 		if err := adv.ProductTree.Validate(); err != nil {
 			return fmt.Errorf("'product_tree' is invalid: %w", err)
@@ -1644,3 +1930,21 @@ func (cvs *CVSS3VectorString) UnmarshalText(data []byte) error {
 	}
 	return err
 }
+
+// UnmarshalText implements the encoding.TextUnmarshaller interface.
+func (cv *CVSSVersion4) UnmarshalText(data []byte) error {
+	s, err := cvss4VersionPattern(data)
+	if err == nil {
+		*cv = CVSSVersion4(s)
+	}
+	return err
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaller interface.
+func (cvs *CVSS4VectorString) UnmarshalText(data []byte) error {
+	s, err := cvss4VectorStringPattern(data)
+	if err == nil {
+		*cvs = CVSS4VectorString(s)
+	}
+	return err
+}
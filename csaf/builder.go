@@ -0,0 +1,414 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// ptr returns a pointer to a copy of v.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// AdvisoryBuilder assembles an advisory programmatically.
+// Product and group ids are generated and the tracking information
+// is maintained by Bump.
+type AdvisoryBuilder struct {
+	adv       *Advisory
+	productID int
+	groupID   int
+}
+
+// VulnerabilityBuilder adds the details of a single vulnerability.
+type VulnerabilityBuilder struct {
+	v *Vulnerability
+}
+
+// NewAdvisoryBuilder returns a builder for a new advisory with the
+// given tracking id and title. The document category defaults to
+// "csaf_base" and the tracking status to "final".
+func NewAdvisoryBuilder(id TrackingID, title string) *AdvisoryBuilder {
+	return &AdvisoryBuilder{
+		adv: &Advisory{
+			Document: &Document{
+				Category:    ptr(DocumentCategory(profileBase)),
+				CSAFVersion: ptr(CSAFVersion20),
+				Title:       ptr(title),
+				Tracking: &Tracking{
+					ID:              ptr(id),
+					RevisionHistory: Revisions{},
+					Status:          ptr(CSAFTrackingStatusFinal),
+				},
+			},
+		},
+	}
+}
+
+// Category sets the category of the document.
+func (ab *AdvisoryBuilder) Category(category DocumentCategory) *AdvisoryBuilder {
+	ab.adv.Document.Category = &category
+	return ab
+}
+
+// Lang sets the language of the document.
+func (ab *AdvisoryBuilder) Lang(lang Lang) *AdvisoryBuilder {
+	ab.adv.Document.Lang = &lang
+	return ab
+}
+
+// Status sets the tracking status of the document.
+func (ab *AdvisoryBuilder) Status(status TrackingStatus) *AdvisoryBuilder {
+	ab.adv.Document.Tracking.Status = &status
+	return ab
+}
+
+// Publisher sets the publisher of the document.
+func (ab *AdvisoryBuilder) Publisher(category Category, name, namespace string) *AdvisoryBuilder {
+	ab.adv.Document.Publisher = &DocumentPublisher{
+		Category:  &category,
+		Name:      &name,
+		Namespace: &namespace,
+	}
+	return ab
+}
+
+// TLP sets the TLP label of the document distribution.
+func (ab *AdvisoryBuilder) TLP(label TLPLabel) *AdvisoryBuilder {
+	ab.adv.Document.Distribution = &DocumentDistribution{
+		TLP: &TLP{DocumentTLPLabel: &label},
+	}
+	return ab
+}
+
+// Note adds a note to the document. An empty title is omitted.
+func (ab *AdvisoryBuilder) Note(category NoteCategory, title, text string) *AdvisoryBuilder {
+	ab.adv.Document.Notes = append(ab.adv.Document.Notes, newNote(category, title, text))
+	return ab
+}
+
+// Reference adds a reference to the document.
+func (ab *AdvisoryBuilder) Reference(category ReferenceCategory, summary, url string) *AdvisoryBuilder {
+	ab.adv.Document.References = append(ab.adv.Document.References, &Reference{
+		ReferenceCategory: ptr(string(category)),
+		Summary:           &summary,
+		URL:               &url,
+	})
+	return ab
+}
+
+// newNote creates a note. An empty title is omitted.
+func newNote(category NoteCategory, title, text string) *Note {
+	n := &Note{NoteCategory: &category, Text: &text}
+	if title != "" {
+		n.Title = &title
+	}
+	return n
+}
+
+// productTree returns the product tree and creates it if needed.
+func (ab *AdvisoryBuilder) productTree() *ProductTree {
+	if ab.adv.ProductTree == nil {
+		ab.adv.ProductTree = &ProductTree{}
+	}
+	return ab.adv.ProductTree
+}
+
+// Product adds a product with the given full name at the end of the
+// given branch path and returns its generated product id.
+// Branches with the same category and name are shared between products.
+// If there is already a product at the end of the path its id is returned.
+// helper may be nil.
+func (ab *AdvisoryBuilder) Product(
+	name string,
+	helper *ProductIdentificationHelper,
+	path ...BranchPathElement,
+) (ProductID, error) {
+	if len(path) == 0 {
+		return "", errors.New("product needs at least one branch")
+	}
+	pt := ab.productTree()
+	branches := &pt.Branches
+	var leaf *Branch
+	for _, elem := range path {
+		leaf = nil
+		for _, b := range *branches {
+			if b != nil && b.Category != nil && *b.Category == elem.Category &&
+				b.Name != nil && *b.Name == elem.Name {
+				leaf = b
+				break
+			}
+		}
+		if leaf == nil {
+			leaf = &Branch{Category: ptr(elem.Category), Name: ptr(elem.Name)}
+			*branches = append(*branches, leaf)
+		}
+		branches = &leaf.Branches
+	}
+	if leaf.Product != nil && leaf.Product.ProductID != nil {
+		return *leaf.Product.ProductID, nil
+	}
+	if len(leaf.Branches) > 0 {
+		return "", fmt.Errorf("branch %q already has sub branches", *leaf.Name)
+	}
+	ab.productID++
+	id := ProductID(fmt.Sprintf("CSAFPID-%04d", ab.productID))
+	leaf.Product = &FullProductName{
+		Name:                        &name,
+		ProductID:                   &id,
+		ProductIdentificationHelper: helper,
+	}
+	return id, nil
+}
+
+// ProductGroup adds a group of the given products and
+// returns its generated group id. An empty summary is omitted.
+func (ab *AdvisoryBuilder) ProductGroup(summary string, ids ...ProductID) ProductGroupID {
+	pt := ab.productTree()
+	ab.groupID++
+	gid := ProductGroupID(fmt.Sprintf("CSAFGID-%04d", ab.groupID))
+	pg := &ProductGroup{GroupID: &gid, ProductIDs: newProducts(ids)}
+	if summary != "" {
+		pg.Summary = &summary
+	}
+	pt.ProductGroups = append(pt.ProductGroups, pg)
+	return gid
+}
+
+// newProducts creates a list of products from the given ids.
+func newProducts(ids []ProductID) *Products {
+	products := make(Products, 0, len(ids))
+	for _, id := range ids {
+		products = append(products, ptr(id))
+	}
+	return &products
+}
+
+// Vulnerability adds a vulnerability and returns a builder for it.
+// An empty cve or title is omitted.
+func (ab *AdvisoryBuilder) Vulnerability(cve CVE, title string) *VulnerabilityBuilder {
+	v := &Vulnerability{}
+	if cve != "" {
+		v.CVE = &cve
+	}
+	if title != "" {
+		v.Title = &title
+	}
+	ab.adv.Vulnerabilities = append(ab.adv.Vulnerabilities, v)
+	return &VulnerabilityBuilder{v: v}
+}
+
+// Vulnerability returns the vulnerability which is built.
+func (vb *VulnerabilityBuilder) Vulnerability() *Vulnerability {
+	return vb.v
+}
+
+// CWE sets the weakness of the vulnerability.
+func (vb *VulnerabilityBuilder) CWE(id WeaknessID, name string) *VulnerabilityBuilder {
+	vb.v.CWE = &CWE{ID: &id, Name: &name}
+	return vb
+}
+
+// Note adds a note to the vulnerability. An empty title is omitted.
+func (vb *VulnerabilityBuilder) Note(category NoteCategory, title, text string) *VulnerabilityBuilder {
+	vb.v.Notes = append(vb.v.Notes, newNote(category, title, text))
+	return vb
+}
+
+// Status adds the given products to the product status list
+// of the given category. Products already in the list are skipped.
+func (vb *VulnerabilityBuilder) Status(category ProductStatusCategory, ids ...ProductID) *VulnerabilityBuilder {
+	if vb.v.ProductStatus == nil {
+		vb.v.ProductStatus = &ProductStatus{}
+	}
+	ps := vb.v.ProductStatus
+	var list **Products
+	switch category {
+	case ProductStatusFirstAffected:
+		list = &ps.FirstAffected
+	case ProductStatusFirstFixed:
+		list = &ps.FirstFixed
+	case ProductStatusFixed:
+		list = &ps.Fixed
+	case ProductStatusKnownAffected:
+		list = &ps.KnownAffected
+	case ProductStatusKnownNotAffected:
+		list = &ps.KnownNotAffected
+	case ProductStatusLastAffected:
+		list = &ps.LastAffected
+	case ProductStatusRecommended:
+		list = &ps.Recommended
+	case ProductStatusUnderInvestigation:
+		list = &ps.UnderInvestigation
+	default:
+		return vb
+	}
+	if *list == nil {
+		*list = &Products{}
+	}
+next:
+	for _, id := range ids {
+		for _, p := range **list {
+			if p != nil && *p == id {
+				continue next
+			}
+		}
+		**list = append(**list, ptr(id))
+	}
+	return vb
+}
+
+// Remediation adds a remediation for the given products.
+func (vb *VulnerabilityBuilder) Remediation(
+	category RemediationCategory,
+	details string,
+	ids ...ProductID,
+) *VulnerabilityBuilder {
+	vb.v.Remediations = append(vb.v.Remediations, &Remediation{
+		Category:   &category,
+		Details:    &details,
+		ProductIds: newProducts(ids),
+	})
+	return vb
+}
+
+// GroupRemediation adds a remediation for the given product groups.
+func (vb *VulnerabilityBuilder) GroupRemediation(
+	category RemediationCategory,
+	details string,
+	gids ...ProductGroupID,
+) *VulnerabilityBuilder {
+	groups := make(ProductGroupIDs, 0, len(gids))
+	for _, gid := range gids {
+		groups = append(groups, ptr(gid))
+	}
+	vb.v.Remediations = append(vb.v.Remediations, &Remediation{
+		Category: &category,
+		Details:  &details,
+		GroupIds: &groups,
+	})
+	return vb
+}
+
+// Flag adds a flag for the given products.
+func (vb *VulnerabilityBuilder) Flag(label FlagLabel, ids ...ProductID) *VulnerabilityBuilder {
+	vb.v.Flags = append(vb.v.Flags, &Flag{
+		Label:      &label,
+		ProductIds: newProducts(ids),
+	})
+	return vb
+}
+
+// Threat adds a threat. If no products are given the
+// threat applies to the whole vulnerability.
+func (vb *VulnerabilityBuilder) Threat(category ThreatCategory, details string, ids ...ProductID) *VulnerabilityBuilder {
+	t := &Threat{Category: &category, Details: &details}
+	if len(ids) > 0 {
+		t.ProductIds = newProducts(ids)
+	}
+	vb.v.Threats = append(vb.v.Threats, t)
+	return vb
+}
+
+// Score adds a CVSS v3 score for the given products.
+func (vb *VulnerabilityBuilder) Score(cvss3 *CVSS3, ids ...ProductID) *VulnerabilityBuilder {
+	vb.v.Scores = append(vb.v.Scores, &Score{
+		CVSS3:    cvss3,
+		Products: newProducts(ids),
+	})
+	return vb
+}
+
+// Bump adds a new revision with the given date and summary to the
+// revision history and advances the version and the current release date.
+// An integer version is incremented by one, a semantic version gets its
+// patch level incremented and loses its pre-release and build parts.
+// The first revision has the version "1" unless a version was set before.
+// The initial release date is set on the first revision.
+func (t *Tracking) Bump(date time.Time, summary string) error {
+	next := RevisionNumber("1")
+	if t.Version != nil && len(t.RevisionHistory) > 0 {
+		rn, err := parseRevisionNumber(string(*t.Version))
+		if err != nil {
+			return err
+		}
+		if rn.semantic {
+			next = RevisionNumber(fmt.Sprintf("%d.%d.%d", rn.major, rn.minor, rn.patch+1))
+		} else {
+			next = RevisionNumber(strconv.FormatUint(rn.major+1, 10))
+		}
+	} else if t.Version != nil {
+		next = *t.Version
+	}
+	d := date.UTC().Format(time.RFC3339)
+	t.RevisionHistory = append(t.RevisionHistory, &Revision{
+		Date:    &d,
+		Number:  &next,
+		Summary: &summary,
+	})
+	t.Version = &next
+	t.CurrentReleaseDate = &d
+	if t.InitialReleaseDate == nil {
+		t.InitialReleaseDate = ptr(d)
+	}
+	return nil
+}
+
+// Bump adds a new revision to the tracking of the advisory
+// and updates the generator. See Tracking.Bump for details.
+func (ab *AdvisoryBuilder) Bump(date time.Time, summary string) error {
+	t := ab.adv.Document.Tracking
+	if err := t.Bump(date, summary); err != nil {
+		return err
+	}
+	t.setGenerator()
+	return nil
+}
+
+// setGenerator marks csaf_distribution as the generator
+// of the current release of the document.
+func (t *Tracking) setGenerator() {
+	t.Generator = &Generator{
+		Date: t.CurrentReleaseDate,
+		Engine: &Engine{
+			Name:    ptr("csaf_distribution"),
+			Version: ptr(util.SemVersion),
+		},
+	}
+}
+
+// Build checks the advisory against the model and
+// the JSON schema and returns it if it is valid.
+// The builder should not be used afterwards.
+func (ab *AdvisoryBuilder) Build() (*Advisory, error) {
+	adv := ab.adv
+	if len(adv.Document.Tracking.RevisionHistory) == 0 {
+		return nil, errors.New("advisory has no revision")
+	}
+	if err := adv.Validate(); err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := util.ReMarshalJSON(&doc, adv); err != nil {
+		return nil, err
+	}
+	errs, err := ValidateCSAF(doc)
+	if err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("advisory is not schema valid: %v", errs)
+	}
+	return adv, nil
+}
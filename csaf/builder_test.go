@@ -0,0 +1,140 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdvisoryBuilder(t *testing.T) {
+	ab := NewAdvisoryBuilder("EXAMPLE-2023-0001", "Example advisory").
+		Category(profileSecurityAdvisory).
+		Lang("en").
+		Publisher(CSAFCategoryVendor, "Example Company", "https://example.com").
+		TLP(TLPLabelWhite).
+		Note(CSAFNoteCategorySummary, "Summary", "A buffer overflow.").
+		Reference(CSAFReferenceCategorySelf, "Self", "https://example.com/advisories/0001.json")
+
+	vendor := BranchPathElement{Category: CSAFBranchCategoryVendor, Name: "Example Company"}
+	product := BranchPathElement{Category: CSAFBranchCategoryProductName, Name: "Product"}
+
+	v10, err := ab.Product("Example Company Product 1.0", nil, vendor, product,
+		BranchPathElement{Category: CSAFBranchCategoryProductVersion, Name: "1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v11, err := ab.Product("Example Company Product 1.1", nil, vendor, product,
+		BranchPathElement{Category: CSAFBranchCategoryProductVersion, Name: "1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v10 == v11 {
+		t.Fatalf("expected different product ids, got %q", v10)
+	}
+	again, err := ab.Product("Example Company Product 1.0", nil, vendor, product,
+		BranchPathElement{Category: CSAFBranchCategoryProductVersion, Name: "1.0"})
+	if err != nil || again != v10 {
+		t.Errorf("expected existing product %q, got %q (%v)", v10, again, err)
+	}
+	if _, err := ab.Product("Example Company Product", nil, vendor, product); err == nil {
+		t.Error("expected product on branch with sub branches to fail")
+	}
+
+	gid := ab.ProductGroup("All versions", v10, v11)
+
+	ab.Vulnerability("CVE-2023-0001", "Buffer overflow").
+		CWE("CWE-120", "Buffer Copy without Checking Size of Input ('Classic Buffer Overflow')").
+		Note(CSAFNoteCategoryDescription, "", "A buffer overflow in the parser.").
+		Status(ProductStatusKnownAffected, v10, v10).
+		Status(ProductStatusFixed, v11).
+		Remediation(CSAFRemediationCategoryVendorFix, "Update to 1.1.", v10).
+		GroupRemediation(CSAFRemediationCategoryWorkaround, "Disable the parser.", gid).
+		Threat(CSAFThreatCategoryImpact, "Remote code execution.")
+
+	if _, err := ab.Build(); err == nil {
+		t.Error("expected advisory without revision to fail")
+	}
+
+	date := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	if err := ab.Bump(date, "Initial version."); err != nil {
+		t.Fatal(err)
+	}
+	if err := ab.Bump(date.Add(24*time.Hour), "Added workaround."); err != nil {
+		t.Fatal(err)
+	}
+
+	adv, err := ab.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracking := adv.Document.Tracking
+	if *tracking.Version != "2" || len(tracking.RevisionHistory) != 2 {
+		t.Errorf("unexpected version %q with %d revisions",
+			*tracking.Version, len(tracking.RevisionHistory))
+	}
+	if *tracking.InitialReleaseDate != "2023-01-01T10:00:00Z" ||
+		*tracking.CurrentReleaseDate != "2023-01-02T10:00:00Z" {
+		t.Errorf("unexpected release dates %q %q",
+			*tracking.InitialReleaseDate, *tracking.CurrentReleaseDate)
+	}
+	if n := len(*adv.Vulnerabilities[0].ProductStatus.KnownAffected); n != 1 {
+		t.Errorf("expected one known affected product, got %d", n)
+	}
+
+	rvr, err := ValidateAdvisory(adv, "mandatory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rvr.Valid {
+		for _, test := range rvr.Tests {
+			for _, e := range test.Error {
+				t.Errorf("%s: %s: %s", test.Name, e.InstancePath, e.Message)
+			}
+		}
+	}
+
+	if s := NewStatusResolver(adv).Status(v10, "CVE-2023-0001"); len(s.Remediations) != 2 {
+		t.Errorf("expected two remediations, got %d", len(s.Remediations))
+	}
+}
+
+func TestTrackingBump(t *testing.T) {
+	date := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		version string
+		want    []RevisionNumber
+	}{
+		{"", []RevisionNumber{"1", "2", "3"}},
+		{"0.9.0-rc.1", []RevisionNumber{"0.9.0-rc.1", "0.9.1", "0.9.2"}},
+		{"1.0.0", []RevisionNumber{"1.0.0", "1.0.1", "1.0.2"}},
+	} {
+		tr := &Tracking{}
+		if tc.version != "" {
+			tr.Version = ptr(RevisionNumber(tc.version))
+		}
+		for i, want := range tc.want {
+			if err := tr.Bump(date.Add(time.Duration(i)*time.Hour), "change"); err != nil {
+				t.Fatal(err)
+			}
+			if *tr.Version != want {
+				t.Errorf("%q: %d. bump: expected %q, got %q", tc.version, i+1, want, *tr.Version)
+			}
+		}
+	}
+
+	tr := &Tracking{
+		Version:         ptr(RevisionNumber("x")),
+		RevisionHistory: Revisions{{}},
+	}
+	if err := tr.Bump(date, "change"); err == nil {
+		t.Error("expected invalid version to fail")
+	}
+}
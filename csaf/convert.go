@@ -0,0 +1,173 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// DefaultCWEVersion is the version of the CWE specification
+// assumed for the CWEs of CSAF 2.0 documents.
+const DefaultCWEVersion = "4.13"
+
+// ConversionIssue is a construct which could not
+// be converted without a loss or change of information.
+type ConversionIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String implements fmt.Stringer.
+func (ci ConversionIssue) String() string {
+	return ci.Path + ": " + ci.Message
+}
+
+// ConvertOptions configure the conversion of an advisory.
+type ConvertOptions struct {
+	// Date is the date of the revision added for the conversion.
+	// If zero no revision is added.
+	Date time.Time
+	// Summary is the summary of the revision added for the conversion.
+	Summary string
+	// CWEVersion is the version of the CWE specification assumed
+	// for the CWEs. Defaults to DefaultCWEVersion.
+	CWEVersion string
+}
+
+// converter collects the issues of a conversion.
+type converter struct {
+	opts   *ConvertOptions
+	issues []ConversionIssue
+}
+
+// report adds an issue found at the given path.
+func (c *converter) report(path, format string, args ...any) {
+	c.issues = append(c.issues, ConversionIssue{
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// ConvertToCSAF21 converts a CSAF 2.0 advisory into a CSAF 2.1 advisory.
+// The given advisory is not modified. The returned issues list
+// the constructs which could not be converted losslessly.
+// If opts is nil no revision is added and the default CWE version is used.
+func (adv *Advisory) ConvertToCSAF21(opts *ConvertOptions) (*Advisory, []ConversionIssue, error) {
+	if adv.Document == nil || adv.Document.CSAFVersion == nil {
+		return nil, nil, errors.New("advisory has no CSAF version")
+	}
+	if v := *adv.Document.CSAFVersion; v != CSAFVersion20 {
+		return nil, nil, fmt.Errorf("cannot convert CSAF version %q", v)
+	}
+	if opts == nil {
+		opts = &ConvertOptions{}
+	}
+	var out Advisory
+	if err := util.ReMarshalJSON(&out, adv); err != nil {
+		return nil, nil, fmt.Errorf("copying advisory failed: %w", err)
+	}
+	out.Schema = ptr(SchemaURL(CSAFVersion21))
+	c := converter{opts: opts}
+	c.document(out.Document)
+	if out.ProductTree != nil {
+		out.ProductTree.visitFullProductNames(c.fullProductName)
+	}
+	for i, v := range out.Vulnerabilities {
+		if v != nil {
+			c.vulnerability(v, fmt.Sprintf("/vulnerabilities/%d", i))
+		}
+	}
+	if !opts.Date.IsZero() {
+		t := out.Document.Tracking
+		if t == nil {
+			return nil, nil, errors.New("advisory has no tracking")
+		}
+		if err := t.Bump(opts.Date, opts.Summary); err != nil {
+			return nil, nil, err
+		}
+		t.setGenerator()
+	}
+	return &out, c.issues, nil
+}
+
+// document converts the document meta data.
+func (c *converter) document(doc *Document) {
+	doc.CSAFVersion = ptr(CSAFVersion21)
+	if doc.Distribution == nil || doc.Distribution.TLP == nil ||
+		doc.Distribution.TLP.DocumentTLPLabel == nil {
+		c.report("/document/distribution/tlp/label",
+			"TLP label is missing but required by CSAF 2.1")
+		return
+	}
+	const path = "/document/distribution/tlp/label"
+	label := doc.Distribution.TLP.DocumentTLPLabel
+	switch *label {
+	case TLPLabelWhite:
+		*label = TLPLabelClear
+	case TLPLabelAmber:
+		// AMBER+STRICT keeps the restrictions of TLP v1 AMBER.
+		*label = TLPLabelAmberStrict
+		c.report(path,
+			"TLP:AMBER converted to TLP:AMBER+STRICT, consider if TLP:AMBER is sufficient")
+	}
+	if url := doc.Distribution.TLP.URL; url != nil && *url != "https://www.first.org/tlp/" {
+		c.report("/document/distribution/tlp/url",
+			"TLP URL %q may describe TLP v1 labels", *url)
+	}
+}
+
+// fullProductName converts the product identification helper.
+func (c *converter) fullProductName(fpn *FullProductName, _ string) {
+	if h := fpn.ProductIdentificationHelper; h != nil && h.PURL != nil {
+		h.PURLs = append([]*PURL{h.PURL}, h.PURLs...)
+		h.PURL = nil
+	}
+}
+
+// vulnerability converts the fields of a vulnerability
+// which were renamed or changed in CSAF 2.1.
+func (c *converter) vulnerability(v *Vulnerability, path string) {
+	if v.CWE != nil {
+		cwe := v.CWE
+		if cwe.Version == nil {
+			version := c.opts.CWEVersion
+			if version == "" {
+				version = DefaultCWEVersion
+			}
+			cwe.Version = &version
+			c.report(path+"/cwe", "CWE version is unknown, assumed %s", version)
+		}
+		v.CWEs = append(CWEs{cwe}, v.CWEs...)
+		v.CWE = nil
+	}
+	if v.ReleaseDate != nil {
+		if v.DisclosureDate == nil {
+			v.DisclosureDate = v.ReleaseDate
+		}
+		v.ReleaseDate = nil
+	}
+	for _, s := range v.Scores {
+		if s == nil {
+			continue
+		}
+		v.Metrics = append(v.Metrics, &Metric{
+			Content: &MetricContent{
+				CVSS2: s.CVSS2,
+				CVSS3: s.CVSS3,
+				CVSS4: s.CVSS4,
+			},
+			Products: s.Products,
+		})
+	}
+	v.Scores = nil
+}
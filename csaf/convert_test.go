@@ -0,0 +1,101 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+func TestConvertToCSAF21(t *testing.T) {
+	adv := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		walkJSON(doc, "document", "distribution", "tlp").(map[string]any)["label"] = "AMBER"
+		walkJSON(doc, "vulnerabilities", 0).(map[string]any)["release_date"] = "2023-01-01T10:00:00.000Z"
+	})
+
+	date := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	converted, issues, err := adv.ConvertToCSAF21(&ConvertOptions{
+		Date:    date,
+		Summary: "Converted to CSAF 2.1.",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ConversionIssue{
+		{Path: "/document/distribution/tlp/label",
+			Message: "TLP:AMBER converted to TLP:AMBER+STRICT, consider if TLP:AMBER is sufficient"},
+		{Path: "/vulnerabilities/0/cwe", Message: "CWE version is unknown, assumed 4.13"},
+	}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("expected issues %v, got %v", want, issues)
+	}
+
+	if converted.Schema == nil || *converted.Schema != csaf21SchemaURL {
+		t.Errorf("expected $schema %q, got %v", csaf21SchemaURL, converted.Schema)
+	}
+
+	var doc any
+	if err := util.ReMarshalJSON(&doc, converted); err != nil {
+		t.Fatal(err)
+	}
+	if errs, err := ValidateCSAF(doc); err != nil || len(errs) > 0 {
+		t.Fatalf("converted advisory is not schema valid: %v (%v)", errs, err)
+	}
+
+	tracking := converted.Document.Tracking
+	if *tracking.Version != "3" || len(tracking.RevisionHistory) != 3 ||
+		*tracking.CurrentReleaseDate != "2023-03-01T10:00:00Z" || tracking.Generator == nil {
+		t.Errorf("unexpected tracking %+v", tracking)
+	}
+	v := converted.Vulnerabilities[0]
+	if v.CWE != nil || len(v.CWEs) != 1 || v.ReleaseDate != nil || v.DisclosureDate == nil ||
+		len(v.Scores) != 0 || len(v.Metrics) != 1 || v.Metrics[0].Content.CVSS3 == nil {
+		t.Errorf("unexpected vulnerability %+v", v)
+	}
+
+	// The original advisory is left untouched.
+	if *adv.Document.CSAFVersion != CSAFVersion20 || len(adv.Vulnerabilities[0].Scores) != 1 ||
+		len(adv.Document.Tracking.RevisionHistory) != 2 {
+		t.Error("original advisory was modified")
+	}
+
+	if _, _, err := converted.ConvertToCSAF21(nil); err == nil {
+		t.Error("expected conversion of CSAF 2.1 advisory to fail")
+	}
+
+	// Without a date no revision is added.
+	again, _, err := adv.ConvertToCSAF21(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(again.Document.Tracking.RevisionHistory); n != 2 {
+		t.Errorf("expected two revisions, got %d", n)
+	}
+}
+
+func TestConvertToCSAF21WithoutTLP(t *testing.T) {
+	adv := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		delete(walkJSON(doc, "document").(map[string]any), "distribution")
+	})
+	_, issues, err := adv.ConvertToCSAF21(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ConversionIssue{
+		Path:    "/document/distribution/tlp/label",
+		Message: "TLP label is missing but required by CSAF 2.1",
+	}
+	if len(issues) == 0 || issues[0] != want {
+		t.Errorf("expected issue %v, got %v", want, issues)
+	}
+}
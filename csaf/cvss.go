@@ -0,0 +1,608 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// cvssMetric describes a metric of a CVSS vector and
+// the property it is reflected by in the JSON representation.
+type cvssMetric struct {
+	abbrev   string
+	property string
+	values   map[string]string // vector value -> property value
+}
+
+// cvssMetrics is a list of metrics of a CVSS version.
+type cvssMetrics []cvssMetric
+
+var (
+	cvss3CIA = map[string]string{
+		"N": "NONE", "L": "LOW", "H": "HIGH",
+	}
+	cvss3ModifiedCIA = map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "L": "LOW", "H": "HIGH",
+	}
+	cvss3Requirement = map[string]string{
+		"X": "NOT_DEFINED", "L": "LOW", "M": "MEDIUM", "H": "HIGH",
+	}
+	cvss2CIA = map[string]string{
+		"N": "NONE", "P": "PARTIAL", "C": "COMPLETE",
+	}
+	cvss2Requirement = map[string]string{
+		"L": "LOW", "M": "MEDIUM", "H": "HIGH", "ND": "NOT_DEFINED",
+	}
+)
+
+// cvss3Metrics are the metrics of CVSS v3.x.
+var cvss3Metrics = cvssMetrics{
+	{"AV", "attackVector", map[string]string{
+		"N": "NETWORK", "A": "ADJACENT_NETWORK", "L": "LOCAL", "P": "PHYSICAL"}},
+	{"AC", "attackComplexity", map[string]string{
+		"L": "LOW", "H": "HIGH"}},
+	{"PR", "privilegesRequired", map[string]string{
+		"N": "NONE", "L": "LOW", "H": "HIGH"}},
+	{"UI", "userInteraction", map[string]string{
+		"N": "NONE", "R": "REQUIRED"}},
+	{"S", "scope", map[string]string{
+		"U": "UNCHANGED", "C": "CHANGED"}},
+	{"C", "confidentialityImpact", cvss3CIA},
+	{"I", "integrityImpact", cvss3CIA},
+	{"A", "availabilityImpact", cvss3CIA},
+	{"E", "exploitCodeMaturity", map[string]string{
+		"X": "NOT_DEFINED", "U": "UNPROVEN", "P": "PROOF_OF_CONCEPT",
+		"F": "FUNCTIONAL", "H": "HIGH"}},
+	{"RL", "remediationLevel", map[string]string{
+		"X": "NOT_DEFINED", "O": "OFFICIAL_FIX", "T": "TEMPORARY_FIX",
+		"W": "WORKAROUND", "U": "UNAVAILABLE"}},
+	{"RC", "reportConfidence", map[string]string{
+		"X": "NOT_DEFINED", "U": "UNKNOWN", "R": "REASONABLE", "C": "CONFIRMED"}},
+	{"CR", "confidentialityRequirement", cvss3Requirement},
+	{"IR", "integrityRequirement", cvss3Requirement},
+	{"AR", "availabilityRequirement", cvss3Requirement},
+	{"MAV", "modifiedAttackVector", map[string]string{
+		"X": "NOT_DEFINED", "N": "NETWORK", "A": "ADJACENT_NETWORK",
+		"L": "LOCAL", "P": "PHYSICAL"}},
+	{"MAC", "modifiedAttackComplexity", map[string]string{
+		"X": "NOT_DEFINED", "L": "LOW", "H": "HIGH"}},
+	{"MPR", "modifiedPrivilegesRequired", map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "L": "LOW", "H": "HIGH"}},
+	{"MUI", "modifiedUserInteraction", map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "R": "REQUIRED"}},
+	{"MS", "modifiedScope", map[string]string{
+		"X": "NOT_DEFINED", "U": "UNCHANGED", "C": "CHANGED"}},
+	{"MC", "modifiedConfidentialityImpact", cvss3ModifiedCIA},
+	{"MI", "modifiedIntegrityImpact", cvss3ModifiedCIA},
+	{"MA", "modifiedAvailabilityImpact", cvss3ModifiedCIA},
+}
+
+// cvss2Metrics are the metrics of CVSS v2.0.
+var cvss2Metrics = cvssMetrics{
+	{"AV", "accessVector", map[string]string{
+		"L": "LOCAL", "A": "ADJACENT_NETWORK", "N": "NETWORK"}},
+	{"AC", "accessComplexity", map[string]string{
+		"H": "HIGH", "M": "MEDIUM", "L": "LOW"}},
+	{"Au", "authentication", map[string]string{
+		"M": "MULTIPLE", "S": "SINGLE", "N": "NONE"}},
+	{"C", "confidentialityImpact", cvss2CIA},
+	{"I", "integrityImpact", cvss2CIA},
+	{"A", "availabilityImpact", cvss2CIA},
+	{"E", "exploitability", map[string]string{
+		"U": "UNPROVEN", "POC": "PROOF_OF_CONCEPT", "F": "FUNCTIONAL",
+		"H": "HIGH", "ND": "NOT_DEFINED"}},
+	{"RL", "remediationLevel", map[string]string{
+		"OF": "OFFICIAL_FIX", "TF": "TEMPORARY_FIX", "W": "WORKAROUND",
+		"U": "UNAVAILABLE", "ND": "NOT_DEFINED"}},
+	{"RC", "reportConfidence", map[string]string{
+		"UC": "UNCONFIRMED", "UR": "UNCORROBORATED", "C": "CONFIRMED",
+		"ND": "NOT_DEFINED"}},
+	{"CDP", "collateralDamagePotential", map[string]string{
+		"N": "NONE", "L": "LOW", "LM": "LOW_MEDIUM", "MH": "MEDIUM_HIGH",
+		"H": "HIGH", "ND": "NOT_DEFINED"}},
+	{"TD", "targetDistribution", map[string]string{
+		"N": "NONE", "L": "LOW", "M": "MEDIUM", "H": "HIGH", "ND": "NOT_DEFINED"}},
+	{"CR", "confidentialityRequirement", cvss2Requirement},
+	{"IR", "integrityRequirement", cvss2Requirement},
+	{"AR", "availabilityRequirement", cvss2Requirement},
+}
+
+// find returns the metric with the given abbreviation.
+func (cms cvssMetrics) find(abbrev string) *cvssMetric {
+	for i := range cms {
+		if cms[i].abbrev == abbrev {
+			return &cms[i]
+		}
+	}
+	return nil
+}
+
+// parse splits a vector into its metrics and checks them
+// against the given metrics. The values are returned keyed
+// by the abbreviations of the metrics.
+func (cms cvssMetrics) parse(vector string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, part := range strings.Split(vector, "/") {
+		abbrev, value, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid metric %q", part)
+		}
+		m := cms.find(abbrev)
+		if m == nil {
+			return nil, fmt.Errorf("unknown metric %q", abbrev)
+		}
+		if _, ok := m.values[value]; !ok {
+			return nil, fmt.Errorf("invalid value %q for metric %q", value, abbrev)
+		}
+		if _, dup := values[abbrev]; dup {
+			return nil, fmt.Errorf("metric %q is defined more than once", abbrev)
+		}
+		values[abbrev] = value
+	}
+	return values, nil
+}
+
+// parseCVSS3Vector parses a CVSS v3.x vector string into its metrics.
+func parseCVSS3Vector(vector string) (string, map[string]string, error) {
+	prefix, rest, ok := strings.Cut(vector, "/")
+	if !ok || !strings.HasPrefix(prefix, "CVSS:") {
+		return "", nil, fmt.Errorf("vector %q has no CVSS version prefix", vector)
+	}
+	version := prefix[len("CVSS:"):]
+	if version != string(CVSSVersion30) && version != string(CVSSVersion31) {
+		return "", nil, fmt.Errorf("unsupported CVSS version %q", version)
+	}
+	values, err := cvss3Metrics.parse(rest)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, base := range []string{"AV", "AC", "PR", "UI", "S", "C", "I", "A"} {
+		if _, ok := values[base]; !ok {
+			return "", nil, fmt.Errorf("base metric %q is missing", base)
+		}
+	}
+	return version, values, nil
+}
+
+// parseCVSS2Vector parses a CVSS v2.0 vector string into its metrics.
+func parseCVSS2Vector(vector string) (map[string]string, error) {
+	values, err := cvss2Metrics.parse(vector)
+	if err != nil {
+		return nil, err
+	}
+	for _, base := range []string{"AV", "AC", "Au", "C", "I", "A"} {
+		if _, ok := values[base]; !ok {
+			return nil, fmt.Errorf("base metric %q is missing", base)
+		}
+	}
+	return values, nil
+}
+
+// cvss3Roundup rounds up to one decimal as defined by the CVSS v3.x
+// specifications. Version 3.1 avoids floating point artifacts.
+func cvss3Roundup(version string, x float64) float64 {
+	if version == string(CVSSVersion30) {
+		return math.Ceil(x*10) / 10
+	}
+	i := int64(math.Round(x * 100000))
+	if i%10000 == 0 {
+		return float64(i) / 100000
+	}
+	return float64(i/10000+1) / 10
+}
+
+// cvssWeight returns the weight of a metric value.
+// Values which are not in the table like undefined
+// optional metrics have the neutral weight 1.
+func cvssWeight(weights map[string]float64, value string) float64 {
+	if w, ok := weights[value]; ok {
+		return w
+	}
+	return 1
+}
+
+// Weights of the CVSS v3.x metrics.
+var (
+	cvss3AVWeights  = map[string]float64{"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2}
+	cvss3ACWeights  = map[string]float64{"L": 0.77, "H": 0.44}
+	cvss3UIWeights  = map[string]float64{"N": 0.85, "R": 0.62}
+	cvss3CIAWeights = map[string]float64{"H": 0.56, "L": 0.22, "N": 0}
+	cvss3EWeights   = map[string]float64{"H": 1, "F": 0.97, "P": 0.94, "U": 0.91}
+	cvss3RLWeights  = map[string]float64{"U": 1, "W": 0.97, "T": 0.96, "O": 0.95}
+	cvss3RCWeights  = map[string]float64{"C": 1, "R": 0.96, "U": 0.92}
+	cvss3ReqWeights = map[string]float64{"H": 1.5, "M": 1, "L": 0.5}
+)
+
+// cvss3PRWeight returns the weight of the privileges required
+// which depends on the scope.
+func cvss3PRWeight(value string, changed bool) float64 {
+	switch value {
+	case "L":
+		if changed {
+			return 0.68
+		}
+		return 0.62
+	case "H":
+		if changed {
+			return 0.5
+		}
+		return 0.27
+	default:
+		return 0.85
+	}
+}
+
+// cvss3Combine combines the impact and the exploitability
+// sub scores to a score.
+func cvss3Combine(version string, changed bool, impact, exploitability float64) float64 {
+	if impact <= 0 {
+		return 0
+	}
+	if changed {
+		return cvss3Roundup(version, math.Min(1.08*(impact+exploitability), 10))
+	}
+	return cvss3Roundup(version, math.Min(impact+exploitability, 10))
+}
+
+// cvss3TemporalFactor returns the product of the temporal metric weights.
+func cvss3TemporalFactor(values map[string]string) float64 {
+	return cvssWeight(cvss3EWeights, values["E"]) *
+		cvssWeight(cvss3RLWeights, values["RL"]) *
+		cvssWeight(cvss3RCWeights, values["RC"])
+}
+
+// cvss3BaseScore calculates the base score of CVSS v3.x metrics.
+func cvss3BaseScore(version string, values map[string]string) float64 {
+	changed := values["S"] == "C"
+	iss := 1 - (1-cvss3CIAWeights[values["C"]])*
+		(1-cvss3CIAWeights[values["I"]])*
+		(1-cvss3CIAWeights[values["A"]])
+	var impact float64
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	} else {
+		impact = 6.42 * iss
+	}
+	exploitability := 8.22 *
+		cvss3AVWeights[values["AV"]] *
+		cvss3ACWeights[values["AC"]] *
+		cvss3PRWeight(values["PR"], changed) *
+		cvss3UIWeights[values["UI"]]
+	return cvss3Combine(version, changed, impact, exploitability)
+}
+
+// cvss3TemporalScore calculates the temporal score of CVSS v3.x metrics.
+func cvss3TemporalScore(version string, values map[string]string) float64 {
+	return cvss3Roundup(version, cvss3BaseScore(version, values)*cvss3TemporalFactor(values))
+}
+
+// cvss3EnvironmentalScore calculates the environmental score of CVSS v3.x metrics.
+func cvss3EnvironmentalScore(version string, values map[string]string) float64 {
+	// modified returns the modified value of a base metric
+	// falling back to the base metric if not defined.
+	modified := func(abbrev string) string {
+		if v, ok := values["M"+abbrev]; ok && v != "X" {
+			return v
+		}
+		return values[abbrev]
+	}
+	changed := modified("S") == "C"
+	miss := 1.0
+	for _, m := range []string{"C", "I", "A"} {
+		miss *= 1 - cvssWeight(cvss3ReqWeights, values[m+"R"])*cvss3CIAWeights[modified(m)]
+	}
+	miss = math.Min(1-miss, 0.915)
+	var impact float64
+	switch {
+	case !changed:
+		impact = 6.42 * miss
+	case version == string(CVSSVersion30):
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss-0.02, 15)
+	default:
+		impact = 7.52*(miss-0.029) - 3.25*math.Pow(miss*0.9731-0.02, 13)
+	}
+	exploitability := 8.22 *
+		cvss3AVWeights[modified("AV")] *
+		cvss3ACWeights[modified("AC")] *
+		cvss3PRWeight(modified("PR"), changed) *
+		cvss3UIWeights[modified("UI")]
+	score := cvss3Combine(version, changed, impact, exploitability)
+	return cvss3Roundup(version, score*cvss3TemporalFactor(values))
+}
+
+// cvss3Severity returns the qualitative severity rating of a CVSS v3.x score.
+func cvss3Severity(score float64) CVSS3Severity {
+	switch {
+	case score == 0:
+		return CVSS3SeverityNone
+	case score < 4:
+		return CVSS3SeverityLow
+	case score < 7:
+		return CVSS3SeverityMedium
+	case score < 9:
+		return CVSS3SeverityHigh
+	default:
+		return CVSS3SeverityCritical
+	}
+}
+
+// cvss2Round rounds to one decimal as defined by the CVSS v2.0 specification.
+func cvss2Round(x float64) float64 {
+	return math.Round(x*10) / 10
+}
+
+// Weights of the CVSS v2.0 metrics.
+var (
+	cvss2AVWeights  = map[string]float64{"L": 0.395, "A": 0.646, "N": 1}
+	cvss2ACWeights  = map[string]float64{"H": 0.35, "M": 0.61, "L": 0.71}
+	cvss2AuWeights  = map[string]float64{"M": 0.45, "S": 0.56, "N": 0.704}
+	cvss2CIAWeights = map[string]float64{"N": 0, "P": 0.275, "C": 0.66}
+	cvss2EWeights   = map[string]float64{"U": 0.85, "POC": 0.9, "F": 0.95, "H": 1}
+	cvss2RLWeights  = map[string]float64{"OF": 0.87, "TF": 0.9, "W": 0.95, "U": 1}
+	cvss2RCWeights  = map[string]float64{"UC": 0.9, "UR": 0.95, "C": 1}
+	cvss2CDPWeights = map[string]float64{"N": 0, "L": 0.1, "LM": 0.3, "MH": 0.4, "H": 0.5, "ND": 0}
+	cvss2TDWeights  = map[string]float64{"N": 0, "L": 0.25, "M": 0.75, "H": 1}
+	cvss2ReqWeights = map[string]float64{"L": 0.5, "M": 1, "H": 1.51}
+)
+
+// cvss2Score calculates a CVSS v2.0 base score with the given impact.
+func cvss2Score(values map[string]string, impact float64) float64 {
+	if impact == 0 {
+		return 0
+	}
+	exploitability := 20 *
+		cvss2AVWeights[values["AV"]] *
+		cvss2ACWeights[values["AC"]] *
+		cvss2AuWeights[values["Au"]]
+	return cvss2Round((0.6*impact + 0.4*exploitability - 1.5) * 1.176)
+}
+
+// cvss2Temporal applies the temporal metrics to a CVSS v2.0 score.
+func cvss2Temporal(values map[string]string, score float64) float64 {
+	return cvss2Round(score *
+		cvssWeight(cvss2EWeights, values["E"]) *
+		cvssWeight(cvss2RLWeights, values["RL"]) *
+		cvssWeight(cvss2RCWeights, values["RC"]))
+}
+
+// cvss2BaseScore calculates the base score of CVSS v2.0 metrics.
+func cvss2BaseScore(values map[string]string) float64 {
+	impact := 10.41 * (1 - (1-cvss2CIAWeights[values["C"]])*
+		(1-cvss2CIAWeights[values["I"]])*
+		(1-cvss2CIAWeights[values["A"]]))
+	return cvss2Score(values, impact)
+}
+
+// cvss2TemporalScore calculates the temporal score of CVSS v2.0 metrics.
+func cvss2TemporalScore(values map[string]string) float64 {
+	return cvss2Temporal(values, cvss2BaseScore(values))
+}
+
+// cvss2EnvironmentalScore calculates the environmental score of CVSS v2.0 metrics.
+func cvss2EnvironmentalScore(values map[string]string) float64 {
+	impact := 1.0
+	for _, m := range []string{"C", "I", "A"} {
+		impact *= 1 - cvss2CIAWeights[values[m]]*cvssWeight(cvss2ReqWeights, values[m+"R"])
+	}
+	impact = math.Min(10, 10.41*(1-impact))
+	temporal := cvss2Temporal(values, cvss2Score(values, impact))
+	return cvss2Round((temporal + (10-temporal)*cvssWeight(cvss2CDPWeights, values["CDP"])) *
+		cvssWeight(cvss2TDWeights, values["TD"]))
+}
+
+// hasAnyMetric returns true if one of the given metrics is set.
+func hasAnyMetric(values map[string]string, abbrevs ...string) bool {
+	for _, abbrev := range abbrevs {
+		if _, ok := values[abbrev]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	cvss3TemporalMetrics      = []string{"E", "RL", "RC"}
+	cvss3EnvironmentalMetrics = []string{
+		"CR", "IR", "AR", "MAV", "MAC", "MPR", "MUI", "MS", "MC", "MI", "MA"}
+	cvss2TemporalMetrics      = []string{"E", "RL", "RC"}
+	cvss2EnvironmentalMetrics = []string{"CDP", "TD", "CR", "IR", "AR"}
+)
+
+// properties returns the JSON properties of the given metric values.
+func (cms cvssMetrics) properties(values map[string]string) map[string]any {
+	props := map[string]any{}
+	for abbrev, value := range values {
+		if m := cms.find(abbrev); m != nil {
+			props[m.property] = m.values[value]
+		}
+	}
+	return props
+}
+
+// cvss3Properties returns the JSON properties of a CVSS v3.x object
+// given by a vector. The temporal and environmental scores are only
+// included if all is true or if the vector has metrics of these groups.
+func cvss3Properties(vector string, all bool) (map[string]any, error) {
+	version, values, err := parseCVSS3Vector(vector)
+	if err != nil {
+		return nil, err
+	}
+	props := cvss3Metrics.properties(values)
+	props["version"] = version
+	props["vectorString"] = vector
+	base := cvss3BaseScore(version, values)
+	props["baseScore"] = base
+	props["baseSeverity"] = string(cvss3Severity(base))
+	if all || hasAnyMetric(values, cvss3TemporalMetrics...) {
+		temporal := cvss3TemporalScore(version, values)
+		props["temporalScore"] = temporal
+		props["temporalSeverity"] = string(cvss3Severity(temporal))
+	}
+	if all || hasAnyMetric(values, cvss3EnvironmentalMetrics...) {
+		env := cvss3EnvironmentalScore(version, values)
+		props["environmentalScore"] = env
+		props["environmentalSeverity"] = string(cvss3Severity(env))
+	}
+	return props, nil
+}
+
+// cvss2Properties returns the JSON properties of a CVSS v2.0 object
+// given by a vector. The temporal and environmental scores are only
+// included if all is true or if the vector has metrics of these groups.
+func cvss2Properties(vector string, all bool) (map[string]any, error) {
+	values, err := parseCVSS2Vector(vector)
+	if err != nil {
+		return nil, err
+	}
+	props := cvss2Metrics.properties(values)
+	props["version"] = string(CVSSVersion20)
+	props["vectorString"] = vector
+	props["baseScore"] = cvss2BaseScore(values)
+	if all || hasAnyMetric(values, cvss2TemporalMetrics...) {
+		props["temporalScore"] = cvss2TemporalScore(values)
+	}
+	if all || hasAnyMetric(values, cvss2EnvironmentalMetrics...) {
+		props["environmentalScore"] = cvss2EnvironmentalScore(values)
+	}
+	return props, nil
+}
+
+// ParseCVSS3 parses a CVSS v3.x vector string into a CVSS3 object.
+// The metric properties, the base score and severity are set.
+// The temporal and environmental scores and severities are set
+// if the vector has metrics of these groups.
+func ParseCVSS3(vector string) (*CVSS3, error) {
+	props, err := cvss3Properties(vector, false)
+	if err != nil {
+		return nil, err
+	}
+	var c CVSS3
+	if err := util.ReMarshalJSON(&c, props); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ParseCVSS2 parses a CVSS v2.0 vector string into a CVSS2 object.
+// The metric properties and the base score are set.
+// The temporal and environmental scores are set if the vector
+// has metrics of these groups.
+func ParseCVSS2(vector string) (*CVSS2, error) {
+	props, err := cvss2Properties(vector, false)
+	if err != nil {
+		return nil, err
+	}
+	var c CVSS2
+	if err := util.ReMarshalJSON(&c, props); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// CVSSMismatch is a property of a CVSS object which does not
+// agree with the value given by or calculated from its vector string.
+type CVSSMismatch struct {
+	Property string `json:"property"`
+	Value    string `json:"value"`
+	Expected string `json:"expected"`
+}
+
+// Computed returns true if the mismatch is in a score or a severity
+// and not in a metric given by the vector.
+func (cm *CVSSMismatch) Computed() bool {
+	return strings.HasSuffix(cm.Property, "Score") ||
+		strings.HasSuffix(cm.Property, "Severity")
+}
+
+// cvssComputedProperties are the properties which are
+// not metrics of the vector in the order they are checked.
+var cvssComputedProperties = []string{
+	"version",
+	"baseScore", "baseSeverity",
+	"temporalScore", "temporalSeverity",
+	"threatScore", "threatSeverity",
+	"environmentalScore", "environmentalSeverity",
+}
+
+// cvssMismatches compares the properties of a CVSS object with
+// the expected ones. Metrics not given by the vector are expected
+// to be "NOT_DEFINED".
+func cvssMismatches(metrics cvssMetrics, cvss any, want map[string]any) ([]CVSSMismatch, error) {
+	var props map[string]any
+	if err := util.ReMarshalJSON(&props, cvss); err != nil {
+		return nil, err
+	}
+	var mismatches []CVSSMismatch
+	check := func(property string, expected any) {
+		value, ok := props[property]
+		if !ok {
+			return
+		}
+		if v, ok := value.(float64); ok {
+			if e, ok := expected.(float64); ok && math.Abs(v-e) < 1e-9 {
+				return
+			}
+		} else if value == expected {
+			return
+		}
+		mismatches = append(mismatches, CVSSMismatch{
+			Property: property,
+			Value:    fmt.Sprint(value),
+			Expected: fmt.Sprint(expected),
+		})
+	}
+	for _, m := range metrics {
+		expected, ok := want[m.property]
+		if !ok {
+			expected = "NOT_DEFINED"
+		}
+		check(m.property, expected)
+	}
+	for _, property := range cvssComputedProperties {
+		if expected, ok := want[property]; ok {
+			check(property, expected)
+		}
+	}
+	return mismatches, nil
+}
+
+// Mismatches returns the properties of the CVSS v3.x object which
+// do not agree with its vector string. This covers the version,
+// the metrics and the scores and severities.
+// An error is returned if the vector string is missing or invalid.
+func (c *CVSS3) Mismatches() ([]CVSSMismatch, error) {
+	if c.VectorString == nil {
+		return nil, errors.New("'vectorString' is missing")
+	}
+	want, err := cvss3Properties(string(*c.VectorString), true)
+	if err != nil {
+		return nil, err
+	}
+	return cvssMismatches(cvss3Metrics, c, want)
+}
+
+// Mismatches returns the properties of the CVSS v2.0 object which
+// do not agree with its vector string. This covers the metrics
+// and the scores.
+// An error is returned if the vector string is missing or invalid.
+func (c *CVSS2) Mismatches() ([]CVSSMismatch, error) {
+	if c.VectorString == nil {
+		return nil, errors.New("'vectorString' is missing")
+	}
+	want, err := cvss2Properties(string(*c.VectorString), true)
+	if err != nil {
+		return nil, err
+	}
+	return cvssMismatches(cvss2Metrics, c, want)
+}
@@ -0,0 +1,525 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+var (
+	cvss4CIA = map[string]string{
+		"H": "HIGH", "L": "LOW", "N": "NONE",
+	}
+	cvss4ModifiedCIA = map[string]string{
+		"X": "NOT_DEFINED", "H": "HIGH", "L": "LOW", "N": "NONE",
+	}
+	cvss4ModifiedSubIA = map[string]string{
+		"X": "NOT_DEFINED", "S": "SAFETY", "H": "HIGH", "L": "LOW", "N": "NONE",
+	}
+	cvss4Requirement = map[string]string{
+		"X": "NOT_DEFINED", "H": "HIGH", "M": "MEDIUM", "L": "LOW",
+	}
+)
+
+// cvss4Metrics are the metrics of CVSS v4.0.
+var cvss4Metrics = cvssMetrics{
+	{"AV", "attackVector", map[string]string{
+		"N": "NETWORK", "A": "ADJACENT", "L": "LOCAL", "P": "PHYSICAL"}},
+	{"AC", "attackComplexity", map[string]string{
+		"L": "LOW", "H": "HIGH"}},
+	{"AT", "attackRequirements", map[string]string{
+		"N": "NONE", "P": "PRESENT"}},
+	{"PR", "privilegesRequired", map[string]string{
+		"N": "NONE", "L": "LOW", "H": "HIGH"}},
+	{"UI", "userInteraction", map[string]string{
+		"N": "NONE", "P": "PASSIVE", "A": "ACTIVE"}},
+	{"VC", "vulnConfidentialityImpact", cvss4CIA},
+	{"VI", "vulnIntegrityImpact", cvss4CIA},
+	{"VA", "vulnAvailabilityImpact", cvss4CIA},
+	{"SC", "subConfidentialityImpact", cvss4CIA},
+	{"SI", "subIntegrityImpact", cvss4CIA},
+	{"SA", "subAvailabilityImpact", cvss4CIA},
+	{"E", "exploitMaturity", map[string]string{
+		"X": "NOT_DEFINED", "A": "ATTACKED", "P": "PROOF_OF_CONCEPT", "U": "UNREPORTED"}},
+	{"CR", "confidentialityRequirement", cvss4Requirement},
+	{"IR", "integrityRequirement", cvss4Requirement},
+	{"AR", "availabilityRequirement", cvss4Requirement},
+	{"MAV", "modifiedAttackVector", map[string]string{
+		"X": "NOT_DEFINED", "N": "NETWORK", "A": "ADJACENT", "L": "LOCAL", "P": "PHYSICAL"}},
+	{"MAC", "modifiedAttackComplexity", map[string]string{
+		"X": "NOT_DEFINED", "L": "LOW", "H": "HIGH"}},
+	{"MAT", "modifiedAttackRequirements", map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "P": "PRESENT"}},
+	{"MPR", "modifiedPrivilegesRequired", map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "L": "LOW", "H": "HIGH"}},
+	{"MUI", "modifiedUserInteraction", map[string]string{
+		"X": "NOT_DEFINED", "N": "NONE", "P": "PASSIVE", "A": "ACTIVE"}},
+	{"MVC", "modifiedVulnConfidentialityImpact", cvss4ModifiedCIA},
+	{"MVI", "modifiedVulnIntegrityImpact", cvss4ModifiedCIA},
+	{"MVA", "modifiedVulnAvailabilityImpact", cvss4ModifiedCIA},
+	{"MSC", "modifiedSubConfidentialityImpact", cvss4ModifiedCIA},
+	{"MSI", "modifiedSubIntegrityImpact", cvss4ModifiedSubIA},
+	{"MSA", "modifiedSubAvailabilityImpact", cvss4ModifiedSubIA},
+	{"S", "Safety", map[string]string{
+		"X": "NOT_DEFINED", "N": "NEGLIGIBLE", "P": "PRESENT"}},
+	{"AU", "Automatable", map[string]string{
+		"X": "NOT_DEFINED", "N": "NO", "Y": "YES"}},
+	{"R", "Recovery", map[string]string{
+		"X": "NOT_DEFINED", "A": "AUTOMATIC", "U": "USER", "I": "IRRECOVERABLE"}},
+	{"V", "valueDensity", map[string]string{
+		"X": "NOT_DEFINED", "D": "DIFFUSE", "C": "CONCENTRATED"}},
+	{"RE", "vulnerabilityResponseEffort", map[string]string{
+		"X": "NOT_DEFINED", "L": "LOW", "M": "MODERATE", "H": "HIGH"}},
+	{"U", "providerUrgency", map[string]string{
+		"X": "NOT_DEFINED", "Clear": "CLEAR", "Green": "GREEN",
+		"Amber": "AMBER", "Red": "RED"}},
+}
+
+var (
+	cvss4BaseMetrics = []string{
+		"AV", "AC", "AT", "PR", "UI", "VC", "VI", "VA", "SC", "SI", "SA"}
+	cvss4ThreatMetrics        = []string{"E"}
+	cvss4EnvironmentalMetrics = []string{
+		"CR", "IR", "AR", "MAV", "MAC", "MAT", "MPR", "MUI",
+		"MVC", "MVI", "MVA", "MSC", "MSI", "MSA"}
+)
+
+// parseCVSS4Vector parses a CVSS v4.0 vector string into its metrics.
+func parseCVSS4Vector(vector string) (map[string]string, error) {
+	prefix, rest, ok := strings.Cut(vector, "/")
+	if !ok || !strings.HasPrefix(prefix, "CVSS:") {
+		return nil, fmt.Errorf("vector %q has no CVSS version prefix", vector)
+	}
+	if version := prefix[len("CVSS:"):]; version != string(CVSSVersion40) {
+		return nil, fmt.Errorf("unsupported CVSS version %q", version)
+	}
+	values, err := cvss4Metrics.parse(rest)
+	if err != nil {
+		return nil, err
+	}
+	for _, base := range cvss4BaseMetrics {
+		if _, ok := values[base]; !ok {
+			return nil, fmt.Errorf("base metric %q is missing", base)
+		}
+	}
+	if _, err := cvss4VectorStringPattern([]byte(vector)); err != nil {
+		return nil, errors.New("metrics are not in the order of the specification")
+	}
+	return values, nil
+}
+
+// cvss4Lookup are the scores of the macro vectors
+// as given by the CVSS v4.0 specification.
+var cvss4Lookup = map[string]float64{
+	"000000": 10, "000001": 9.9, "000010": 9.8, "000011": 9.5, "000020": 9.5, "000021": 9.2,
+	"000100": 10, "000101": 9.6, "000110": 9.3, "000111": 8.7, "000120": 9.1, "000121": 8.1,
+	"000200": 9.3, "000201": 9, "000210": 8.9, "000211": 8, "000220": 8.1, "000221": 6.8,
+	"001000": 9.8, "001001": 9.5, "001010": 9.5, "001011": 9.2, "001020": 9, "001021": 8.4,
+	"001100": 9.3, "001101": 9.2, "001110": 8.9, "001111": 8.1, "001120": 8.1, "001121": 6.5,
+	"001200": 8.8, "001201": 8, "001210": 7.8, "001211": 7, "001220": 6.9, "001221": 4.8,
+	"002001": 9.2, "002011": 8.2, "002021": 7.2, "002101": 7.9, "002111": 6.9, "002121": 5,
+	"002201": 6.9, "002211": 5.5, "002221": 2.7, "010000": 9.9, "010001": 9.7, "010010": 9.5,
+	"010011": 9.2, "010020": 9.2, "010021": 8.5, "010100": 9.5, "010101": 9.1, "010110": 9,
+	"010111": 8.3, "010120": 8.4, "010121": 7.1, "010200": 9.2, "010201": 8.1, "010210": 8.2,
+	"010211": 7.1, "010220": 7.2, "010221": 5.3, "011000": 9.5, "011001": 9.3, "011010": 9.2,
+	"011011": 8.5, "011020": 8.5, "011021": 7.3, "011100": 9.2, "011101": 8.2, "011110": 8,
+	"011111": 7.2, "011120": 7, "011121": 5.9, "011200": 8.4, "011201": 7, "011210": 7.1,
+	"011211": 5.2, "011220": 5, "011221": 3, "012001": 8.6, "012011": 7.5, "012021": 5.2,
+	"012101": 7.1, "012111": 5.2, "012121": 2.9, "012201": 6.3, "012211": 2.9, "012221": 1.7,
+	"100000": 9.8, "100001": 9.5, "100010": 9.4, "100011": 8.7, "100020": 9.1, "100021": 8.1,
+	"100100": 9.4, "100101": 8.9, "100110": 8.6, "100111": 7.4, "100120": 7.7, "100121": 6.4,
+	"100200": 8.7, "100201": 7.5, "100210": 7.4, "100211": 6.3, "100220": 6.3, "100221": 4.9,
+	"101000": 9.4, "101001": 8.9, "101010": 8.8, "101011": 7.7, "101020": 7.6, "101021": 6.7,
+	"101100": 8.6, "101101": 7.6, "101110": 7.4, "101111": 5.8, "101120": 5.9, "101121": 5,
+	"101200": 7.2, "101201": 5.7, "101210": 5.7, "101211": 5.2, "101220": 5.2, "101221": 2.5,
+	"102001": 8.3, "102011": 7, "102021": 5.4, "102101": 6.5, "102111": 5.8, "102121": 2.6,
+	"102201": 5.3, "102211": 2.1, "102221": 1.3, "110000": 9.5, "110001": 9, "110010": 8.8,
+	"110011": 7.6, "110020": 7.6, "110021": 7, "110100": 9, "110101": 7.7, "110110": 7.5,
+	"110111": 6.2, "110120": 6.1, "110121": 5.3, "110200": 7.7, "110201": 6.6, "110210": 6.8,
+	"110211": 5.9, "110220": 5.2, "110221": 3, "111000": 8.9, "111001": 7.8, "111010": 7.6,
+	"111011": 6.7, "111020": 6.2, "111021": 5.8, "111100": 7.4, "111101": 5.9, "111110": 5.7,
+	"111111": 5.7, "111120": 4.7, "111121": 2.3, "111200": 6.1, "111201": 5.2, "111210": 5.7,
+	"111211": 2.9, "111220": 2.4, "111221": 1.6, "112001": 7.1, "112011": 5.9, "112021": 3,
+	"112101": 5.8, "112111": 2.6, "112121": 1.5, "112201": 2.3, "112211": 1.3, "112221": 0.6,
+	"200000": 9.3, "200001": 8.7, "200010": 8.6, "200011": 7.2, "200020": 7.5, "200021": 5.8,
+	"200100": 8.6, "200101": 7.4, "200110": 7.4, "200111": 6.1, "200120": 5.6, "200121": 3.4,
+	"200200": 7, "200201": 5.4, "200210": 5.2, "200211": 4, "200220": 4, "200221": 2.2,
+	"201000": 8.5, "201001": 7.5, "201010": 7.4, "201011": 5.5, "201020": 6.2, "201021": 5.1,
+	"201100": 7.2, "201101": 5.7, "201110": 5.5, "201111": 4.1, "201120": 4.6, "201121": 1.9,
+	"201200": 5.3, "201201": 3.6, "201210": 3.4, "201211": 1.9, "201220": 1.9, "201221": 0.8,
+	"202001": 6.4, "202011": 5.1, "202021": 2, "202101": 4.7, "202111": 2.1, "202121": 1.1,
+	"202201": 2.4, "202211": 0.9, "202221": 0.4, "210000": 8.8, "210001": 7.5, "210010": 7.3,
+	"210011": 5.3, "210020": 6, "210021": 5, "210100": 7.3, "210101": 5.5, "210110": 5.9,
+	"210111": 4, "210120": 4.1, "210121": 2, "210200": 5.4, "210201": 4.3, "210210": 4.5,
+	"210211": 2.2, "210220": 2, "210221": 1.1, "211000": 7.5, "211001": 5.5, "211010": 5.8,
+	"211011": 4.5, "211020": 4, "211021": 2.1, "211100": 6.1, "211101": 5.1, "211110": 4.8,
+	"211111": 1.8, "211120": 2, "211121": 0.9, "211200": 4.6, "211201": 1.8, "211210": 1.7,
+	"211211": 0.7, "211220": 0.8, "211221": 0.2, "212001": 5.3, "212011": 2.4, "212021": 1.4,
+	"212101": 2.4, "212111": 1.2, "212121": 0.5, "212201": 1, "212211": 0.3, "212221": 0.1,
+}
+
+// cvss4MaxComposed are the highest severity vectors
+// of the levels of the equivalence classes.
+var (
+	cvss4MaxEQ1 = [][]string{
+		{"AV:N/PR:N/UI:N"},
+		{"AV:A/PR:N/UI:N", "AV:N/PR:L/UI:N", "AV:N/PR:N/UI:P"},
+		{"AV:P/PR:N/UI:N", "AV:A/PR:L/UI:P"},
+	}
+	cvss4MaxEQ2 = [][]string{
+		{"AC:L/AT:N"},
+		{"AC:H/AT:N", "AC:L/AT:P"},
+	}
+	cvss4MaxEQ3EQ6 = [][][]string{
+		{
+			{"VC:H/VI:H/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:H/VI:H/VA:L/CR:M/IR:M/AR:H", "VC:H/VI:H/VA:H/CR:M/IR:M/AR:M"},
+		},
+		{
+			{"VC:L/VI:H/VA:H/CR:H/IR:H/AR:H", "VC:H/VI:L/VA:H/CR:H/IR:H/AR:H"},
+			{"VC:L/VI:H/VA:H/CR:H/IR:M/AR:M", "VC:H/VI:L/VA:H/CR:M/IR:H/AR:M",
+				"VC:L/VI:H/VA:L/CR:H/IR:M/AR:H", "VC:H/VI:L/VA:L/CR:M/IR:H/AR:H",
+				"VC:L/VI:L/VA:H/CR:H/IR:H/AR:M"},
+		},
+		{
+			nil,
+			{"VC:L/VI:L/VA:L/CR:H/IR:H/AR:H"},
+		},
+	}
+	cvss4MaxEQ4 = [][]string{
+		{"SC:H/SI:S/SA:S"},
+		{"SC:H/SI:H/SA:H"},
+		{"SC:L/SI:L/SA:L"},
+	}
+	cvss4MaxEQ5 = [][]string{
+		{"E:A"},
+		{"E:P"},
+		{"E:U"},
+	}
+)
+
+// cvss4MaxSeverity are the depths of the levels of the equivalence classes.
+var (
+	cvss4MaxSeverityEQ1    = []float64{1, 4, 5}
+	cvss4MaxSeverityEQ2    = []float64{1, 2}
+	cvss4MaxSeverityEQ3EQ6 = [][]float64{{7, 6}, {8, 8}, {0, 10}}
+	cvss4MaxSeverityEQ4    = []float64{6, 5, 4}
+)
+
+// cvss4Levels are the severity levels of the metric values
+// used to calculate the severity distances.
+var cvss4Levels = map[string]map[string]float64{
+	"AV": {"N": 0.0, "A": 0.1, "L": 0.2, "P": 0.3},
+	"PR": {"N": 0.0, "L": 0.1, "H": 0.2},
+	"UI": {"N": 0.0, "P": 0.1, "A": 0.2},
+	"AC": {"L": 0.0, "H": 0.1},
+	"AT": {"N": 0.0, "P": 0.1},
+	"VC": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VI": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"VA": {"H": 0.0, "L": 0.1, "N": 0.2},
+	"SC": {"H": 0.1, "L": 0.2, "N": 0.3},
+	"SI": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"SA": {"S": 0.0, "H": 0.1, "L": 0.2, "N": 0.3},
+	"CR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"IR": {"H": 0.0, "M": 0.1, "L": 0.2},
+	"AR": {"H": 0.0, "M": 0.1, "L": 0.2},
+}
+
+// cvss4Effective returns the effective value of a metric.
+// Undefined threat and requirement metrics default to the worst case,
+// modified metrics overwrite the base metrics.
+func cvss4Effective(values map[string]string, metric string) string {
+	v := values[metric]
+	switch metric {
+	case "E":
+		if v == "" || v == "X" {
+			return "A"
+		}
+	case "CR", "IR", "AR":
+		if v == "" || v == "X" {
+			return "H"
+		}
+	}
+	if mv, ok := values["M"+metric]; ok && mv != "X" {
+		return mv
+	}
+	return v
+}
+
+// cvss4MacroVector returns the levels of the six
+// equivalence classes of the effective metrics.
+func cvss4MacroVector(m func(string) string) [6]int {
+	var eq [6]int
+	switch {
+	case m("AV") == "N" && m("PR") == "N" && m("UI") == "N":
+		eq[0] = 0
+	case (m("AV") == "N" || m("PR") == "N" || m("UI") == "N") && m("AV") != "P":
+		eq[0] = 1
+	default:
+		eq[0] = 2
+	}
+	if m("AC") != "L" || m("AT") != "N" {
+		eq[1] = 1
+	}
+	switch {
+	case m("VC") == "H" && m("VI") == "H":
+		eq[2] = 0
+	case m("VC") == "H" || m("VI") == "H" || m("VA") == "H":
+		eq[2] = 1
+	default:
+		eq[2] = 2
+	}
+	switch {
+	case m("SI") == "S" || m("SA") == "S":
+		eq[3] = 0
+	case m("SC") == "H" || m("SI") == "H" || m("SA") == "H":
+		eq[3] = 1
+	default:
+		eq[3] = 2
+	}
+	switch m("E") {
+	case "A":
+		eq[4] = 0
+	case "P":
+		eq[4] = 1
+	default:
+		eq[4] = 2
+	}
+	if !(m("CR") == "H" && m("VC") == "H" ||
+		m("IR") == "H" && m("VI") == "H" ||
+		m("AR") == "H" && m("VA") == "H") {
+		eq[5] = 1
+	}
+	return eq
+}
+
+// cvss4MacroScore returns the score of a macro vector.
+func cvss4MacroScore(eq [6]int) (float64, bool) {
+	var key [6]byte
+	for i, v := range eq {
+		key[i] = byte('0' + v)
+	}
+	score, ok := cvss4Lookup[string(key[:])]
+	return score, ok
+}
+
+// cvss4Score calculates the score of CVSS v4.0 metrics following
+// the algorithm of the reference implementation of the specification.
+// The score is interpolated between the score of the macro vector
+// and the scores of the next lower macro vectors by the severity
+// distance to the highest severity vectors of the macro vector.
+func cvss4Score(values map[string]string) float64 {
+	m := func(metric string) string { return cvss4Effective(values, metric) }
+
+	// No impact on the vulnerable and the subsequent systems.
+	if m("VC") == "N" && m("VI") == "N" && m("VA") == "N" &&
+		m("SC") == "N" && m("SI") == "N" && m("SA") == "N" {
+		return 0
+	}
+
+	eq := cvss4MacroVector(m)
+	value, _ := cvss4MacroScore(eq)
+
+	// lower returns the score of the macro vector with
+	// the given equivalence classes one level lower.
+	lower := func(classes ...int) (float64, bool) {
+		next := eq
+		for _, c := range classes {
+			next[c]++
+		}
+		return cvss4MacroScore(next)
+	}
+
+	scoreEQ1, okEQ1 := lower(0)
+	scoreEQ2, okEQ2 := lower(1)
+	var scoreEQ3EQ6 float64
+	var okEQ3EQ6 bool
+	switch eq3, eq6 := eq[2], eq[5]; {
+	case eq3 == 0 && eq6 == 0:
+		// Take the higher of both paths.
+		left, okLeft := lower(5)
+		right, okRight := lower(2)
+		if okLeft && okRight && left > right {
+			scoreEQ3EQ6, okEQ3EQ6 = left, true
+		} else {
+			scoreEQ3EQ6, okEQ3EQ6 = right, okRight
+		}
+	case eq3 == 1 && eq6 == 0:
+		scoreEQ3EQ6, okEQ3EQ6 = lower(5)
+	case eq6 == 1 && eq3 < 2:
+		scoreEQ3EQ6, okEQ3EQ6 = lower(2)
+	default:
+		scoreEQ3EQ6, okEQ3EQ6 = lower(2, 5)
+	}
+	scoreEQ4, okEQ4 := lower(3)
+	_, okEQ5 := lower(4)
+
+	// Find the first highest severity vector of the macro vector
+	// which is not less severe than the vector to be scored.
+	levels := []string{
+		"AV", "PR", "UI", "AC", "AT", "VC", "VI", "VA", "SC", "SI", "SA", "CR", "IR", "AR"}
+	distances := map[string]float64{}
+search:
+	for _, eq1 := range cvss4MaxEQ1[eq[0]] {
+		for _, eq2 := range cvss4MaxEQ2[eq[1]] {
+			for _, eq3eq6 := range cvss4MaxEQ3EQ6[eq[2]][eq[5]] {
+				for _, eq4 := range cvss4MaxEQ4[eq[3]] {
+					for _, eq5 := range cvss4MaxEQ5[eq[4]] {
+						highest := map[string]string{}
+						for _, part := range strings.Split(
+							strings.Join([]string{eq1, eq2, eq3eq6, eq4, eq5}, "/"), "/") {
+							k, v, _ := strings.Cut(part, ":")
+							highest[k] = v
+						}
+						greater := true
+						for _, l := range levels {
+							d := cvss4Levels[l][m(l)] - cvss4Levels[l][highest[l]]
+							distances[l] = d
+							greater = greater && d >= 0
+						}
+						if greater {
+							break search
+						}
+					}
+				}
+			}
+		}
+	}
+
+	const step = 0.1
+	var (
+		n   int
+		sum float64
+	)
+	add := func(ok bool, lower, distance, maxSeverity float64) {
+		if ok {
+			n++
+			sum += (value - lower) * (distance / (maxSeverity * step))
+		}
+	}
+	add(okEQ1, scoreEQ1,
+		distances["AV"]+distances["PR"]+distances["UI"],
+		cvss4MaxSeverityEQ1[eq[0]])
+	add(okEQ2, scoreEQ2,
+		distances["AC"]+distances["AT"],
+		cvss4MaxSeverityEQ2[eq[1]])
+	add(okEQ3EQ6, scoreEQ3EQ6,
+		distances["VC"]+distances["VI"]+distances["VA"]+
+			distances["CR"]+distances["IR"]+distances["AR"],
+		cvss4MaxSeverityEQ3EQ6[eq[2]][eq[5]])
+	add(okEQ4, scoreEQ4,
+		distances["SC"]+distances["SI"]+distances["SA"],
+		cvss4MaxSeverityEQ4[eq[3]])
+	// The distance in EQ5 is always zero.
+	if okEQ5 {
+		n++
+	}
+	if n > 0 {
+		value -= sum / float64(n)
+	}
+	value = math.Max(0, math.Min(value, 10))
+	// Like the reference implementation round with a small epsilon
+	// to compensate for floating point errors at x.x5 boundaries.
+	return math.Round((value+1e-6)*10) / 10
+}
+
+// cvss4Subset returns the values of the given metric groups.
+func cvss4Subset(values map[string]string, groups ...[]string) map[string]string {
+	subset := map[string]string{}
+	for _, group := range groups {
+		for _, abbrev := range group {
+			if v, ok := values[abbrev]; ok {
+				subset[abbrev] = v
+			}
+		}
+	}
+	return subset
+}
+
+// cvss4BaseScore calculates the score of the base metrics (CVSS-B).
+func cvss4BaseScore(values map[string]string) float64 {
+	return cvss4Score(cvss4Subset(values, cvss4BaseMetrics))
+}
+
+// cvss4ThreatScore calculates the score of the base
+// and threat metrics (CVSS-BT).
+func cvss4ThreatScore(values map[string]string) float64 {
+	return cvss4Score(cvss4Subset(values, cvss4BaseMetrics, cvss4ThreatMetrics))
+}
+
+// cvss4EnvironmentalScore calculates the score of the base, threat
+// and environmental metrics (CVSS-BTE).
+func cvss4EnvironmentalScore(values map[string]string) float64 {
+	return cvss4Score(values)
+}
+
+// cvss4Properties returns the JSON properties of a CVSS v4.0 object
+// given by a vector. The threat and environmental scores are only
+// included if all is true or if the vector has metrics of these groups.
+func cvss4Properties(vector string, all bool) (map[string]any, error) {
+	values, err := parseCVSS4Vector(vector)
+	if err != nil {
+		return nil, err
+	}
+	props := cvss4Metrics.properties(values)
+	props["version"] = string(CVSSVersion40)
+	props["vectorString"] = vector
+	base := cvss4BaseScore(values)
+	props["baseScore"] = base
+	props["baseSeverity"] = string(cvss3Severity(base))
+	if all || hasAnyMetric(values, cvss4ThreatMetrics...) {
+		threat := cvss4ThreatScore(values)
+		props["threatScore"] = threat
+		props["threatSeverity"] = string(cvss3Severity(threat))
+	}
+	if all || hasAnyMetric(values, cvss4EnvironmentalMetrics...) {
+		env := cvss4EnvironmentalScore(values)
+		props["environmentalScore"] = env
+		props["environmentalSeverity"] = string(cvss3Severity(env))
+	}
+	return props, nil
+}
+
+// ParseCVSS4 parses a CVSS v4.0 vector string into a CVSS4 object.
+// The metric properties, the base score and severity are set.
+// The threat and environmental scores and severities are set
+// if the vector has metrics of these groups.
+// The base score only covers the base metrics, the threat score
+// the base and threat metrics and the environmental score all metrics.
+func ParseCVSS4(vector string) (*CVSS4, error) {
+	props, err := cvss4Properties(vector, false)
+	if err != nil {
+		return nil, err
+	}
+	var c CVSS4
+	if err := util.ReMarshalJSON(&c, props); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Mismatches returns the properties of the CVSS v4.0 object which
+// do not agree with its vector string. This covers the metrics
+// and the scores and severities.
+// An error is returned if the vector string is missing or invalid.
+func (c *CVSS4) Mismatches() ([]CVSSMismatch, error) {
+	if c.VectorString == nil {
+		return nil, errors.New("'vectorString' is missing")
+	}
+	want, err := cvss4Properties(string(*c.VectorString), true)
+	if err != nil {
+		return nil, err
+	}
+	return cvssMismatches(cvss4Metrics, c, want)
+}
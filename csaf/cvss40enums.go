@@ -0,0 +1,704 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+//
+// THIS FILE IS MACHINE GENERATED. EDIT WITH CARE!
+
+package csaf
+
+// CVSS40AttackComplexity represents the attackComplexityType in CVSS40.
+type CVSS40AttackComplexity string
+
+const (
+	// CVSS40AttackComplexityHigh is a constant for "HIGH".
+	CVSS40AttackComplexityHigh CVSS40AttackComplexity = "HIGH"
+	// CVSS40AttackComplexityLow is a constant for "LOW".
+	CVSS40AttackComplexityLow CVSS40AttackComplexity = "LOW"
+)
+
+var cvss40AttackComplexityPattern = alternativesUnmarshal(
+	string(CVSS40AttackComplexityHigh),
+	string(CVSS40AttackComplexityLow),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40AttackComplexity) UnmarshalText(data []byte) error {
+	s, err := cvss40AttackComplexityPattern(data)
+	if err == nil {
+		*e = CVSS40AttackComplexity(s)
+	}
+	return err
+}
+
+// CVSS40AttackRequirements represents the attackRequirementsType in CVSS40.
+type CVSS40AttackRequirements string
+
+const (
+	// CVSS40AttackRequirementsNone is a constant for "NONE".
+	CVSS40AttackRequirementsNone CVSS40AttackRequirements = "NONE"
+	// CVSS40AttackRequirementsPresent is a constant for "PRESENT".
+	CVSS40AttackRequirementsPresent CVSS40AttackRequirements = "PRESENT"
+)
+
+var cvss40AttackRequirementsPattern = alternativesUnmarshal(
+	string(CVSS40AttackRequirementsNone),
+	string(CVSS40AttackRequirementsPresent),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40AttackRequirements) UnmarshalText(data []byte) error {
+	s, err := cvss40AttackRequirementsPattern(data)
+	if err == nil {
+		*e = CVSS40AttackRequirements(s)
+	}
+	return err
+}
+
+// CVSS40AttackVector represents the attackVectorType in CVSS40.
+type CVSS40AttackVector string
+
+const (
+	// CVSS40AttackVectorNetwork is a constant for "NETWORK".
+	CVSS40AttackVectorNetwork CVSS40AttackVector = "NETWORK"
+	// CVSS40AttackVectorAdjacent is a constant for "ADJACENT".
+	CVSS40AttackVectorAdjacent CVSS40AttackVector = "ADJACENT"
+	// CVSS40AttackVectorLocal is a constant for "LOCAL".
+	CVSS40AttackVectorLocal CVSS40AttackVector = "LOCAL"
+	// CVSS40AttackVectorPhysical is a constant for "PHYSICAL".
+	CVSS40AttackVectorPhysical CVSS40AttackVector = "PHYSICAL"
+)
+
+var cvss40AttackVectorPattern = alternativesUnmarshal(
+	string(CVSS40AttackVectorNetwork),
+	string(CVSS40AttackVectorAdjacent),
+	string(CVSS40AttackVectorLocal),
+	string(CVSS40AttackVectorPhysical),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40AttackVector) UnmarshalText(data []byte) error {
+	s, err := cvss40AttackVectorPattern(data)
+	if err == nil {
+		*e = CVSS40AttackVector(s)
+	}
+	return err
+}
+
+// CVSS40Automatable represents the automatableType in CVSS40.
+type CVSS40Automatable string
+
+const (
+	// CVSS40AutomatableNo is a constant for "NO".
+	CVSS40AutomatableNo CVSS40Automatable = "NO"
+	// CVSS40AutomatableYes is a constant for "YES".
+	CVSS40AutomatableYes CVSS40Automatable = "YES"
+	// CVSS40AutomatableNotDefined is a constant for "NOT_DEFINED".
+	CVSS40AutomatableNotDefined CVSS40Automatable = "NOT_DEFINED"
+)
+
+var cvss40AutomatablePattern = alternativesUnmarshal(
+	string(CVSS40AutomatableNo),
+	string(CVSS40AutomatableYes),
+	string(CVSS40AutomatableNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40Automatable) UnmarshalText(data []byte) error {
+	s, err := cvss40AutomatablePattern(data)
+	if err == nil {
+		*e = CVSS40Automatable(s)
+	}
+	return err
+}
+
+// CVSS40CiaRequirement represents the ciaRequirementType in CVSS40.
+type CVSS40CiaRequirement string
+
+const (
+	// CVSS40CiaRequirementLow is a constant for "LOW".
+	CVSS40CiaRequirementLow CVSS40CiaRequirement = "LOW"
+	// CVSS40CiaRequirementMedium is a constant for "MEDIUM".
+	CVSS40CiaRequirementMedium CVSS40CiaRequirement = "MEDIUM"
+	// CVSS40CiaRequirementHigh is a constant for "HIGH".
+	CVSS40CiaRequirementHigh CVSS40CiaRequirement = "HIGH"
+	// CVSS40CiaRequirementNotDefined is a constant for "NOT_DEFINED".
+	CVSS40CiaRequirementNotDefined CVSS40CiaRequirement = "NOT_DEFINED"
+)
+
+var cvss40CiaRequirementPattern = alternativesUnmarshal(
+	string(CVSS40CiaRequirementLow),
+	string(CVSS40CiaRequirementMedium),
+	string(CVSS40CiaRequirementHigh),
+	string(CVSS40CiaRequirementNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40CiaRequirement) UnmarshalText(data []byte) error {
+	s, err := cvss40CiaRequirementPattern(data)
+	if err == nil {
+		*e = CVSS40CiaRequirement(s)
+	}
+	return err
+}
+
+// CVSS40ExploitMaturity represents the exploitMaturityType in CVSS40.
+type CVSS40ExploitMaturity string
+
+const (
+	// CVSS40ExploitMaturityUnreported is a constant for "UNREPORTED".
+	CVSS40ExploitMaturityUnreported CVSS40ExploitMaturity = "UNREPORTED"
+	// CVSS40ExploitMaturityProofOfConcept is a constant for "PROOF_OF_CONCEPT".
+	CVSS40ExploitMaturityProofOfConcept CVSS40ExploitMaturity = "PROOF_OF_CONCEPT"
+	// CVSS40ExploitMaturityAttacked is a constant for "ATTACKED".
+	CVSS40ExploitMaturityAttacked CVSS40ExploitMaturity = "ATTACKED"
+	// CVSS40ExploitMaturityNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ExploitMaturityNotDefined CVSS40ExploitMaturity = "NOT_DEFINED"
+)
+
+var cvss40ExploitMaturityPattern = alternativesUnmarshal(
+	string(CVSS40ExploitMaturityUnreported),
+	string(CVSS40ExploitMaturityProofOfConcept),
+	string(CVSS40ExploitMaturityAttacked),
+	string(CVSS40ExploitMaturityNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ExploitMaturity) UnmarshalText(data []byte) error {
+	s, err := cvss40ExploitMaturityPattern(data)
+	if err == nil {
+		*e = CVSS40ExploitMaturity(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedAttackComplexity represents the modifiedAttackComplexityType in CVSS40.
+type CVSS40ModifiedAttackComplexity string
+
+const (
+	// CVSS40ModifiedAttackComplexityHigh is a constant for "HIGH".
+	CVSS40ModifiedAttackComplexityHigh CVSS40ModifiedAttackComplexity = "HIGH"
+	// CVSS40ModifiedAttackComplexityLow is a constant for "LOW".
+	CVSS40ModifiedAttackComplexityLow CVSS40ModifiedAttackComplexity = "LOW"
+	// CVSS40ModifiedAttackComplexityNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedAttackComplexityNotDefined CVSS40ModifiedAttackComplexity = "NOT_DEFINED"
+)
+
+var cvss40ModifiedAttackComplexityPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedAttackComplexityHigh),
+	string(CVSS40ModifiedAttackComplexityLow),
+	string(CVSS40ModifiedAttackComplexityNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedAttackComplexity) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedAttackComplexityPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedAttackComplexity(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedAttackRequirements represents the modifiedAttackRequirementsType in CVSS40.
+type CVSS40ModifiedAttackRequirements string
+
+const (
+	// CVSS40ModifiedAttackRequirementsNone is a constant for "NONE".
+	CVSS40ModifiedAttackRequirementsNone CVSS40ModifiedAttackRequirements = "NONE"
+	// CVSS40ModifiedAttackRequirementsPresent is a constant for "PRESENT".
+	CVSS40ModifiedAttackRequirementsPresent CVSS40ModifiedAttackRequirements = "PRESENT"
+	// CVSS40ModifiedAttackRequirementsNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedAttackRequirementsNotDefined CVSS40ModifiedAttackRequirements = "NOT_DEFINED"
+)
+
+var cvss40ModifiedAttackRequirementsPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedAttackRequirementsNone),
+	string(CVSS40ModifiedAttackRequirementsPresent),
+	string(CVSS40ModifiedAttackRequirementsNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedAttackRequirements) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedAttackRequirementsPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedAttackRequirements(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedAttackVector represents the modifiedAttackVectorType in CVSS40.
+type CVSS40ModifiedAttackVector string
+
+const (
+	// CVSS40ModifiedAttackVectorNetwork is a constant for "NETWORK".
+	CVSS40ModifiedAttackVectorNetwork CVSS40ModifiedAttackVector = "NETWORK"
+	// CVSS40ModifiedAttackVectorAdjacent is a constant for "ADJACENT".
+	CVSS40ModifiedAttackVectorAdjacent CVSS40ModifiedAttackVector = "ADJACENT"
+	// CVSS40ModifiedAttackVectorLocal is a constant for "LOCAL".
+	CVSS40ModifiedAttackVectorLocal CVSS40ModifiedAttackVector = "LOCAL"
+	// CVSS40ModifiedAttackVectorPhysical is a constant for "PHYSICAL".
+	CVSS40ModifiedAttackVectorPhysical CVSS40ModifiedAttackVector = "PHYSICAL"
+	// CVSS40ModifiedAttackVectorNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedAttackVectorNotDefined CVSS40ModifiedAttackVector = "NOT_DEFINED"
+)
+
+var cvss40ModifiedAttackVectorPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedAttackVectorNetwork),
+	string(CVSS40ModifiedAttackVectorAdjacent),
+	string(CVSS40ModifiedAttackVectorLocal),
+	string(CVSS40ModifiedAttackVectorPhysical),
+	string(CVSS40ModifiedAttackVectorNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedAttackVector) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedAttackVectorPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedAttackVector(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedPrivilegesRequired represents the modifiedPrivilegesRequiredType in CVSS40.
+type CVSS40ModifiedPrivilegesRequired string
+
+const (
+	// CVSS40ModifiedPrivilegesRequiredHigh is a constant for "HIGH".
+	CVSS40ModifiedPrivilegesRequiredHigh CVSS40ModifiedPrivilegesRequired = "HIGH"
+	// CVSS40ModifiedPrivilegesRequiredLow is a constant for "LOW".
+	CVSS40ModifiedPrivilegesRequiredLow CVSS40ModifiedPrivilegesRequired = "LOW"
+	// CVSS40ModifiedPrivilegesRequiredNone is a constant for "NONE".
+	CVSS40ModifiedPrivilegesRequiredNone CVSS40ModifiedPrivilegesRequired = "NONE"
+	// CVSS40ModifiedPrivilegesRequiredNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedPrivilegesRequiredNotDefined CVSS40ModifiedPrivilegesRequired = "NOT_DEFINED"
+)
+
+var cvss40ModifiedPrivilegesRequiredPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedPrivilegesRequiredHigh),
+	string(CVSS40ModifiedPrivilegesRequiredLow),
+	string(CVSS40ModifiedPrivilegesRequiredNone),
+	string(CVSS40ModifiedPrivilegesRequiredNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedPrivilegesRequired) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedPrivilegesRequiredPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedPrivilegesRequired(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedSubC represents the modifiedSubCType in CVSS40.
+type CVSS40ModifiedSubC string
+
+const (
+	// CVSS40ModifiedSubCNone is a constant for "NONE".
+	CVSS40ModifiedSubCNone CVSS40ModifiedSubC = "NONE"
+	// CVSS40ModifiedSubCLow is a constant for "LOW".
+	CVSS40ModifiedSubCLow CVSS40ModifiedSubC = "LOW"
+	// CVSS40ModifiedSubCHigh is a constant for "HIGH".
+	CVSS40ModifiedSubCHigh CVSS40ModifiedSubC = "HIGH"
+	// CVSS40ModifiedSubCNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedSubCNotDefined CVSS40ModifiedSubC = "NOT_DEFINED"
+)
+
+var cvss40ModifiedSubCPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedSubCNone),
+	string(CVSS40ModifiedSubCLow),
+	string(CVSS40ModifiedSubCHigh),
+	string(CVSS40ModifiedSubCNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedSubC) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedSubCPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedSubC(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedSubIa represents the modifiedSubIaType in CVSS40.
+type CVSS40ModifiedSubIa string
+
+const (
+	// CVSS40ModifiedSubIaNone is a constant for "NONE".
+	CVSS40ModifiedSubIaNone CVSS40ModifiedSubIa = "NONE"
+	// CVSS40ModifiedSubIaLow is a constant for "LOW".
+	CVSS40ModifiedSubIaLow CVSS40ModifiedSubIa = "LOW"
+	// CVSS40ModifiedSubIaHigh is a constant for "HIGH".
+	CVSS40ModifiedSubIaHigh CVSS40ModifiedSubIa = "HIGH"
+	// CVSS40ModifiedSubIaSafety is a constant for "SAFETY".
+	CVSS40ModifiedSubIaSafety CVSS40ModifiedSubIa = "SAFETY"
+	// CVSS40ModifiedSubIaNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedSubIaNotDefined CVSS40ModifiedSubIa = "NOT_DEFINED"
+)
+
+var cvss40ModifiedSubIaPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedSubIaNone),
+	string(CVSS40ModifiedSubIaLow),
+	string(CVSS40ModifiedSubIaHigh),
+	string(CVSS40ModifiedSubIaSafety),
+	string(CVSS40ModifiedSubIaNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedSubIa) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedSubIaPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedSubIa(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedUserInteraction represents the modifiedUserInteractionType in CVSS40.
+type CVSS40ModifiedUserInteraction string
+
+const (
+	// CVSS40ModifiedUserInteractionNone is a constant for "NONE".
+	CVSS40ModifiedUserInteractionNone CVSS40ModifiedUserInteraction = "NONE"
+	// CVSS40ModifiedUserInteractionPassive is a constant for "PASSIVE".
+	CVSS40ModifiedUserInteractionPassive CVSS40ModifiedUserInteraction = "PASSIVE"
+	// CVSS40ModifiedUserInteractionActive is a constant for "ACTIVE".
+	CVSS40ModifiedUserInteractionActive CVSS40ModifiedUserInteraction = "ACTIVE"
+	// CVSS40ModifiedUserInteractionNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedUserInteractionNotDefined CVSS40ModifiedUserInteraction = "NOT_DEFINED"
+)
+
+var cvss40ModifiedUserInteractionPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedUserInteractionNone),
+	string(CVSS40ModifiedUserInteractionPassive),
+	string(CVSS40ModifiedUserInteractionActive),
+	string(CVSS40ModifiedUserInteractionNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedUserInteraction) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedUserInteractionPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedUserInteraction(s)
+	}
+	return err
+}
+
+// CVSS40ModifiedVulnCia represents the modifiedVulnCiaType in CVSS40.
+type CVSS40ModifiedVulnCia string
+
+const (
+	// CVSS40ModifiedVulnCiaNone is a constant for "NONE".
+	CVSS40ModifiedVulnCiaNone CVSS40ModifiedVulnCia = "NONE"
+	// CVSS40ModifiedVulnCiaLow is a constant for "LOW".
+	CVSS40ModifiedVulnCiaLow CVSS40ModifiedVulnCia = "LOW"
+	// CVSS40ModifiedVulnCiaHigh is a constant for "HIGH".
+	CVSS40ModifiedVulnCiaHigh CVSS40ModifiedVulnCia = "HIGH"
+	// CVSS40ModifiedVulnCiaNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ModifiedVulnCiaNotDefined CVSS40ModifiedVulnCia = "NOT_DEFINED"
+)
+
+var cvss40ModifiedVulnCiaPattern = alternativesUnmarshal(
+	string(CVSS40ModifiedVulnCiaNone),
+	string(CVSS40ModifiedVulnCiaLow),
+	string(CVSS40ModifiedVulnCiaHigh),
+	string(CVSS40ModifiedVulnCiaNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ModifiedVulnCia) UnmarshalText(data []byte) error {
+	s, err := cvss40ModifiedVulnCiaPattern(data)
+	if err == nil {
+		*e = CVSS40ModifiedVulnCia(s)
+	}
+	return err
+}
+
+// CVSS40PrivilegesRequired represents the privilegesRequiredType in CVSS40.
+type CVSS40PrivilegesRequired string
+
+const (
+	// CVSS40PrivilegesRequiredHigh is a constant for "HIGH".
+	CVSS40PrivilegesRequiredHigh CVSS40PrivilegesRequired = "HIGH"
+	// CVSS40PrivilegesRequiredLow is a constant for "LOW".
+	CVSS40PrivilegesRequiredLow CVSS40PrivilegesRequired = "LOW"
+	// CVSS40PrivilegesRequiredNone is a constant for "NONE".
+	CVSS40PrivilegesRequiredNone CVSS40PrivilegesRequired = "NONE"
+)
+
+var cvss40PrivilegesRequiredPattern = alternativesUnmarshal(
+	string(CVSS40PrivilegesRequiredHigh),
+	string(CVSS40PrivilegesRequiredLow),
+	string(CVSS40PrivilegesRequiredNone),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40PrivilegesRequired) UnmarshalText(data []byte) error {
+	s, err := cvss40PrivilegesRequiredPattern(data)
+	if err == nil {
+		*e = CVSS40PrivilegesRequired(s)
+	}
+	return err
+}
+
+// CVSS40ProviderUrgency represents the providerUrgencyType in CVSS40.
+type CVSS40ProviderUrgency string
+
+const (
+	// CVSS40ProviderUrgencyClear is a constant for "CLEAR".
+	CVSS40ProviderUrgencyClear CVSS40ProviderUrgency = "CLEAR"
+	// CVSS40ProviderUrgencyGreen is a constant for "GREEN".
+	CVSS40ProviderUrgencyGreen CVSS40ProviderUrgency = "GREEN"
+	// CVSS40ProviderUrgencyAmber is a constant for "AMBER".
+	CVSS40ProviderUrgencyAmber CVSS40ProviderUrgency = "AMBER"
+	// CVSS40ProviderUrgencyRed is a constant for "RED".
+	CVSS40ProviderUrgencyRed CVSS40ProviderUrgency = "RED"
+	// CVSS40ProviderUrgencyNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ProviderUrgencyNotDefined CVSS40ProviderUrgency = "NOT_DEFINED"
+)
+
+var cvss40ProviderUrgencyPattern = alternativesUnmarshal(
+	string(CVSS40ProviderUrgencyClear),
+	string(CVSS40ProviderUrgencyGreen),
+	string(CVSS40ProviderUrgencyAmber),
+	string(CVSS40ProviderUrgencyRed),
+	string(CVSS40ProviderUrgencyNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ProviderUrgency) UnmarshalText(data []byte) error {
+	s, err := cvss40ProviderUrgencyPattern(data)
+	if err == nil {
+		*e = CVSS40ProviderUrgency(s)
+	}
+	return err
+}
+
+// CVSS40Recovery represents the recoveryType in CVSS40.
+type CVSS40Recovery string
+
+const (
+	// CVSS40RecoveryAutomatic is a constant for "AUTOMATIC".
+	CVSS40RecoveryAutomatic CVSS40Recovery = "AUTOMATIC"
+	// CVSS40RecoveryUser is a constant for "USER".
+	CVSS40RecoveryUser CVSS40Recovery = "USER"
+	// CVSS40RecoveryIrrecoverable is a constant for "IRRECOVERABLE".
+	CVSS40RecoveryIrrecoverable CVSS40Recovery = "IRRECOVERABLE"
+	// CVSS40RecoveryNotDefined is a constant for "NOT_DEFINED".
+	CVSS40RecoveryNotDefined CVSS40Recovery = "NOT_DEFINED"
+)
+
+var cvss40RecoveryPattern = alternativesUnmarshal(
+	string(CVSS40RecoveryAutomatic),
+	string(CVSS40RecoveryUser),
+	string(CVSS40RecoveryIrrecoverable),
+	string(CVSS40RecoveryNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40Recovery) UnmarshalText(data []byte) error {
+	s, err := cvss40RecoveryPattern(data)
+	if err == nil {
+		*e = CVSS40Recovery(s)
+	}
+	return err
+}
+
+// CVSS40Safety represents the safetyType in CVSS40.
+type CVSS40Safety string
+
+const (
+	// CVSS40SafetyNegligible is a constant for "NEGLIGIBLE".
+	CVSS40SafetyNegligible CVSS40Safety = "NEGLIGIBLE"
+	// CVSS40SafetyPresent is a constant for "PRESENT".
+	CVSS40SafetyPresent CVSS40Safety = "PRESENT"
+	// CVSS40SafetyNotDefined is a constant for "NOT_DEFINED".
+	CVSS40SafetyNotDefined CVSS40Safety = "NOT_DEFINED"
+)
+
+var cvss40SafetyPattern = alternativesUnmarshal(
+	string(CVSS40SafetyNegligible),
+	string(CVSS40SafetyPresent),
+	string(CVSS40SafetyNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40Safety) UnmarshalText(data []byte) error {
+	s, err := cvss40SafetyPattern(data)
+	if err == nil {
+		*e = CVSS40Safety(s)
+	}
+	return err
+}
+
+// CVSS40Severity represents the severityType in CVSS40.
+type CVSS40Severity string
+
+const (
+	// CVSS40SeverityNone is a constant for "NONE".
+	CVSS40SeverityNone CVSS40Severity = "NONE"
+	// CVSS40SeverityLow is a constant for "LOW".
+	CVSS40SeverityLow CVSS40Severity = "LOW"
+	// CVSS40SeverityMedium is a constant for "MEDIUM".
+	CVSS40SeverityMedium CVSS40Severity = "MEDIUM"
+	// CVSS40SeverityHigh is a constant for "HIGH".
+	CVSS40SeverityHigh CVSS40Severity = "HIGH"
+	// CVSS40SeverityCritical is a constant for "CRITICAL".
+	CVSS40SeverityCritical CVSS40Severity = "CRITICAL"
+)
+
+var cvss40SeverityPattern = alternativesUnmarshal(
+	string(CVSS40SeverityNone),
+	string(CVSS40SeverityLow),
+	string(CVSS40SeverityMedium),
+	string(CVSS40SeverityHigh),
+	string(CVSS40SeverityCritical),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40Severity) UnmarshalText(data []byte) error {
+	s, err := cvss40SeverityPattern(data)
+	if err == nil {
+		*e = CVSS40Severity(s)
+	}
+	return err
+}
+
+// CVSS40SubCia represents the subCiaType in CVSS40.
+type CVSS40SubCia string
+
+const (
+	// CVSS40SubCiaNone is a constant for "NONE".
+	CVSS40SubCiaNone CVSS40SubCia = "NONE"
+	// CVSS40SubCiaLow is a constant for "LOW".
+	CVSS40SubCiaLow CVSS40SubCia = "LOW"
+	// CVSS40SubCiaHigh is a constant for "HIGH".
+	CVSS40SubCiaHigh CVSS40SubCia = "HIGH"
+)
+
+var cvss40SubCiaPattern = alternativesUnmarshal(
+	string(CVSS40SubCiaNone),
+	string(CVSS40SubCiaLow),
+	string(CVSS40SubCiaHigh),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40SubCia) UnmarshalText(data []byte) error {
+	s, err := cvss40SubCiaPattern(data)
+	if err == nil {
+		*e = CVSS40SubCia(s)
+	}
+	return err
+}
+
+// CVSS40UserInteraction represents the userInteractionType in CVSS40.
+type CVSS40UserInteraction string
+
+const (
+	// CVSS40UserInteractionNone is a constant for "NONE".
+	CVSS40UserInteractionNone CVSS40UserInteraction = "NONE"
+	// CVSS40UserInteractionPassive is a constant for "PASSIVE".
+	CVSS40UserInteractionPassive CVSS40UserInteraction = "PASSIVE"
+	// CVSS40UserInteractionActive is a constant for "ACTIVE".
+	CVSS40UserInteractionActive CVSS40UserInteraction = "ACTIVE"
+)
+
+var cvss40UserInteractionPattern = alternativesUnmarshal(
+	string(CVSS40UserInteractionNone),
+	string(CVSS40UserInteractionPassive),
+	string(CVSS40UserInteractionActive),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40UserInteraction) UnmarshalText(data []byte) error {
+	s, err := cvss40UserInteractionPattern(data)
+	if err == nil {
+		*e = CVSS40UserInteraction(s)
+	}
+	return err
+}
+
+// CVSS40ValueDensity represents the valueDensityType in CVSS40.
+type CVSS40ValueDensity string
+
+const (
+	// CVSS40ValueDensityDiffuse is a constant for "DIFFUSE".
+	CVSS40ValueDensityDiffuse CVSS40ValueDensity = "DIFFUSE"
+	// CVSS40ValueDensityConcentrated is a constant for "CONCENTRATED".
+	CVSS40ValueDensityConcentrated CVSS40ValueDensity = "CONCENTRATED"
+	// CVSS40ValueDensityNotDefined is a constant for "NOT_DEFINED".
+	CVSS40ValueDensityNotDefined CVSS40ValueDensity = "NOT_DEFINED"
+)
+
+var cvss40ValueDensityPattern = alternativesUnmarshal(
+	string(CVSS40ValueDensityDiffuse),
+	string(CVSS40ValueDensityConcentrated),
+	string(CVSS40ValueDensityNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40ValueDensity) UnmarshalText(data []byte) error {
+	s, err := cvss40ValueDensityPattern(data)
+	if err == nil {
+		*e = CVSS40ValueDensity(s)
+	}
+	return err
+}
+
+// CVSS40VulnCia represents the vulnCiaType in CVSS40.
+type CVSS40VulnCia string
+
+const (
+	// CVSS40VulnCiaNone is a constant for "NONE".
+	CVSS40VulnCiaNone CVSS40VulnCia = "NONE"
+	// CVSS40VulnCiaLow is a constant for "LOW".
+	CVSS40VulnCiaLow CVSS40VulnCia = "LOW"
+	// CVSS40VulnCiaHigh is a constant for "HIGH".
+	CVSS40VulnCiaHigh CVSS40VulnCia = "HIGH"
+)
+
+var cvss40VulnCiaPattern = alternativesUnmarshal(
+	string(CVSS40VulnCiaNone),
+	string(CVSS40VulnCiaLow),
+	string(CVSS40VulnCiaHigh),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40VulnCia) UnmarshalText(data []byte) error {
+	s, err := cvss40VulnCiaPattern(data)
+	if err == nil {
+		*e = CVSS40VulnCia(s)
+	}
+	return err
+}
+
+// CVSS40VulnerabilityResponseEffort represents the vulnerabilityResponseEffortType in CVSS40.
+type CVSS40VulnerabilityResponseEffort string
+
+const (
+	// CVSS40VulnerabilityResponseEffortLow is a constant for "LOW".
+	CVSS40VulnerabilityResponseEffortLow CVSS40VulnerabilityResponseEffort = "LOW"
+	// CVSS40VulnerabilityResponseEffortModerate is a constant for "MODERATE".
+	CVSS40VulnerabilityResponseEffortModerate CVSS40VulnerabilityResponseEffort = "MODERATE"
+	// CVSS40VulnerabilityResponseEffortHigh is a constant for "HIGH".
+	CVSS40VulnerabilityResponseEffortHigh CVSS40VulnerabilityResponseEffort = "HIGH"
+	// CVSS40VulnerabilityResponseEffortNotDefined is a constant for "NOT_DEFINED".
+	CVSS40VulnerabilityResponseEffortNotDefined CVSS40VulnerabilityResponseEffort = "NOT_DEFINED"
+)
+
+var cvss40VulnerabilityResponseEffortPattern = alternativesUnmarshal(
+	string(CVSS40VulnerabilityResponseEffortLow),
+	string(CVSS40VulnerabilityResponseEffortModerate),
+	string(CVSS40VulnerabilityResponseEffortHigh),
+	string(CVSS40VulnerabilityResponseEffortNotDefined),
+)
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (e *CVSS40VulnerabilityResponseEffort) UnmarshalText(data []byte) error {
+	s, err := cvss40VulnerabilityResponseEffortPattern(data)
+	if err == nil {
+		*e = CVSS40VulnerabilityResponseEffort(s)
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCVSS3(t *testing.T) {
+	for _, tc := range []struct {
+		vector        string
+		base          float64
+		temporal      float64
+		environmental float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8, -1, -1},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/E:P/RL:O/RC:C", 9.8, 8.8, -1},
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H/CR:L/IR:L/AR:L", 9.8, -1, 8.0},
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N/MS:C/MPR:N", 5.5, -1, 7.1},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N/E:H/MAV:L", 6.1, 6.1, 5.0},
+	} {
+		c, err := ParseCVSS3(tc.vector)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vector, err)
+		}
+		if c.BaseScore == nil || *c.BaseScore != tc.base {
+			t.Errorf("%s: expected base score %.1f, got %v", tc.vector, tc.base, c.BaseScore)
+		}
+		if c.BaseSeverity == nil || *c.BaseSeverity != cvss3Severity(tc.base) {
+			t.Errorf("%s: unexpected base severity %v", tc.vector, c.BaseSeverity)
+		}
+		for _, s := range []struct {
+			name  string
+			want  float64
+			score *float64
+		}{
+			{"temporal", tc.temporal, c.TemporalScore},
+			{"environmental", tc.environmental, c.EenvironmentalScore},
+		} {
+			switch {
+			case s.want < 0 && s.score != nil:
+				t.Errorf("%s: expected no %s score, got %.1f", tc.vector, s.name, *s.score)
+			case s.want >= 0 && (s.score == nil || *s.score != s.want):
+				t.Errorf("%s: expected %s score %.1f, got %v", tc.vector, s.name, s.want, s.score)
+			}
+		}
+		if c.Version == nil || *c.Version != CVSSVersion3(tc.vector[5:8]) {
+			t.Errorf("%s: unexpected version %v", tc.vector, c.Version)
+		}
+		if mismatches, err := c.Mismatches(); err != nil || len(mismatches) > 0 {
+			t.Errorf("%s: expected no mismatches, got %v (%v)", tc.vector, mismatches, err)
+		}
+	}
+
+	if c, _ := ParseCVSS3("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"); c.AttackVector == nil ||
+		*c.AttackVector != CVSS3AttackVectorNetwork || c.IntegrityImpact != CVSS3CiaHigh {
+		t.Errorf("unexpected metrics %+v", c)
+	}
+
+	for _, vector := range []string{
+		"AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.2/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+		"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H",
+		"CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+	} {
+		if _, err := ParseCVSS3(vector); err == nil {
+			t.Errorf("%s: expected invalid vector to fail", vector)
+		}
+	}
+}
+
+func TestParseCVSS2(t *testing.T) {
+	for _, tc := range []struct {
+		vector        string
+		base          float64
+		temporal      float64
+		environmental float64
+	}{
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", 7.5, -1, -1},
+		{"AV:N/AC:L/Au:N/C:N/I:N/A:C/E:F/RL:OF/RC:C", 7.8, 6.4, -1},
+		{"AV:N/AC:L/Au:N/C:N/I:N/A:C/E:F/RL:OF/RC:C/CDP:H/TD:H/CR:M/IR:M/AR:H", 7.8, 6.4, 9.2},
+	} {
+		c, err := ParseCVSS2(tc.vector)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vector, err)
+		}
+		if c.BaseScore == nil || *c.BaseScore != tc.base {
+			t.Errorf("%s: expected base score %.1f, got %v", tc.vector, tc.base, c.BaseScore)
+		}
+		for _, s := range []struct {
+			name  string
+			want  float64
+			score *float64
+		}{
+			{"temporal", tc.temporal, c.TemporalScore},
+			{"environmental", tc.environmental, c.EnvironmentalScore},
+		} {
+			switch {
+			case s.want < 0 && s.score != nil:
+				t.Errorf("%s: expected no %s score, got %.1f", tc.vector, s.name, *s.score)
+			case s.want >= 0 && (s.score == nil || *s.score != s.want):
+				t.Errorf("%s: expected %s score %.1f, got %v", tc.vector, s.name, s.want, s.score)
+			}
+		}
+		if mismatches, err := c.Mismatches(); err != nil || len(mismatches) > 0 {
+			t.Errorf("%s: expected no mismatches, got %v (%v)", tc.vector, mismatches, err)
+		}
+	}
+}
+
+func TestParseCVSS4(t *testing.T) {
+	for _, tc := range []struct {
+		vector        string
+		base          float64
+		threat        float64
+		environmental float64
+	}{
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H", 10, -1, -1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", 9.3, -1, -1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:U", 9.3, 8.1, -1},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N/E:P/CR:L/IR:L/AR:L", 9.3, 8.9, 7.9},
+		{"CVSS:4.0/AV:L/AC:L/AT:N/PR:L/UI:N/VC:H/VI:N/VA:N/SC:N/SI:N/SA:N/MAV:N/MPR:N", 6.8, -1, 8.7},
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:N/VI:N/VA:N/SC:N/SI:N/SA:N", 0, -1, -1},
+	} {
+		c, err := ParseCVSS4(tc.vector)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vector, err)
+		}
+		if c.BaseScore == nil || *c.BaseScore != tc.base {
+			t.Errorf("%s: expected base score %.1f, got %v", tc.vector, tc.base, c.BaseScore)
+		}
+		if c.BaseSeverity == nil || string(*c.BaseSeverity) != string(cvss3Severity(tc.base)) {
+			t.Errorf("%s: unexpected base severity %v", tc.vector, c.BaseSeverity)
+		}
+		for _, s := range []struct {
+			name  string
+			want  float64
+			score *float64
+		}{
+			{"threat", tc.threat, c.ThreatScore},
+			{"environmental", tc.environmental, c.EnvironmentalScore},
+		} {
+			switch {
+			case s.want < 0 && s.score != nil:
+				t.Errorf("%s: expected no %s score, got %.1f", tc.vector, s.name, *s.score)
+			case s.want >= 0 && (s.score == nil || *s.score != s.want):
+				t.Errorf("%s: expected %s score %.1f, got %v", tc.vector, s.name, s.want, s.score)
+			}
+		}
+		if mismatches, err := c.Mismatches(); err != nil || len(mismatches) > 0 {
+			t.Errorf("%s: expected no mismatches, got %v (%v)", tc.vector, mismatches, err)
+		}
+	}
+
+	if c, _ := ParseCVSS4("CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H"); c.AttackVector == nil ||
+		*c.AttackVector != CVSS40AttackVectorNetwork {
+		t.Errorf("unexpected metrics %+v", c)
+	}
+
+	for _, vector := range []string{
+		"AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H",
+		"CVSS:4.1/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H",
+		"CVSS:4.0/AC:L/AV:N/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:H/SA:H",
+		"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:H/SI:S/SA:H",
+	} {
+		if _, err := ParseCVSS4(vector); err == nil {
+			t.Errorf("%s: expected invalid vector to fail", vector)
+		}
+	}
+}
+
+func TestCVSSMismatches(t *testing.T) {
+	c, err := ParseCVSS3("CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := CVSS3AttackVectorLocal
+	score := 9.1
+	severity := CVSS3SeverityHigh
+	rc := CVSS3ConfidenceUnknown
+	c.AttackVector = &local
+	c.BaseScore = &score
+	c.BaseSeverity = &severity
+	c.ReportConfidence = &rc
+
+	mismatches, err := c.Mismatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CVSSMismatch{
+		{Property: "attackVector", Value: "LOCAL", Expected: "NETWORK"},
+		{Property: "reportConfidence", Value: "UNKNOWN", Expected: "NOT_DEFINED"},
+		{Property: "baseScore", Value: "9.1", Expected: "9.8"},
+		{Property: "baseSeverity", Value: "HIGH", Expected: "CRITICAL"},
+	}
+	if !reflect.DeepEqual(mismatches, want) {
+		t.Errorf("expected %v, got %v", want, mismatches)
+	}
+	var computed int
+	for i := range mismatches {
+		if mismatches[i].Computed() {
+			computed++
+		}
+	}
+	if computed != 2 {
+		t.Errorf("expected two computed mismatches, got %d", computed)
+	}
+
+	if _, err := (&CVSS3{}).Mismatches(); err == nil {
+		t.Error("expected missing vector to fail")
+	}
+}
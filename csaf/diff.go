@@ -0,0 +1,456 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiffChange is the kind of change of a vulnerability.
+type DiffChange string
+
+const (
+	// DiffAdded marks an element which only exists in the newer advisory.
+	DiffAdded DiffChange = "added"
+	// DiffRemoved marks an element which only exists in the older advisory.
+	DiffRemoved DiffChange = "removed"
+	// DiffModified marks an element which exists in both advisories but differs.
+	DiffModified DiffChange = "modified"
+)
+
+// ProductChange is a product added to or removed from the product tree.
+type ProductChange struct {
+	ProductID ProductID `json:"product_id"`
+	Name      string    `json:"name,omitempty"`
+}
+
+// ProductStatusChange is a change of the status of a product.
+// Old and New are the names of the product status lists
+// the product is in, e.g. "known_affected" or "fixed".
+type ProductStatusChange struct {
+	ProductID ProductID `json:"product_id"`
+	Old       []string  `json:"old,omitempty"`
+	New       []string  `json:"new,omitempty"`
+}
+
+// ScoreChange is a change of a score of a product for a CVSS version.
+type ScoreChange struct {
+	ProductID ProductID `json:"product_id"`
+	Version   string    `json:"version"`
+	OldVector string    `json:"old_vector,omitempty"`
+	OldScore  *float64  `json:"old_score,omitempty"`
+	NewVector string    `json:"new_vector,omitempty"`
+	NewScore  *float64  `json:"new_score,omitempty"`
+}
+
+// VulnerabilityDiff are the differences of a vulnerability.
+// Key identifies the vulnerability. It is the CVE if present,
+// otherwise the first of the ids or the title.
+type VulnerabilityDiff struct {
+	Key                 string                `json:"key"`
+	Change              DiffChange            `json:"change"`
+	ProductStatus       []ProductStatusChange `json:"product_status,omitempty"`
+	AddedRemediations   []*Remediation        `json:"added_remediations,omitempty"`
+	RemovedRemediations []*Remediation        `json:"removed_remediations,omitempty"`
+	Scores              []ScoreChange         `json:"scores,omitempty"`
+}
+
+// AdvisoryDiff are the differences between two revisions of an advisory.
+type AdvisoryDiff struct {
+	TrackingID       TrackingID          `json:"tracking_id"`
+	OldVersion       RevisionNumber      `json:"old_version"`
+	NewVersion       RevisionNumber      `json:"new_version"`
+	AddedProducts    []ProductChange     `json:"added_products,omitempty"`
+	RemovedProducts  []ProductChange     `json:"removed_products,omitempty"`
+	Vulnerabilities  []VulnerabilityDiff `json:"vulnerabilities,omitempty"`
+	AddedRevisions   []*Revision         `json:"added_revisions,omitempty"`
+	RemovedRevisions []*Revision         `json:"removed_revisions,omitempty"`
+}
+
+// Empty returns true if there are no differences.
+func (ad *AdvisoryDiff) Empty() bool {
+	return len(ad.AddedProducts) == 0 &&
+		len(ad.RemovedProducts) == 0 &&
+		len(ad.Vulnerabilities) == 0 &&
+		len(ad.AddedRevisions) == 0 &&
+		len(ad.RemovedRevisions) == 0
+}
+
+// trackingID returns the tracking id of the advisory or an empty string.
+func (adv *Advisory) trackingID() TrackingID {
+	if doc := adv.Document; doc != nil && doc.Tracking != nil && doc.Tracking.ID != nil {
+		return *doc.Tracking.ID
+	}
+	return ""
+}
+
+// version returns the version of the advisory or an empty string.
+func (adv *Advisory) version() RevisionNumber {
+	if doc := adv.Document; doc != nil && doc.Tracking != nil && doc.Tracking.Version != nil {
+		return *doc.Tracking.Version
+	}
+	return ""
+}
+
+// Diff returns the differences between the advisory and a newer
+// revision of it. It is an error if the tracking ids differ.
+func (adv *Advisory) Diff(newer *Advisory) (*AdvisoryDiff, error) {
+	if o, n := adv.trackingID(), newer.trackingID(); o != n {
+		return nil, fmt.Errorf("tracking ids differ: %q != %q", o, n)
+	}
+	ad := &AdvisoryDiff{
+		TrackingID: adv.trackingID(),
+		OldVersion: adv.version(),
+		NewVersion: newer.version(),
+	}
+	ad.diffProducts(adv, newer)
+	ad.diffVulnerabilities(adv, newer)
+	ad.diffRevisions(adv, newer)
+	return ad, nil
+}
+
+// productNames returns the defined products with their names
+// in order of their definition.
+func (adv *Advisory) productNames() ([]ProductID, map[ProductID]string) {
+	var ids []ProductID
+	names := map[ProductID]string{}
+	if adv.ProductTree == nil {
+		return ids, names
+	}
+	adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, _ string) {
+		if fpn.ProductID == nil {
+			return
+		}
+		id := *fpn.ProductID
+		if _, ok := names[id]; ok {
+			return
+		}
+		ids = append(ids, id)
+		if fpn.Name != nil {
+			names[id] = *fpn.Name
+		} else {
+			names[id] = ""
+		}
+	})
+	return ids, names
+}
+
+// diffProducts collects the added and removed products.
+func (ad *AdvisoryDiff) diffProducts(older, newer *Advisory) {
+	oldIDs, oldNames := older.productNames()
+	newIDs, newNames := newer.productNames()
+	for _, id := range newIDs {
+		if _, ok := oldNames[id]; !ok {
+			ad.AddedProducts = append(ad.AddedProducts,
+				ProductChange{ProductID: id, Name: newNames[id]})
+		}
+	}
+	for _, id := range oldIDs {
+		if _, ok := newNames[id]; !ok {
+			ad.RemovedProducts = append(ad.RemovedProducts,
+				ProductChange{ProductID: id, Name: oldNames[id]})
+		}
+	}
+}
+
+// key returns the key identifying the vulnerability.
+func (v *Vulnerability) key() string {
+	if v.CVE != nil {
+		return string(*v.CVE)
+	}
+	for _, id := range v.IDs {
+		if id != nil && id.SystemName != nil && id.Text != nil {
+			return *id.SystemName + ":" + *id.Text
+		}
+	}
+	if v.Title != nil {
+		return *v.Title
+	}
+	return ""
+}
+
+// vulnerabilityKeys returns the keys of the vulnerabilities in order
+// and the vulnerabilities indexed by them. Vulnerabilities without
+// key are keyed by their position.
+func (adv *Advisory) vulnerabilityKeys() ([]string, map[string]*Vulnerability) {
+	var keys []string
+	vulns := map[string]*Vulnerability{}
+	for i, v := range adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		key := v.key()
+		if _, dup := vulns[key]; key == "" || dup {
+			key = fmt.Sprintf("#%d", i+1)
+		}
+		keys = append(keys, key)
+		vulns[key] = v
+	}
+	return keys, vulns
+}
+
+// diffVulnerabilities collects the changes of the vulnerabilities.
+func (ad *AdvisoryDiff) diffVulnerabilities(older, newer *Advisory) {
+	oldKeys, oldVulns := older.vulnerabilityKeys()
+	newKeys, newVulns := newer.vulnerabilityKeys()
+	empty := &Vulnerability{}
+	for _, key := range newKeys {
+		change := DiffModified
+		o := oldVulns[key]
+		if o == nil {
+			o, change = empty, DiffAdded
+		}
+		if vd := diffVulnerability(key, change, o, newVulns[key]); vd != nil {
+			ad.Vulnerabilities = append(ad.Vulnerabilities, *vd)
+		}
+	}
+	for _, key := range oldKeys {
+		if newVulns[key] == nil {
+			ad.Vulnerabilities = append(ad.Vulnerabilities,
+				*diffVulnerability(key, DiffRemoved, oldVulns[key], empty))
+		}
+	}
+}
+
+// diffVulnerability returns the differences of two vulnerabilities.
+// It returns nil if a modified vulnerability has no differences.
+func diffVulnerability(key string, change DiffChange, older, newer *Vulnerability) *VulnerabilityDiff {
+	vd := &VulnerabilityDiff{
+		Key:           key,
+		Change:        change,
+		ProductStatus: diffProductStatus(older.ProductStatus, newer.ProductStatus),
+		Scores:        diffScores(older.AllScores(), newer.AllScores()),
+	}
+	vd.AddedRemediations, vd.RemovedRemediations = diffRemediations(
+		older.Remediations, newer.Remediations)
+	if change == DiffModified &&
+		len(vd.ProductStatus) == 0 &&
+		len(vd.Scores) == 0 &&
+		len(vd.AddedRemediations) == 0 &&
+		len(vd.RemovedRemediations) == 0 {
+		return nil
+	}
+	return vd
+}
+
+// statusLists returns the names of the product status lists
+// a product is in.
+func (ps *ProductStatus) statusLists() map[ProductID][]string {
+	lists := map[ProductID][]string{}
+	if ps == nil {
+		return lists
+	}
+	for _, l := range ps.lists() {
+		if l.products == nil {
+			continue
+		}
+		for _, p := range *l.products {
+			if p != nil {
+				lists[*p] = append(lists[*p], l.name)
+			}
+		}
+	}
+	return lists
+}
+
+// sortedProductIDs returns the union of the keys of the maps sorted.
+func sortedProductIDs[V any](ms ...map[ProductID]V) []ProductID {
+	var ids []ProductID
+	seen := map[ProductID]bool{}
+	for _, m := range ms {
+		for id := range m {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// diffProductStatus returns the changes of the product status.
+func diffProductStatus(older, newer *ProductStatus) []ProductStatusChange {
+	oldLists, newLists := older.statusLists(), newer.statusLists()
+	var changes []ProductStatusChange
+	for _, id := range sortedProductIDs(oldLists, newLists) {
+		o, n := oldLists[id], newLists[id]
+		if strings.Join(o, ",") != strings.Join(n, ",") {
+			changes = append(changes, ProductStatusChange{
+				ProductID: id,
+				Old:       o,
+				New:       n,
+			})
+		}
+	}
+	return changes
+}
+
+// diffRemediations returns the added and removed remediations.
+// Remediations are considered equal if their JSON encodings are equal.
+func diffRemediations(older, newer Remediations) ([]*Remediation, []*Remediation) {
+	encode := func(r *Remediation) string {
+		data, _ := json.Marshal(r)
+		return string(data)
+	}
+	contains := func(rs Remediations, enc string) bool {
+		for _, r := range rs {
+			if r != nil && encode(r) == enc {
+				return true
+			}
+		}
+		return false
+	}
+	var added, removed []*Remediation
+	for _, r := range newer {
+		if r != nil && !contains(older, encode(r)) {
+			added = append(added, r)
+		}
+	}
+	for _, r := range older {
+		if r != nil && !contains(newer, encode(r)) {
+			removed = append(removed, r)
+		}
+	}
+	return added, removed
+}
+
+// scoreValue is a score of a product for a CVSS version.
+type scoreValue struct {
+	vector string
+	score  *float64
+}
+
+// productScores returns the scores indexed by CVSS version and product.
+func (ss Scores) productScores() map[string]map[ProductID]scoreValue {
+	scores := map[string]map[ProductID]scoreValue{}
+	add := func(version string, sv scoreValue, products *Products) {
+		if products == nil {
+			return
+		}
+		m := scores[version]
+		if m == nil {
+			m = map[ProductID]scoreValue{}
+			scores[version] = m
+		}
+		for _, p := range *products {
+			if p != nil {
+				m[*p] = sv
+			}
+		}
+	}
+	for _, s := range ss {
+		if s == nil {
+			continue
+		}
+		if c := s.CVSS2; c != nil {
+			sv := scoreValue{score: c.BaseScore}
+			if c.VectorString != nil {
+				sv.vector = string(*c.VectorString)
+			}
+			add(string(CVSSVersion20), sv, s.Products)
+		}
+		if c := s.CVSS3; c != nil {
+			sv := scoreValue{score: c.BaseScore}
+			if c.VectorString != nil {
+				sv.vector = string(*c.VectorString)
+			}
+			version := "3"
+			if c.Version != nil {
+				version = string(*c.Version)
+			}
+			add(version, sv, s.Products)
+		}
+		if c := s.CVSS4; c != nil {
+			sv := scoreValue{score: c.BaseScore}
+			if c.VectorString != nil {
+				sv.vector = string(*c.VectorString)
+			}
+			add(string(CVSSVersion40), sv, s.Products)
+		}
+	}
+	return scores
+}
+
+// diffScores returns the changes of the scores.
+func diffScores(older, newer Scores) []ScoreChange {
+	oldScores, newScores := older.productScores(), newer.productScores()
+	versions := map[string]bool{}
+	for v := range oldScores {
+		versions[v] = true
+	}
+	for v := range newScores {
+		versions[v] = true
+	}
+	sortedVersions := make([]string, 0, len(versions))
+	for v := range versions {
+		sortedVersions = append(sortedVersions, v)
+	}
+	sort.Strings(sortedVersions)
+
+	equal := func(a, b *float64) bool {
+		return a == nil && b == nil || a != nil && b != nil && *a == *b
+	}
+
+	var changes []ScoreChange
+	for _, version := range sortedVersions {
+		o, n := oldScores[version], newScores[version]
+		for _, id := range sortedProductIDs(o, n) {
+			os, hasOld := o[id]
+			ns, hasNew := n[id]
+			if hasOld && hasNew && os.vector == ns.vector && equal(os.score, ns.score) {
+				continue
+			}
+			changes = append(changes, ScoreChange{
+				ProductID: id,
+				Version:   version,
+				OldVector: os.vector,
+				OldScore:  os.score,
+				NewVector: ns.vector,
+				NewScore:  ns.score,
+			})
+		}
+	}
+	return changes
+}
+
+// diffRevisions collects the added and removed revisions.
+// Revisions are identified by their numbers.
+func (ad *AdvisoryDiff) diffRevisions(older, newer *Advisory) {
+	revisions := func(adv *Advisory) Revisions {
+		if doc := adv.Document; doc != nil && doc.Tracking != nil {
+			return doc.Tracking.RevisionHistory
+		}
+		return nil
+	}
+	numbers := func(revs Revisions) map[RevisionNumber]bool {
+		m := map[RevisionNumber]bool{}
+		for _, r := range revs {
+			if r != nil && r.Number != nil {
+				m[*r.Number] = true
+			}
+		}
+		return m
+	}
+	oldRevs, newRevs := revisions(older), revisions(newer)
+	oldNumbers, newNumbers := numbers(oldRevs), numbers(newRevs)
+	for _, r := range newRevs {
+		if r != nil && r.Number != nil && !oldNumbers[*r.Number] {
+			ad.AddedRevisions = append(ad.AddedRevisions, r)
+		}
+	}
+	for _, r := range oldRevs {
+		if r != nil && r.Number != nil && !newNumbers[*r.Number] {
+			ad.RemovedRevisions = append(ad.RemovedRevisions, r)
+		}
+	}
+}
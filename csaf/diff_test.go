@@ -0,0 +1,159 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// loadTestAdvisoryModel loads the test advisory as an Advisory.
+func loadTestAdvisoryModel(t *testing.T, modify func(doc map[string]any)) *Advisory {
+	t.Helper()
+	var adv Advisory
+	if err := util.ReMarshalJSON(&adv, loadTestAdvisory(t, modify)); err != nil {
+		t.Fatal(err)
+	}
+	return &adv
+}
+
+func TestAdvisoryDiffEmpty(t *testing.T) {
+	older := loadTestAdvisoryModel(t, nil)
+	newer := loadTestAdvisoryModel(t, nil)
+	diff, err := older.Diff(newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !diff.Empty() {
+		t.Errorf("expected no differences, got %+v", diff)
+	}
+}
+
+func TestAdvisoryDiffTrackingID(t *testing.T) {
+	older := loadTestAdvisoryModel(t, nil)
+	newer := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		walkJSON(doc, "document", "tracking").(map[string]any)["id"] = "OTHER-2023-0001"
+	})
+	if _, err := older.Diff(newer); err == nil {
+		t.Error("expected differing tracking ids to fail")
+	}
+}
+
+func TestAdvisoryDiff(t *testing.T) {
+	older := loadTestAdvisoryModel(t, nil)
+	newer := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		tracking := walkJSON(doc, "document", "tracking").(map[string]any)
+		tracking["version"] = "3"
+		tracking["revision_history"] = append(
+			tracking["revision_history"].([]any),
+			map[string]any{
+				"date":    "2023-03-01T10:00:00.000Z",
+				"number":  "3",
+				"summary": "Fixed in 1.2.",
+			})
+
+		versions := walkJSON(doc, "product_tree", "branches", 0, "branches", 0).(map[string]any)
+		versions["branches"] = append(versions["branches"].([]any),
+			map[string]any{
+				"category": "product_version",
+				"name":     "1.2",
+				"product": map[string]any{
+					"name":       "Example Company Product 1.2",
+					"product_id": "CSAFPID-0003",
+				},
+			})
+
+		v := walkJSON(doc, "vulnerabilities", 0).(map[string]any)
+		v["product_status"] = map[string]any{
+			"known_affected": []any{"CSAFPID-0001", "CSAFPID-0002"},
+			"fixed":          []any{"CSAFPID-0003"},
+		}
+		v["remediations"] = append(v["remediations"].([]any),
+			map[string]any{
+				"category":    "vendor_fix",
+				"details":     "Update to 1.2.",
+				"product_ids": []any{"CSAFPID-0001", "CSAFPID-0002"},
+			})
+		c := walkJSON(v, "scores", 0, "cvss_v3").(map[string]any)
+		c["vectorString"] = "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:N"
+		c["baseScore"] = 9.1
+
+		doc["vulnerabilities"] = append(doc["vulnerabilities"].([]any),
+			map[string]any{
+				"cve":            "CVE-2023-0002",
+				"product_status": map[string]any{"known_affected": []any{"CSAFPID-0002"}},
+			})
+	})
+
+	diff, err := older.Diff(newer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff.OldVersion != "2" || diff.NewVersion != "3" {
+		t.Errorf("unexpected versions %q -> %q", diff.OldVersion, diff.NewVersion)
+	}
+	if want := []ProductChange{{"CSAFPID-0003", "Example Company Product 1.2"}}; !reflect.DeepEqual(diff.AddedProducts, want) {
+		t.Errorf("added products: expected %v, got %v", want, diff.AddedProducts)
+	}
+	if len(diff.RemovedProducts) != 0 {
+		t.Errorf("expected no removed products, got %v", diff.RemovedProducts)
+	}
+	if len(diff.AddedRevisions) != 1 || *diff.AddedRevisions[0].Number != "3" {
+		t.Errorf("expected revision 3 to be added, got %v", diff.AddedRevisions)
+	}
+
+	if len(diff.Vulnerabilities) != 2 {
+		t.Fatalf("expected 2 changed vulnerabilities, got %d", len(diff.Vulnerabilities))
+	}
+
+	vd := diff.Vulnerabilities[0]
+	if vd.Key != "CVE-2023-0001" || vd.Change != DiffModified {
+		t.Errorf("unexpected vulnerability %q (%s)", vd.Key, vd.Change)
+	}
+	wantStatus := []ProductStatusChange{
+		{ProductID: "CSAFPID-0002", Old: []string{"fixed"}, New: []string{"known_affected"}},
+		{ProductID: "CSAFPID-0003", New: []string{"fixed"}},
+	}
+	if !reflect.DeepEqual(vd.ProductStatus, wantStatus) {
+		t.Errorf("product status: expected %+v, got %+v", wantStatus, vd.ProductStatus)
+	}
+	if len(vd.AddedRemediations) != 1 || *vd.AddedRemediations[0].Details != "Update to 1.2." {
+		t.Errorf("unexpected added remediations %v", vd.AddedRemediations)
+	}
+	if len(vd.RemovedRemediations) != 0 {
+		t.Errorf("expected no removed remediations, got %v", vd.RemovedRemediations)
+	}
+	if len(vd.Scores) != 1 {
+		t.Fatalf("expected one score change, got %d", len(vd.Scores))
+	}
+	if sc := vd.Scores[0]; sc.ProductID != "CSAFPID-0001" || sc.Version != "3.1" ||
+		*sc.OldScore != 9.8 || *sc.NewScore != 9.1 {
+		t.Errorf("unexpected score change %+v", sc)
+	}
+
+	if vd := diff.Vulnerabilities[1]; vd.Key != "CVE-2023-0002" || vd.Change != DiffAdded ||
+		len(vd.ProductStatus) != 1 {
+		t.Errorf("unexpected vulnerability change %+v", vd)
+	}
+
+	// The other direction.
+	reverse, err := newer.Diff(older)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverse.RemovedProducts) != 1 || len(reverse.RemovedRevisions) != 1 {
+		t.Errorf("expected removed product and revision, got %+v", reverse)
+	}
+	if vd := reverse.Vulnerabilities[len(reverse.Vulnerabilities)-1]; vd.Change != DiffRemoved {
+		t.Errorf("expected removed vulnerability, got %+v", vd)
+	}
+}
@@ -13,3 +13,4 @@ package csaf
 // Generating only enums for CVSS 3.0 and not for 3.1 since the enums of both of them
 // are identical.
 //go:generate go run ./generate_cvss_enums.go -o cvss3enums.go -i ./schema/cvss-v3.0.json -p CVSS3
+//go:generate go run ./generate_cvss_enums.go -o cvss40enums.go -i ./schema/cvss-v4.0.json -p CVSS40
@@ -0,0 +1,83 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import "fmt"
+
+// informativePreset are the informative tests of section 6.3
+// of the CSAF 2.0 specification.
+// Not covered are the tests 6.3.6 and 6.3.7 (URLs failing to resolve)
+// and 6.3.8 (Spell check) as they need network access or dictionaries.
+var informativePreset = testPreset{
+	level: infoLevel,
+	tests: []advisoryTest{
+		{"informativeTest_6_3_1", (*testEnv).onlyCVSS2},
+		{"informativeTest_6_3_2", (*testEnv).useOfCVSS30},
+		{"informativeTest_6_3_3", (*testEnv).missingCVE},
+		{"informativeTest_6_3_4", (*testEnv).missingCWE},
+		{"informativeTest_6_3_5", (*testEnv).shortHashes},
+	},
+}
+
+// onlyCVSS2 implements test 6.3.1.
+func (env *testEnv) onlyCVSS2(report testReporter) {
+	env.visitScores(func(s *Score, path string) {
+		if s.CVSS2 != nil && s.CVSS3 == nil && s.CVSS4 == nil {
+			report(path, "CVSS v2 is the only scoring system used")
+		}
+	})
+}
+
+// useOfCVSS30 implements test 6.3.2.
+func (env *testEnv) useOfCVSS30(report testReporter) {
+	env.visitScores(func(s *Score, path string) {
+		if s.CVSS3 != nil && s.CVSS3.Version != nil && *s.CVSS3.Version == CVSSVersion30 {
+			report(path+"/cvss_v3/version", "CVSS v3.0 is used instead of v3.1")
+		}
+	})
+}
+
+// missingCVE implements test 6.3.3.
+func (env *testEnv) missingCVE(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.CVE == nil {
+			report(path, "vulnerability has no CVE")
+		}
+	})
+}
+
+// missingCWE implements test 6.3.4.
+func (env *testEnv) missingCWE(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.CWE == nil && len(v.CWEs) == 0 {
+			report(path, "vulnerability has no CWE")
+		}
+	})
+}
+
+// shortHashes implements test 6.3.5.
+func (env *testEnv) shortHashes(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	const minLength = 64
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		h := fpn.ProductIdentificationHelper
+		if h == nil || h.Hashes == nil {
+			return
+		}
+		for i, fh := range h.Hashes.FileHashes {
+			if fh != nil && fh.Value != nil && len(*fh.Value) < minLength {
+				report(fmt.Sprintf(
+					"%s/product_identification_helper/hashes/file_hashes/%d/value", path, i),
+					"hash value is shorter than %d characters", minLength)
+			}
+		}
+	})
+}
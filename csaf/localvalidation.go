@@ -0,0 +1,164 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"fmt"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// LocalValidatorOptions are the configuration options
+// of the built-in validator.
+type LocalValidatorOptions struct {
+	Presets []string `json:"presets" toml:"presets"`
+}
+
+// testReporter is used by the tests to report their findings.
+// path is the JSON pointer to the offending element.
+type testReporter func(path, format string, args ...any)
+
+// advisoryTest is a single test of the specification
+// which is performed on an advisory.
+type advisoryTest struct {
+	name string
+	run  func(env *testEnv, report testReporter)
+}
+
+// testPreset is a named set of tests.
+type testPreset struct {
+	tests []advisoryTest
+	// level is the result list where findings are stored.
+	level func(*RemoteTest) *[]RemoteTestResult
+}
+
+// errorLevel stores findings as errors.
+func errorLevel(rt *RemoteTest) *[]RemoteTestResult { return &rt.Error }
+
+// warningLevel stores findings as warnings.
+func warningLevel(rt *RemoteTest) *[]RemoteTestResult { return &rt.Warning }
+
+// infoLevel stores findings as infos.
+func infoLevel(rt *RemoteTest) *[]RemoteTestResult { return &rt.Info }
+
+// testPresets are the presets which can be run locally.
+// The names follow the ones of the remote validation service.
+// "recommended" is an alias for "optional" as the specification
+// calls these tests recommended.
+var testPresets = map[string][]*testPreset{
+	"schema":      nil, // Schema validation is done by ValidateCSAF.
+	"mandatory":   {&mandatoryPreset},
+	"optional":    {&optionalPreset},
+	"recommended": {&optionalPreset},
+	"informative": {&informativePreset},
+	"basic":       {&mandatoryPreset},
+	"extended":    {&mandatoryPreset, &optionalPreset},
+	"full":        {&mandatoryPreset, &optionalPreset, &informativePreset},
+}
+
+// localValidator is an implementation of a RemoteValidator
+// which runs the tests in process.
+type localValidator struct {
+	presets []*testPreset
+}
+
+// preparePresets resolves the preset names to the presets.
+func preparePresets(names []string) ([]*testPreset, error) {
+	if len(names) == 0 {
+		names = defaultPresets
+	}
+	var presets []*testPreset
+	for _, name := range names {
+		ps, ok := testPresets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown preset %q", name)
+		}
+	next:
+		for _, p := range ps {
+			for _, q := range presets {
+				if p == q {
+					continue next
+				}
+			}
+			presets = append(presets, p)
+		}
+	}
+	return presets, nil
+}
+
+// Open opens a new local validator.
+// The returned validator runs the tests of the configured presets
+// in process and does not need any external service.
+func (lvo *LocalValidatorOptions) Open() (RemoteValidator, error) {
+	presets, err := preparePresets(lvo.Presets)
+	if err != nil {
+		return nil, err
+	}
+	return &localValidator{presets: presets}, nil
+}
+
+// Close implements the closing part of the RemoteValidator interface.
+func (*localValidator) Close() error { return nil }
+
+// Validate implements the validation part of the RemoteValidator interface.
+// The document is converted into an Advisory before it is tested.
+// If this fails the result is invalid.
+// Findings of the optional and informative tests are reported
+// as warnings and infos and do not render the document invalid.
+func (lv *localValidator) Validate(doc any) (*RemoteValidationResult, error) {
+	var adv Advisory
+	if err := util.ReMarshalJSON(&adv, doc); err != nil {
+		return &RemoteValidationResult{
+			Valid: false,
+			Tests: []RemoteTest{{
+				Name:  "advisory",
+				Valid: false,
+				Error: []RemoteTestResult{{
+					Message:      fmt.Sprintf("document is not an advisory: %v", err),
+					InstancePath: "",
+				}},
+			}},
+		}, nil
+	}
+	return runPresets(&adv, lv.presets), nil
+}
+
+// ValidateAdvisory runs the tests of the given presets on an advisory.
+// If no presets are given the mandatory tests are run.
+func ValidateAdvisory(adv *Advisory, presets ...string) (*RemoteValidationResult, error) {
+	ps, err := preparePresets(presets)
+	if err != nil {
+		return nil, err
+	}
+	return runPresets(adv, ps), nil
+}
+
+// runPresets runs all tests of the given presets.
+func runPresets(adv *Advisory, presets []*testPreset) *RemoteValidationResult {
+	env := newTestEnv(adv)
+	rvr := RemoteValidationResult{Valid: true}
+	for _, p := range presets {
+		for _, t := range p.tests {
+			rt := RemoteTest{Name: t.name}
+			results := p.level(&rt)
+			t.run(env, func(path, format string, args ...any) {
+				*results = append(*results, RemoteTestResult{
+					Message:      fmt.Sprintf(format, args...),
+					InstancePath: path,
+				})
+			})
+			rt.Valid = len(rt.Error) == 0
+			if !rt.Valid {
+				rvr.Valid = false
+			}
+			rvr.Tests = append(rvr.Tests, rt)
+		}
+	}
+	return &rvr
+}
@@ -0,0 +1,490 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// testAdvisory is a minimal valid security advisory
+// which passes all tests.
+const testAdvisory = `{
+  "document": {
+    "category": "csaf_security_advisory",
+    "csaf_version": "2.0",
+    "distribution": {"tlp": {"label": "WHITE"}},
+    "lang": "en",
+    "notes": [{"category": "summary", "text": "A summary."}],
+    "publisher": {
+      "category": "vendor",
+      "name": "Example Company",
+      "namespace": "https://example.com"
+    },
+    "references": [{
+      "category": "self",
+      "summary": "Canonical URL",
+      "url": "https://example.com/.well-known/csaf/white/2023/example-2023-0001.json"
+    }],
+    "title": "Example advisory",
+    "tracking": {
+      "current_release_date": "2023-02-01T10:00:00.000Z",
+      "id": "EXAMPLE-2023-0001",
+      "initial_release_date": "2023-01-01T10:00:00.000Z",
+      "revision_history": [
+        {"date": "2023-01-01T10:00:00.000Z", "number": "1", "summary": "Initial version."},
+        {"date": "2023-02-01T10:00:00.000Z", "number": "2", "summary": "Update."}
+      ],
+      "status": "final",
+      "version": "2"
+    }
+  },
+  "product_tree": {
+    "branches": [{
+      "category": "vendor",
+      "name": "Example Company",
+      "branches": [{
+        "category": "product_name",
+        "name": "Product",
+        "branches": [{
+          "category": "product_version",
+          "name": "1.0",
+          "product": {
+            "name": "Example Company Product 1.0",
+            "product_id": "CSAFPID-0001",
+            "product_identification_helper": {
+              "purl": "pkg:generic/example/product@1.0"
+            }
+          }
+        }, {
+          "category": "product_version",
+          "name": "1.1",
+          "product": {
+            "name": "Example Company Product 1.1",
+            "product_id": "CSAFPID-0002",
+            "product_identification_helper": {
+              "purl": "pkg:generic/example/product@1.1"
+            }
+          }
+        }]
+      }]
+    }],
+    "product_groups": [{
+      "group_id": "CSAFGID-0001",
+      "product_ids": ["CSAFPID-0001", "CSAFPID-0002"]
+    }]
+  },
+  "vulnerabilities": [{
+    "cve": "CVE-2023-0001",
+    "cwe": {"id": "CWE-79", "name": "Improper Neutralization of Input During Web Page Generation ('Cross-site Scripting')"},
+    "notes": [{"category": "description", "text": "A description."}],
+    "product_status": {
+      "known_affected": ["CSAFPID-0001"],
+      "fixed": ["CSAFPID-0002"]
+    },
+    "remediations": [{
+      "category": "vendor_fix",
+      "details": "Update to 1.1.",
+      "group_ids": ["CSAFGID-0001"]
+    }],
+    "scores": [{
+      "products": ["CSAFPID-0001"],
+      "cvss_v3": {
+        "version": "3.1",
+        "vectorString": "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H",
+        "attackVector": "NETWORK",
+        "baseScore": 9.8,
+        "baseSeverity": "CRITICAL"
+      }
+    }]
+  }]
+}`
+
+// loadTestAdvisory loads the test advisory and applies
+// the given modification to its generic JSON form.
+func loadTestAdvisory(t *testing.T, modify func(doc map[string]any)) any {
+	t.Helper()
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(testAdvisory), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if modify != nil {
+		modify(doc)
+	}
+	return doc
+}
+
+// walkJSON walks down a generic JSON document.
+func walkJSON(doc any, elems ...any) any {
+	for _, e := range elems {
+		switch x := e.(type) {
+		case string:
+			doc = doc.(map[string]any)[x]
+		case int:
+			doc = doc.([]any)[x]
+		}
+	}
+	return doc
+}
+
+func TestLocalValidatorValid(t *testing.T) {
+	doc := loadTestAdvisory(t, nil)
+
+	errs, err := ValidateCSAF(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatalf("test advisory is not schema valid: %v", errs)
+	}
+
+	validator, err := (&LocalValidatorOptions{}).Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer validator.Close()
+
+	rvr, err := validator.Validate(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rvr.Valid {
+		for _, test := range rvr.Tests {
+			for _, e := range test.Error {
+				t.Errorf("%s: %s: %s", test.Name, e.InstancePath, e.Message)
+			}
+		}
+	}
+	if len(rvr.Tests) != len(mandatoryPreset.tests) {
+		t.Errorf("expected %d tests, got %d", len(mandatoryPreset.tests), len(rvr.Tests))
+	}
+}
+
+func TestLocalValidatorMandatory(t *testing.T) {
+	for _, tc := range []struct {
+		test   string
+		path   string
+		modify func(map[string]any)
+		// others are tests which fail as a consequence.
+		others []string
+	}{
+		{
+			test: "mandatoryTest_6_1_1",
+			path: "/vulnerabilities/0/product_status/fixed/0",
+			modify: func(doc map[string]any) {
+				ps := walkJSON(doc, "vulnerabilities", 0, "product_status").(map[string]any)
+				ps["fixed"] = []any{"CSAFPID-9999"}
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_4",
+			path: "/vulnerabilities/0/remediations/0/group_ids/0",
+			modify: func(doc map[string]any) {
+				r := walkJSON(doc, "vulnerabilities", 0, "remediations", 0).(map[string]any)
+				r["group_ids"] = []any{"CSAFGID-9999"}
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_6",
+			path: "/vulnerabilities/0/product_status/known_affected/0",
+			modify: func(doc map[string]any) {
+				ps := walkJSON(doc, "vulnerabilities", 0, "product_status").(map[string]any)
+				ps["known_affected"] = []any{"CSAFPID-0002"}
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_9",
+			path: "/vulnerabilities/0/scores/0/cvss_v3/baseScore",
+			modify: func(doc map[string]any) {
+				c := walkJSON(doc, "vulnerabilities", 0, "scores", 0, "cvss_v3").(map[string]any)
+				c["baseScore"] = 9.7
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_9",
+			path: "/vulnerabilities/0/scores/0/cvss_v4/baseScore",
+			modify: func(doc map[string]any) {
+				s := walkJSON(doc, "vulnerabilities", 0, "scores", 0).(map[string]any)
+				s["cvss_v4"] = map[string]any{
+					"version":      "4.0",
+					"vectorString": "CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N",
+					"baseScore":    9.0,
+					"baseSeverity": "CRITICAL",
+				}
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_10",
+			path: "/vulnerabilities/0/scores/0/cvss_v3/attackVector",
+			modify: func(doc map[string]any) {
+				c := walkJSON(doc, "vulnerabilities", 0, "scores", 0, "cvss_v3").(map[string]any)
+				c["attackVector"] = "LOCAL"
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_14",
+			path: "/document/tracking/revision_history/0/number",
+			modify: func(doc map[string]any) {
+				r := walkJSON(doc, "document", "tracking", "revision_history", 0).(map[string]any)
+				r["date"] = "2023-03-01T10:00:00.000Z"
+			},
+			others: []string{"mandatoryTest_6_1_16", "mandatoryTest_6_1_21"},
+		},
+		{
+			test: "mandatoryTest_6_1_16",
+			path: "/document/tracking/version",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "document", "tracking").(map[string]any)["version"] = "1"
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_31",
+			path: "/product_tree/branches/0/branches/0/branches/1/name",
+			modify: func(doc map[string]any) {
+				b := walkJSON(doc, "product_tree", "branches", 0, "branches", 0, "branches", 1)
+				b.(map[string]any)["name"] = "prior to 1.1"
+			},
+		},
+		{
+			test: "mandatoryTest_6_1_27_10",
+			path: "/vulnerabilities/0/product_status/known_affected/0",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "document").(map[string]any)["category"] = "csaf_vex"
+				v := walkJSON(doc, "vulnerabilities", 0).(map[string]any)
+				v["remediations"] = []any{map[string]any{
+					"category":    "vendor_fix",
+					"details":     "Update to 1.1.",
+					"product_ids": []any{"CSAFPID-0002"},
+				}}
+			},
+		},
+	} {
+		t.Run(tc.test, func(t *testing.T) {
+			doc := loadTestAdvisory(t, tc.modify)
+			validator, err := (&LocalValidatorOptions{Presets: []string{"mandatory"}}).Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rvr, err := validator.Validate(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rvr.Valid {
+				t.Fatal("expected document to be invalid")
+			}
+			for _, test := range rvr.Tests {
+				if test.Name != tc.test {
+					if !test.Valid && !containsString(tc.others, test.Name) {
+						t.Errorf("unexpected failure of %s: %v", test.Name, test.Error)
+					}
+					continue
+				}
+				if test.Valid {
+					t.Fatalf("expected %s to fail", tc.test)
+				}
+				if got := test.Error[0].InstancePath; got != tc.path {
+					t.Errorf("expected instance path %q, got %q", tc.path, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLocalValidatorFull(t *testing.T) {
+	validator, err := (&LocalValidatorOptions{Presets: []string{"full"}}).Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rvr, err := validator.Validate(loadTestAdvisory(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rvr.Valid {
+		t.Error("expected document to be valid")
+	}
+	for _, test := range rvr.Tests {
+		for _, results := range [][]RemoteTestResult{test.Error, test.Warning, test.Info} {
+			for _, r := range results {
+				t.Errorf("%s: %s: %s", test.Name, r.InstancePath, r.Message)
+			}
+		}
+	}
+	expected := len(mandatoryPreset.tests) +
+		len(optionalPreset.tests) + len(informativePreset.tests)
+	if len(rvr.Tests) != expected {
+		t.Errorf("expected %d tests, got %d", expected, len(rvr.Tests))
+	}
+}
+
+func TestLocalValidatorOptionalInformative(t *testing.T) {
+	for _, tc := range []struct {
+		test   string
+		preset string
+		path   string
+		modify func(map[string]any)
+	}{
+		{
+			test:   "optionalTest_6_2_1",
+			preset: "recommended",
+			path:   "/product_tree/branches/0/branches/0/branches/1/product/product_id",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "product_tree").(map[string]any)["product_groups"] = nil
+				v := walkJSON(doc, "vulnerabilities", 0).(map[string]any)
+				v["product_status"] = map[string]any{"known_affected": []any{"CSAFPID-0001"}}
+				v["remediations"] = []any{map[string]any{
+					"category":    "no_fix_planned",
+					"details":     "No fix.",
+					"product_ids": []any{"CSAFPID-0001"},
+				}}
+			},
+		},
+		{
+			test:   "optionalTest_6_2_3",
+			preset: "optional",
+			path:   "/vulnerabilities/0/product_status/known_affected/0",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "vulnerabilities", 0).(map[string]any)["scores"] = nil
+			},
+		},
+		{
+			test:   "optionalTest_6_2_6",
+			preset: "extended",
+			path:   "/document/tracking/current_release_date",
+			modify: func(doc map[string]any) {
+				t := walkJSON(doc, "document", "tracking").(map[string]any)
+				t["current_release_date"] = "2023-01-15T10:00:00.000Z"
+			},
+		},
+		{
+			test:   "optionalTest_6_2_15",
+			preset: "optional",
+			path:   "/document/lang",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "document").(map[string]any)["lang"] = "i-default"
+			},
+		},
+		{
+			test:   "optionalTest_6_2_17",
+			preset: "optional",
+			path:   "/vulnerabilities/0/ids/0/text",
+			modify: func(doc map[string]any) {
+				walkJSON(doc, "vulnerabilities", 0).(map[string]any)["ids"] = []any{
+					map[string]any{"system_name": "CVE", "text": "CVE-2023-0002"},
+				}
+			},
+		},
+		{
+			test:   "informativeTest_6_3_2",
+			preset: "informative",
+			path:   "/vulnerabilities/0/scores/0/cvss_v3/version",
+			modify: func(doc map[string]any) {
+				c := walkJSON(doc, "vulnerabilities", 0, "scores", 0, "cvss_v3").(map[string]any)
+				c["version"] = "3.0"
+				c["vectorString"] = "CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"
+			},
+		},
+		{
+			test:   "informativeTest_6_3_3",
+			preset: "full",
+			path:   "/vulnerabilities/0",
+			modify: func(doc map[string]any) {
+				delete(walkJSON(doc, "vulnerabilities", 0).(map[string]any), "cve")
+			},
+		},
+	} {
+		t.Run(tc.test, func(t *testing.T) {
+			doc := loadTestAdvisory(t, tc.modify)
+			validator, err := (&LocalValidatorOptions{Presets: []string{tc.preset}}).Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rvr, err := validator.Validate(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rvr.Valid {
+				t.Error("expected document to be valid")
+			}
+			var found bool
+			for _, test := range rvr.Tests {
+				if test.Name != tc.test {
+					continue
+				}
+				found = true
+				results := test.Warning
+				if strings.HasPrefix(tc.test, "informative") {
+					results = test.Info
+				}
+				if len(results) == 0 {
+					t.Fatalf("expected %s to report", tc.test)
+				}
+				if got := results[0].InstancePath; got != tc.path {
+					t.Errorf("expected instance path %q, got %q", tc.path, got)
+				}
+			}
+			if !found {
+				t.Errorf("%s was not run by preset %q", tc.test, tc.preset)
+			}
+		})
+	}
+}
+
+// containsString returns true if s is in list.
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLocalValidatorUnknownPreset(t *testing.T) {
+	_, err := (&LocalValidatorOptions{Presets: []string{"unknown"}}).Open()
+	if err == nil || !strings.Contains(err.Error(), "unknown") {
+		t.Errorf("expected unknown preset error, got %v", err)
+	}
+}
+
+func TestCVSSBaseScores(t *testing.T) {
+	for _, tc := range []struct {
+		vector string
+		score  float64
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", 9.8},
+		{"CVSS:3.1/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", 9.9},
+		{"CVSS:3.0/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", 6.1},
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:N/I:N/A:N", 0},
+	} {
+		version, values, err := parseCVSS3Vector(tc.vector)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vector, err)
+		}
+		if score := cvss3BaseScore(version, values); score != tc.score {
+			t.Errorf("%s: expected %.1f, got %.1f", tc.vector, tc.score, score)
+		}
+	}
+	for _, tc := range []struct {
+		vector string
+		score  float64
+	}{
+		{"AV:N/AC:L/Au:N/C:P/I:P/A:P", 7.5},
+		{"AV:N/AC:M/Au:N/C:C/I:C/A:C", 9.3},
+		{"AV:L/AC:H/Au:M/C:N/I:N/A:N", 0},
+	} {
+		values, err := parseCVSS2Vector(tc.vector)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.vector, err)
+		}
+		if score := cvss2BaseScore(values); score != tc.score {
+			t.Errorf("%s: expected %.1f, got %.1f", tc.vector, tc.score, score)
+		}
+	}
+}
@@ -0,0 +1,1377 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// mandatoryPreset are the mandatory tests of section 6.1 of the
+// CSAF 2.0 specification.
+// Not covered are the tests 6.1.11 (CWE) and 6.1.12 (Language)
+// as they need external catalogs which are not available offline.
+var mandatoryPreset = testPreset{
+	level: errorLevel,
+	tests: []advisoryTest{
+		{"mandatoryTest_6_1_1", (*testEnv).missingProductDefinitions},
+		{"mandatoryTest_6_1_2", (*testEnv).multipleProductDefinitions},
+		{"mandatoryTest_6_1_3", (*testEnv).circularProductDefinitions},
+		{"mandatoryTest_6_1_4", (*testEnv).missingGroupDefinitions},
+		{"mandatoryTest_6_1_5", (*testEnv).multipleGroupDefinitions},
+		{"mandatoryTest_6_1_6", (*testEnv).contradictingProductStatus},
+		{"mandatoryTest_6_1_7", (*testEnv).multipleScoresPerProduct},
+		{"mandatoryTest_6_1_8", (*testEnv).invalidCVSS},
+		{"mandatoryTest_6_1_9", (*testEnv).invalidCVSSComputation},
+		{"mandatoryTest_6_1_10", (*testEnv).inconsistentCVSS},
+		{"mandatoryTest_6_1_13", (*testEnv).invalidPURLs},
+		{"mandatoryTest_6_1_14", (*testEnv).unsortedRevisionHistory},
+		{"mandatoryTest_6_1_15", (*testEnv).translatorWithoutSourceLang},
+		{"mandatoryTest_6_1_16", (*testEnv).latestDocumentVersion},
+		{"mandatoryTest_6_1_17", (*testEnv).documentStatusDraft},
+		{"mandatoryTest_6_1_18", (*testEnv).releasedRevisionHistory},
+		{"mandatoryTest_6_1_19", (*testEnv).preReleaseRevisions},
+		{"mandatoryTest_6_1_20", (*testEnv).nonDraftDocumentVersion},
+		{"mandatoryTest_6_1_21", (*testEnv).missingRevisions},
+		{"mandatoryTest_6_1_22", (*testEnv).multipleRevisions},
+		{"mandatoryTest_6_1_23", (*testEnv).multipleCVEs},
+		{"mandatoryTest_6_1_24", (*testEnv).multipleInvolvements},
+		{"mandatoryTest_6_1_25", (*testEnv).multipleHashAlgorithms},
+		{"mandatoryTest_6_1_26", (*testEnv).prohibitedDocumentCategory},
+		{"mandatoryTest_6_1_27_1", (*testEnv).profileDocumentNotes},
+		{"mandatoryTest_6_1_27_2", (*testEnv).profileDocumentReferences},
+		{"mandatoryTest_6_1_27_3", (*testEnv).profileNoVulnerabilities},
+		{"mandatoryTest_6_1_27_4", (*testEnv).profileProductTree},
+		{"mandatoryTest_6_1_27_5", (*testEnv).profileVulnerabilityNotes},
+		{"mandatoryTest_6_1_27_6", (*testEnv).profileProductStatus},
+		{"mandatoryTest_6_1_27_7", (*testEnv).profileVEXProductStatus},
+		{"mandatoryTest_6_1_27_8", (*testEnv).profileVulnerabilityID},
+		{"mandatoryTest_6_1_27_9", (*testEnv).profileImpactStatement},
+		{"mandatoryTest_6_1_27_10", (*testEnv).profileActionStatement},
+		{"mandatoryTest_6_1_27_11", (*testEnv).profileVulnerabilities},
+		{"mandatoryTest_6_1_28", (*testEnv).translationSameLang},
+		{"mandatoryTest_6_1_29", (*testEnv).remediationWithoutProducts},
+		{"mandatoryTest_6_1_30", (*testEnv).mixedVersioning},
+		{"mandatoryTest_6_1_31", (*testEnv).versionRangeInProductVersion},
+		{"mandatoryTest_6_1_32", (*testEnv).flagWithoutProducts},
+		{"mandatoryTest_6_1_33", (*testEnv).multipleFlagsPerProduct},
+	},
+}
+
+// Names of the document categories defined by the profiles.
+const (
+	profileBase                   = "csaf_base"
+	profileSecurityIncidentResp   = "csaf_security_incident_response"
+	profileInformationalAdvisory  = "csaf_informational_advisory"
+	profileSecurityAdvisory       = "csaf_security_advisory"
+	profileVEX                    = "csaf_vex"
+	profileReservedCategoryPrefix = "csaf_"
+)
+
+// testEnv holds the advisory and some pre-calculated
+// indices which are shared by the tests.
+type testEnv struct {
+	adv *Advisory
+	// products maps the defined product ids to the
+	// paths of their definitions.
+	products map[ProductID][]string
+	// groups maps the defined product group ids to the
+	// paths of their definitions.
+	groups map[ProductGroupID][]string
+	// members maps the product group ids to their product ids.
+	members map[ProductGroupID][]ProductID
+}
+
+// newTestEnv creates the test environment of an advisory.
+func newTestEnv(adv *Advisory) *testEnv {
+	env := &testEnv{
+		adv:      adv,
+		products: map[ProductID][]string{},
+		groups:   map[ProductGroupID][]string{},
+		members:  map[ProductGroupID][]ProductID{},
+	}
+	pt := adv.ProductTree
+	if pt == nil {
+		return env
+	}
+	pt.visitFullProductNames(func(fpn *FullProductName, path string) {
+		if fpn.ProductID != nil {
+			env.products[*fpn.ProductID] = append(
+				env.products[*fpn.ProductID], path+"/product_id")
+		}
+	})
+	for i, pg := range pt.ProductGroups {
+		if pg == nil || pg.GroupID == nil {
+			continue
+		}
+		id := *pg.GroupID
+		env.groups[id] = append(
+			env.groups[id], fmt.Sprintf("/product_tree/product_groups/%d/group_id", i))
+		if pg.ProductIDs != nil {
+			for _, pid := range *pg.ProductIDs {
+				if pid != nil {
+					env.members[id] = append(env.members[id], *pid)
+				}
+			}
+		}
+	}
+	return env
+}
+
+// visitBranches calls fn for every branch of the
+// product tree with its JSON path.
+func (pt *ProductTree) visitBranches(fn func(*Branch, string)) {
+	var recBranch func(b *Branch, path string)
+	recBranch = func(b *Branch, path string) {
+		if b == nil {
+			return
+		}
+		fn(b, path)
+		for i, c := range b.Branches {
+			recBranch(c, fmt.Sprintf("%s/branches/%d", path, i))
+		}
+	}
+	for i, b := range pt.Branches {
+		recBranch(b, fmt.Sprintf("/product_tree/branches/%d", i))
+	}
+}
+
+// visitFullProductNames calls fn for every full product name
+// defined in the product tree with its JSON path.
+func (pt *ProductTree) visitFullProductNames(fn func(*FullProductName, string)) {
+	pt.visitBranches(func(b *Branch, path string) {
+		if b.Product != nil {
+			fn(b.Product, path+"/product")
+		}
+	})
+	if fpns := pt.FullProductNames; fpns != nil {
+		for i, fpn := range *fpns {
+			if fpn != nil {
+				fn(fpn, fmt.Sprintf("/product_tree/full_product_names/%d", i))
+			}
+		}
+	}
+	if rels := pt.RelationShips; rels != nil {
+		for i, rel := range *rels {
+			if rel != nil && rel.FullProductName != nil {
+				fn(rel.FullProductName,
+					fmt.Sprintf("/product_tree/relationships/%d/full_product_name", i))
+			}
+		}
+	}
+}
+
+// namedProducts is a list of products with the
+// name of the property holding it.
+type namedProducts struct {
+	name     string
+	products *Products
+}
+
+// lists returns the product lists of the product status.
+func (ps *ProductStatus) lists() []namedProducts {
+	return []namedProducts{
+		{"first_affected", ps.FirstAffected},
+		{"first_fixed", ps.FirstFixed},
+		{"fixed", ps.Fixed},
+		{"known_affected", ps.KnownAffected},
+		{"known_not_affected", ps.KnownNotAffected},
+		{"last_affected", ps.LastAffected},
+		{"recommended", ps.Recommended},
+		{"under_investigation", ps.UnderInvestigation},
+	}
+}
+
+// visitProducts calls fn for each product id in the list with its path.
+func visitProducts(products *Products, path string, fn func(ProductID, string)) {
+	if products == nil {
+		return
+	}
+	for i, p := range *products {
+		if p != nil {
+			fn(*p, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+// visitGroups calls fn for each product group id in the list with its path.
+func visitGroups(groups *ProductGroupIDs, path string, fn func(ProductGroupID, string)) {
+	if groups == nil {
+		return
+	}
+	for i, g := range *groups {
+		if g != nil {
+			fn(*g, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+// visitProductReferences calls fn for every reference to
+// a product id in the advisory with its JSON path.
+func (env *testEnv) visitProductReferences(fn func(ProductID, string)) {
+	if pt := env.adv.ProductTree; pt != nil {
+		for i, pg := range pt.ProductGroups {
+			if pg != nil {
+				visitProducts(pg.ProductIDs,
+					fmt.Sprintf("/product_tree/product_groups/%d/product_ids", i), fn)
+			}
+		}
+		if rels := pt.RelationShips; rels != nil {
+			for i, rel := range *rels {
+				if rel == nil {
+					continue
+				}
+				path := fmt.Sprintf("/product_tree/relationships/%d", i)
+				if rel.ProductReference != nil {
+					fn(*rel.ProductReference, path+"/product_reference")
+				}
+				if rel.RelatesToProductReference != nil {
+					fn(*rel.RelatesToProductReference, path+"/relates_to_product_reference")
+				}
+			}
+		}
+	}
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		path := fmt.Sprintf("/vulnerabilities/%d", i)
+		for j, f := range v.Flags {
+			if f != nil {
+				visitProducts(f.ProductIds, fmt.Sprintf("%s/flags/%d/product_ids", path, j), fn)
+			}
+		}
+		if ps := v.ProductStatus; ps != nil {
+			for _, l := range ps.lists() {
+				visitProducts(l.products, path+"/product_status/"+l.name, fn)
+			}
+		}
+		for j, r := range v.Remediations {
+			if r != nil {
+				visitProducts(r.ProductIds, fmt.Sprintf("%s/remediations/%d/product_ids", path, j), fn)
+			}
+		}
+		visitVulnerabilityScores(v, path, func(s *Score, _, productsPath string) {
+			visitProducts(s.Products, productsPath, fn)
+		})
+		for j, t := range v.Threats {
+			if t != nil {
+				visitProducts(t.ProductIds, fmt.Sprintf("%s/threats/%d/product_ids", path, j), fn)
+			}
+		}
+	}
+}
+
+// visitGroupReferences calls fn for every reference to
+// a product group id in the advisory with its JSON path.
+func (env *testEnv) visitGroupReferences(fn func(ProductGroupID, string)) {
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		path := fmt.Sprintf("/vulnerabilities/%d", i)
+		for j, f := range v.Flags {
+			if f != nil {
+				visitGroups(f.GroupIDs, fmt.Sprintf("%s/flags/%d/group_ids", path, j), fn)
+			}
+		}
+		for j, r := range v.Remediations {
+			if r != nil {
+				visitGroups(r.GroupIds, fmt.Sprintf("%s/remediations/%d/group_ids", path, j), fn)
+			}
+		}
+		for j, t := range v.Threats {
+			if t != nil {
+				visitGroups(t.GroupIds, fmt.Sprintf("%s/threats/%d/group_ids", path, j), fn)
+			}
+		}
+	}
+}
+
+// expand returns the product ids of the given products
+// and the members of the given product groups in order
+// of their appearance. Duplicates are removed.
+func (env *testEnv) expand(products *Products, groups *ProductGroupIDs) []ProductID {
+	var ids []ProductID
+	seen := util.Set[ProductID]{}
+	add := func(id ProductID) {
+		if !seen.Contains(id) {
+			seen.Add(id)
+			ids = append(ids, id)
+		}
+	}
+	if products != nil {
+		for _, p := range *products {
+			if p != nil {
+				add(*p)
+			}
+		}
+	}
+	if groups != nil {
+		for _, g := range *groups {
+			if g != nil {
+				for _, p := range env.members[*g] {
+					add(p)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+// category returns the document category or an empty string.
+func (env *testEnv) category() string {
+	if doc := env.adv.Document; doc != nil && doc.Category != nil {
+		return string(*doc.Category)
+	}
+	return ""
+}
+
+// status returns the document status or an empty string.
+func (env *testEnv) status() TrackingStatus {
+	if doc := env.adv.Document; doc != nil &&
+		doc.Tracking != nil && doc.Tracking.Status != nil {
+		return *doc.Tracking.Status
+	}
+	return ""
+}
+
+// released returns true if the document status is final or interim.
+func (env *testEnv) released() bool {
+	s := env.status()
+	return s == CSAFTrackingStatusFinal || s == CSAFTrackingStatusInterim
+}
+
+// tracking returns the tracking of the document or nil.
+func (env *testEnv) tracking() *Tracking {
+	if doc := env.adv.Document; doc != nil {
+		return doc.Tracking
+	}
+	return nil
+}
+
+// revisionNumber is a parsed revision number.
+// It is either an integer or a semantic version.
+type revisionNumber struct {
+	semantic            bool
+	major, minor, patch uint64
+	pre                 string
+	build               string
+}
+
+// parseRevisionNumber parses a revision number.
+func parseRevisionNumber(s string) (revisionNumber, error) {
+	var rn revisionNumber
+	if !strings.Contains(s, ".") {
+		n, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return rn, fmt.Errorf("invalid revision number %q", s)
+		}
+		rn.major = n
+		return rn, nil
+	}
+	rn.semantic = true
+	s, rn.build, _ = strings.Cut(s, "+")
+	s, rn.pre, _ = strings.Cut(s, "-")
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return rn, fmt.Errorf("invalid revision number %q", s)
+	}
+	for i, dst := range []*uint64{&rn.major, &rn.minor, &rn.patch} {
+		n, err := strconv.ParseUint(parts[i], 10, 64)
+		if err != nil {
+			return rn, fmt.Errorf("invalid revision number %q", s)
+		}
+		*dst = n
+	}
+	return rn, nil
+}
+
+// cmpUint compares two unsigned integers.
+func cmpUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return +1
+	}
+	return 0
+}
+
+// comparePreRelease compares two pre-release parts
+// following the precedence rules of semantic versioning.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return +1
+	case b == "":
+		return -1
+	}
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if c := cmpUint(an, bn); c != 0 {
+				return c
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return +1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmpUint(uint64(len(as)), uint64(len(bs)))
+}
+
+// compare compares two revision numbers. Build metadata is ignored.
+func (rn revisionNumber) compare(o revisionNumber) int {
+	if c := cmpUint(rn.major, o.major); c != 0 {
+		return c
+	}
+	if c := cmpUint(rn.minor, o.minor); c != 0 {
+		return c
+	}
+	if c := cmpUint(rn.patch, o.patch); c != 0 {
+		return c
+	}
+	return comparePreRelease(rn.pre, o.pre)
+}
+
+// released returns true if the revision number is not
+// a pre-release or a version before 1.
+func (rn revisionNumber) released() bool {
+	return rn.major != 0 && rn.pre == ""
+}
+
+// revisionEntry is an entry of the revision history
+// with its parsed date and number.
+type revisionEntry struct {
+	index  int
+	date   time.Time
+	number revisionNumber
+}
+
+// sortedRevisions returns the parsable entries of the
+// revision history sorted ascending by date.
+func (env *testEnv) sortedRevisions() []revisionEntry {
+	t := env.tracking()
+	if t == nil {
+		return nil
+	}
+	var entries []revisionEntry
+	for i, r := range t.RevisionHistory {
+		if r == nil || r.Date == nil || r.Number == nil {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, *r.Date)
+		if err != nil {
+			continue
+		}
+		number, err := parseRevisionNumber(string(*r.Number))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, revisionEntry{
+			index:  i,
+			date:   date,
+			number: number,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].date.Before(entries[j].date)
+	})
+	return entries
+}
+
+// documentVersion returns the parsed version of the document.
+func (env *testEnv) documentVersion() (revisionNumber, bool) {
+	t := env.tracking()
+	if t == nil || t.Version == nil {
+		return revisionNumber{}, false
+	}
+	rn, err := parseRevisionNumber(string(*t.Version))
+	return rn, err == nil
+}
+
+// missingProductDefinitions implements test 6.1.1.
+func (env *testEnv) missingProductDefinitions(report testReporter) {
+	env.visitProductReferences(func(id ProductID, path string) {
+		if _, ok := env.products[id]; !ok {
+			report(path, "definition of product id %q is missing", id)
+		}
+	})
+}
+
+// multipleProductDefinitions implements test 6.1.2.
+func (env *testEnv) multipleProductDefinitions(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	seen := util.Set[ProductID]{}
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		if fpn.ProductID == nil {
+			return
+		}
+		if id := *fpn.ProductID; seen.Contains(id) {
+			report(path+"/product_id", "product id %q is defined more than once", id)
+		} else {
+			seen.Add(id)
+		}
+	})
+}
+
+// circularProductDefinitions implements test 6.1.3.
+func (env *testEnv) circularProductDefinitions(report testReporter) {
+	pt := env.adv.ProductTree
+	if pt == nil || pt.RelationShips == nil {
+		return
+	}
+	// Build the graph of the relationships.
+	refs := map[ProductID][]ProductID{}
+	for _, rel := range *pt.RelationShips {
+		if rel == nil || rel.FullProductName == nil || rel.FullProductName.ProductID == nil {
+			continue
+		}
+		id := *rel.FullProductName.ProductID
+		for _, ref := range []*ProductID{rel.ProductReference, rel.RelatesToProductReference} {
+			if ref != nil {
+				refs[id] = append(refs[id], *ref)
+			}
+		}
+	}
+	for i, rel := range *pt.RelationShips {
+		if rel == nil || rel.FullProductName == nil || rel.FullProductName.ProductID == nil {
+			continue
+		}
+		start := *rel.FullProductName.ProductID
+		visited := util.Set[ProductID]{}
+		stack := append([]ProductID(nil), refs[start]...)
+		for len(stack) > 0 {
+			id := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if id == start {
+				report(
+					fmt.Sprintf("/product_tree/relationships/%d/full_product_name/product_id", i),
+					"product id %q has a circular definition", start)
+				break
+			}
+			if visited.Contains(id) {
+				continue
+			}
+			visited.Add(id)
+			stack = append(stack, refs[id]...)
+		}
+	}
+}
+
+// missingGroupDefinitions implements test 6.1.4.
+func (env *testEnv) missingGroupDefinitions(report testReporter) {
+	env.visitGroupReferences(func(id ProductGroupID, path string) {
+		if _, ok := env.groups[id]; !ok {
+			report(path, "definition of product group id %q is missing", id)
+		}
+	})
+}
+
+// multipleGroupDefinitions implements test 6.1.5.
+func (env *testEnv) multipleGroupDefinitions(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	seen := util.Set[ProductGroupID]{}
+	for i, pg := range env.adv.ProductTree.ProductGroups {
+		if pg == nil || pg.GroupID == nil {
+			continue
+		}
+		if id := *pg.GroupID; seen.Contains(id) {
+			report(fmt.Sprintf("/product_tree/product_groups/%d/group_id", i),
+				"product group id %q is defined more than once", id)
+		} else {
+			seen.Add(id)
+		}
+	}
+}
+
+// contradictingProductStatus implements test 6.1.6.
+func (env *testEnv) contradictingProductStatus(report testReporter) {
+	groups := map[string]string{
+		"first_affected":      "affected",
+		"known_affected":      "affected",
+		"last_affected":       "affected",
+		"known_not_affected":  "not affected",
+		"first_fixed":         "fixed",
+		"fixed":               "fixed",
+		"under_investigation": "under investigation",
+	}
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil || v.ProductStatus == nil {
+			continue
+		}
+		seen := map[ProductID]string{}
+		for _, l := range v.ProductStatus.lists() {
+			group, ok := groups[l.name]
+			if !ok {
+				continue
+			}
+			visitProducts(l.products,
+				fmt.Sprintf("/vulnerabilities/%d/product_status/%s", i, l.name),
+				func(id ProductID, path string) {
+					if prev, ok := seen[id]; ok && prev != group {
+						report(path, "product id %q is in contradicting status groups %q and %q",
+							id, prev, group)
+						return
+					}
+					seen[id] = group
+				})
+		}
+	}
+}
+
+// multipleScoresPerProduct implements test 6.1.7.
+func (env *testEnv) multipleScoresPerProduct(report testReporter) {
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		seen := map[ProductID]util.Set[string]{}
+		path := fmt.Sprintf("/vulnerabilities/%d", i)
+		visitVulnerabilityScores(v, path, func(s *Score, _, productsPath string) {
+			var versions []string
+			if s.CVSS2 != nil && s.CVSS2.Version != nil {
+				versions = append(versions, string(*s.CVSS2.Version))
+			}
+			if s.CVSS3 != nil && s.CVSS3.Version != nil {
+				versions = append(versions, string(*s.CVSS3.Version))
+			}
+			if s.CVSS4 != nil && s.CVSS4.Version != nil {
+				versions = append(versions, string(*s.CVSS4.Version))
+			}
+			visitProducts(s.Products, productsPath,
+				func(id ProductID, path string) {
+					have := seen[id]
+					if have == nil {
+						have = util.Set[string]{}
+						seen[id] = have
+					}
+					for _, version := range versions {
+						if have.Contains(version) {
+							report(path, "product id %q has more than one CVSS %s score", id, version)
+						}
+						have.Add(version)
+					}
+				})
+		})
+	}
+}
+
+var (
+	compiledCVSS20Schema = compiledSchema{url: cvss20SchemaURL}
+	compiledCVSS30Schema = compiledSchema{url: cvss30SchemaURL}
+	compiledCVSS31Schema = compiledSchema{url: cvss31SchemaURL}
+	compiledCVSS40Schema = compiledSchema{url: cvss40SchemaURL}
+)
+
+// visitVulnerabilityScores calls fn for the scores and the CVSS parts
+// of the CSAF 2.1 metrics of a vulnerability. fn is called with the
+// path of the CVSS objects and the path of the products.
+func visitVulnerabilityScores(
+	v *Vulnerability,
+	path string,
+	fn func(s *Score, cvssPath, productsPath string),
+) {
+	for j, s := range v.Scores {
+		if s != nil {
+			p := fmt.Sprintf("%s/scores/%d", path, j)
+			fn(s, p, p+"/products")
+		}
+	}
+	for j, m := range v.Metrics {
+		if m != nil {
+			p := fmt.Sprintf("%s/metrics/%d", path, j)
+			fn(m.Score(), p+"/content", p+"/products")
+		}
+	}
+}
+
+// visitScores calls fn for every score with its path.
+func (env *testEnv) visitScores(fn func(*Score, string)) {
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		visitVulnerabilityScores(v, fmt.Sprintf("/vulnerabilities/%d", i),
+			func(s *Score, cvssPath, _ string) { fn(s, cvssPath) })
+	}
+}
+
+// invalidCVSS implements test 6.1.8.
+func (env *testEnv) invalidCVSS(report testReporter) {
+	check := func(cs *compiledSchema, cvss any, path string) {
+		var doc any
+		if err := util.ReMarshalJSON(&doc, cvss); err != nil {
+			report(path, "cannot convert CVSS object: %v", err)
+			return
+		}
+		errs, err := cs.validate(doc)
+		if err != nil {
+			report(path, "cannot validate CVSS object: %v", err)
+			return
+		}
+		for _, e := range errs {
+			report(path, "invalid CVSS object: %s", e)
+		}
+	}
+	env.visitScores(func(s *Score, path string) {
+		if s.CVSS2 != nil {
+			check(&compiledCVSS20Schema, s.CVSS2, path+"/cvss_v2")
+		}
+		if s.CVSS3 != nil && s.CVSS3.Version != nil {
+			cs := &compiledCVSS31Schema
+			if *s.CVSS3.Version == CVSSVersion30 {
+				cs = &compiledCVSS30Schema
+			}
+			check(cs, s.CVSS3, path+"/cvss_v3")
+		}
+		if s.CVSS4 != nil {
+			check(&compiledCVSS40Schema, s.CVSS4, path+"/cvss_v4")
+		}
+	})
+}
+
+// cvssMismatches calls fn with the mismatches of the
+// CVSS objects of a score and their paths.
+// Invalid vectors are passed as errors.
+func (s *Score) cvssMismatches(path string, fn func(string, []CVSSMismatch, error)) {
+	if c := s.CVSS4; c != nil && c.VectorString != nil {
+		mismatches, err := c.Mismatches()
+		fn(path+"/cvss_v4", mismatches, err)
+	}
+	if c := s.CVSS3; c != nil && c.VectorString != nil {
+		mismatches, err := c.Mismatches()
+		fn(path+"/cvss_v3", mismatches, err)
+	}
+	if c := s.CVSS2; c != nil && c.VectorString != nil {
+		mismatches, err := c.Mismatches()
+		fn(path+"/cvss_v2", mismatches, err)
+	}
+}
+
+// invalidCVSSComputation implements test 6.1.9.
+func (env *testEnv) invalidCVSSComputation(report testReporter) {
+	env.visitScores(func(s *Score, path string) {
+		s.cvssMismatches(path, func(p string, mismatches []CVSSMismatch, err error) {
+			if err != nil {
+				return // Reported by 6.1.10.
+			}
+			for _, m := range mismatches {
+				if m.Computed() {
+					report(p+"/"+m.Property,
+						"value %s does not match the calculated %s", m.Value, m.Expected)
+				}
+			}
+		})
+	})
+}
+
+// inconsistentCVSS implements test 6.1.10.
+func (env *testEnv) inconsistentCVSS(report testReporter) {
+	env.visitScores(func(s *Score, path string) {
+		s.cvssMismatches(path, func(p string, mismatches []CVSSMismatch, err error) {
+			if err != nil {
+				report(p+"/vectorString", "invalid vector: %v", err)
+				return
+			}
+			for _, m := range mismatches {
+				if !m.Computed() {
+					report(p+"/"+m.Property,
+						"value %q does not match %q given by the vector", m.Value, m.Expected)
+				}
+			}
+		})
+	})
+}
+
+var purlTypePattern = regexp.MustCompile(`^[A-Za-z.+\-][A-Za-z0-9.+\-]*$`)
+
+// checkPURL checks if a PURL is well-formed.
+func checkPURL(purl string) error {
+	rest, ok := strings.CutPrefix(purl, "pkg:")
+	if !ok {
+		return fmt.Errorf("scheme is not 'pkg'")
+	}
+	rest, _, _ = strings.Cut(rest, "#")
+	rest, qualifiers, _ := strings.Cut(rest, "?")
+	rest = strings.Trim(rest, "/")
+	typ, rest, ok := strings.Cut(rest, "/")
+	if !ok || !purlTypePattern.MatchString(typ) {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	if i := strings.LastIndexByte(rest, '@'); i >= 0 {
+		rest = rest[:i]
+	}
+	segments := strings.Split(rest, "/")
+	if segments[len(segments)-1] == "" {
+		return fmt.Errorf("name is missing")
+	}
+	if qualifiers != "" {
+		for _, q := range strings.Split(qualifiers, "&") {
+			if k, _, ok := strings.Cut(q, "="); !ok || k == "" {
+				return fmt.Errorf("invalid qualifier %q", q)
+			}
+		}
+	}
+	return nil
+}
+
+// invalidPURLs implements test 6.1.13.
+func (env *testEnv) invalidPURLs(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		h := fpn.ProductIdentificationHelper
+		if h == nil {
+			return
+		}
+		if h.PURL != nil {
+			if err := checkPURL(string(*h.PURL)); err != nil {
+				report(path+"/product_identification_helper/purl", "invalid PURL: %v", err)
+			}
+		}
+		for i, purl := range h.PURLs {
+			if purl == nil {
+				continue
+			}
+			if err := checkPURL(string(*purl)); err != nil {
+				report(fmt.Sprintf("%s/product_identification_helper/purls/%d", path, i),
+					"invalid PURL: %v", err)
+			}
+		}
+	})
+}
+
+// revisionPath returns the path of the revision at index i.
+func revisionPath(i int) string {
+	return fmt.Sprintf("/document/tracking/revision_history/%d", i)
+}
+
+// unsortedRevisionHistory implements test 6.1.14.
+func (env *testEnv) unsortedRevisionHistory(report testReporter) {
+	revs := env.sortedRevisions()
+	for i := 1; i < len(revs); i++ {
+		if revs[i].number.compare(revs[i-1].number) < 0 {
+			report(revisionPath(revs[i].index)+"/number",
+				"revision history is not sorted ascending by date and number")
+		}
+	}
+}
+
+// translatorWithoutSourceLang implements test 6.1.15.
+func (env *testEnv) translatorWithoutSourceLang(report testReporter) {
+	doc := env.adv.Document
+	if doc == nil || doc.Publisher == nil || doc.Publisher.Category == nil {
+		return
+	}
+	if *doc.Publisher.Category == CSAFCategoryTranslator && doc.SourceLang == nil {
+		report("/document/source_lang", "source language is missing for a translation")
+	}
+}
+
+// latestDocumentVersion implements test 6.1.16.
+func (env *testEnv) latestDocumentVersion(report testReporter) {
+	version, ok := env.documentVersion()
+	revs := env.sortedRevisions()
+	if !ok || len(revs) == 0 {
+		return
+	}
+	latest := revs[len(revs)-1].number
+	if env.status() == CSAFTrackingStatusDraft {
+		version.pre, latest.pre = "", ""
+	}
+	if version.semantic != latest.semantic || version.compare(latest) != 0 {
+		report("/document/tracking/version",
+			"version does not match the number of the latest revision")
+	}
+}
+
+// documentStatusDraft implements test 6.1.17.
+func (env *testEnv) documentStatusDraft(report testReporter) {
+	if version, ok := env.documentVersion(); ok &&
+		!version.released() && env.status() != CSAFTrackingStatusDraft {
+		report("/document/tracking/status",
+			"document status must be 'draft' for an unreleased version")
+	}
+}
+
+// releasedRevisionHistory implements test 6.1.18.
+func (env *testEnv) releasedRevisionHistory(report testReporter) {
+	if !env.released() {
+		return
+	}
+	for _, rev := range env.sortedRevisions() {
+		if rev.number.major == 0 {
+			report(revisionPath(rev.index)+"/number",
+				"released document contains a revision with major version 0")
+		}
+	}
+}
+
+// preReleaseRevisions implements test 6.1.19.
+func (env *testEnv) preReleaseRevisions(report testReporter) {
+	for _, rev := range env.sortedRevisions() {
+		if rev.number.pre != "" {
+			report(revisionPath(rev.index)+"/number",
+				"revision history contains a pre-release version")
+		}
+	}
+}
+
+// nonDraftDocumentVersion implements test 6.1.20.
+func (env *testEnv) nonDraftDocumentVersion(report testReporter) {
+	if version, ok := env.documentVersion(); ok && env.released() && version.pre != "" {
+		report("/document/tracking/version",
+			"released document must not have a pre-release version")
+	}
+}
+
+// missingRevisions implements test 6.1.21.
+func (env *testEnv) missingRevisions(report testReporter) {
+	revs := env.sortedRevisions()
+	if len(revs) == 0 {
+		return
+	}
+	if first := revs[0].number.major; first > 1 {
+		report(revisionPath(revs[0].index)+"/number",
+			"revision history does not start with version 0 or 1")
+	}
+	for i := 1; i < len(revs); i++ {
+		prev, curr := revs[i-1].number.major, revs[i].number.major
+		if curr != prev && curr != prev+1 {
+			report(revisionPath(revs[i].index)+"/number",
+				"revision history misses an item before this version")
+		}
+	}
+}
+
+// multipleRevisions implements test 6.1.22.
+func (env *testEnv) multipleRevisions(report testReporter) {
+	t := env.tracking()
+	if t == nil {
+		return
+	}
+	seen := util.Set[RevisionNumber]{}
+	for i, r := range t.RevisionHistory {
+		if r == nil || r.Number == nil {
+			continue
+		}
+		if seen.Contains(*r.Number) {
+			report(revisionPath(i)+"/number",
+				"revision number %q is used more than once", *r.Number)
+		}
+		seen.Add(*r.Number)
+	}
+}
+
+// multipleCVEs implements test 6.1.23.
+func (env *testEnv) multipleCVEs(report testReporter) {
+	seen := util.Set[CVE]{}
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil || v.CVE == nil {
+			continue
+		}
+		if seen.Contains(*v.CVE) {
+			report(fmt.Sprintf("/vulnerabilities/%d/cve", i),
+				"CVE %q is used in more than one vulnerability", *v.CVE)
+		}
+		seen.Add(*v.CVE)
+	}
+}
+
+// multipleInvolvements implements test 6.1.24.
+func (env *testEnv) multipleInvolvements(report testReporter) {
+	type key struct {
+		party InvolvementParty
+		date  string
+	}
+	for i, v := range env.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		seen := util.Set[key]{}
+		for j, iv := range v.Involvements {
+			if iv == nil || iv.Party == nil {
+				continue
+			}
+			k := key{party: *iv.Party}
+			if iv.Date != nil {
+				k.date = *iv.Date
+			}
+			if seen.Contains(k) {
+				report(fmt.Sprintf("/vulnerabilities/%d/involvements/%d", i, j),
+					"party %q has more than one involvement at this date", k.party)
+			}
+			seen.Add(k)
+		}
+	}
+}
+
+// multipleHashAlgorithms implements test 6.1.25.
+func (env *testEnv) multipleHashAlgorithms(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		h := fpn.ProductIdentificationHelper
+		if h == nil || h.Hashes == nil {
+			return
+		}
+		seen := util.Set[string]{}
+		for i, fh := range h.Hashes.FileHashes {
+			if fh == nil || fh.Algorithm == nil {
+				continue
+			}
+			if seen.Contains(*fh.Algorithm) {
+				report(fmt.Sprintf(
+					"%s/product_identification_helper/hashes/file_hashes/%d/algorithm", path, i),
+					"hash algorithm %q is used more than once", *fh.Algorithm)
+			}
+			seen.Add(*fh.Algorithm)
+		}
+	})
+}
+
+// normalizeCategory removes hyphens, underscores and white
+// spaces from a category and turns it into lower case.
+func normalizeCategory(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '\t', '\n', '\r':
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+// prohibitedDocumentCategory implements test 6.1.26.
+func (env *testEnv) prohibitedDocumentCategory(report testReporter) {
+	category := env.category()
+	switch category {
+	case "", profileBase, profileSecurityIncidentResp,
+		profileInformationalAdvisory, profileSecurityAdvisory, profileVEX:
+		return
+	}
+	const path = "/document/category"
+	if strings.HasPrefix(strings.ToLower(category), profileReservedCategoryPrefix) {
+		report(path, "document category %q uses the reserved prefix %q",
+			category, profileReservedCategoryPrefix)
+		return
+	}
+	norm := normalizeCategory(category)
+	for _, profile := range []string{
+		profileSecurityIncidentResp,
+		profileInformationalAdvisory,
+		profileSecurityAdvisory,
+		profileVEX,
+	} {
+		if norm == normalizeCategory(profile) ||
+			norm == normalizeCategory(profile[len(profileReservedCategoryPrefix):]) {
+			report(path, "document category %q is too close to the profile %q",
+				category, profile)
+			return
+		}
+	}
+}
+
+// inProfiles returns true if the category of the document
+// is one of the given profiles.
+func (env *testEnv) inProfiles(profiles ...string) bool {
+	category := env.category()
+	for _, p := range profiles {
+		if p == category {
+			return true
+		}
+	}
+	return false
+}
+
+// profileDocumentNotes implements test 6.1.27.1.
+func (env *testEnv) profileDocumentNotes(report testReporter) {
+	if !env.inProfiles(profileInformationalAdvisory, profileSecurityIncidentResp) ||
+		env.adv.Document == nil {
+		return
+	}
+	for _, n := range env.adv.Document.Notes {
+		if n == nil || n.NoteCategory == nil {
+			continue
+		}
+		switch *n.NoteCategory {
+		case CSAFNoteCategoryDescription, CSAFNoteCategoryDetails,
+			CSAFNoteCategoryGeneral, CSAFNoteCategorySummary:
+			return
+		}
+	}
+	report("/document/notes",
+		"document notes need at least one item of category "+
+			"'description', 'details', 'general' or 'summary'")
+}
+
+// profileDocumentReferences implements test 6.1.27.2.
+func (env *testEnv) profileDocumentReferences(report testReporter) {
+	if !env.inProfiles(profileInformationalAdvisory, profileSecurityIncidentResp) ||
+		env.adv.Document == nil {
+		return
+	}
+	for _, r := range env.adv.Document.References {
+		if r != nil && (r.ReferenceCategory == nil ||
+			*r.ReferenceCategory == string(CSAFReferenceCategoryExternal)) {
+			return
+		}
+	}
+	report("/document/references",
+		"document references need at least one item of category 'external'")
+}
+
+// profileNoVulnerabilities implements test 6.1.27.3.
+func (env *testEnv) profileNoVulnerabilities(report testReporter) {
+	if env.inProfiles(profileInformationalAdvisory) && env.adv.Vulnerabilities != nil {
+		report("/vulnerabilities", "informational advisories must not have vulnerabilities")
+	}
+}
+
+// profileProductTree implements test 6.1.27.4.
+func (env *testEnv) profileProductTree(report testReporter) {
+	if env.inProfiles(profileSecurityAdvisory, profileVEX) && env.adv.ProductTree == nil {
+		report("/product_tree", "product tree is missing")
+	}
+}
+
+// visitVulnerabilities calls fn for every vulnerability with its path.
+func (env *testEnv) visitVulnerabilities(fn func(*Vulnerability, string)) {
+	for i, v := range env.adv.Vulnerabilities {
+		if v != nil {
+			fn(v, fmt.Sprintf("/vulnerabilities/%d", i))
+		}
+	}
+}
+
+// profileVulnerabilityNotes implements test 6.1.27.5.
+func (env *testEnv) profileVulnerabilityNotes(report testReporter) {
+	if !env.inProfiles(profileSecurityAdvisory, profileVEX) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.Notes == nil {
+			report(path+"/notes", "vulnerability notes are missing")
+		}
+	})
+}
+
+// profileProductStatus implements test 6.1.27.6.
+func (env *testEnv) profileProductStatus(report testReporter) {
+	if !env.inProfiles(profileSecurityAdvisory) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.ProductStatus == nil {
+			report(path+"/product_status", "product status is missing")
+		}
+	})
+}
+
+// profileVEXProductStatus implements test 6.1.27.7.
+func (env *testEnv) profileVEXProductStatus(report testReporter) {
+	if !env.inProfiles(profileVEX) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		ps := v.ProductStatus
+		if ps == nil || (ps.Fixed == nil && ps.KnownAffected == nil &&
+			ps.KnownNotAffected == nil && ps.UnderInvestigation == nil) {
+			report(path+"/product_status",
+				"product status needs at least one of 'fixed', 'known_affected', "+
+					"'known_not_affected' or 'under_investigation'")
+		}
+	})
+}
+
+// profileVulnerabilityID implements test 6.1.27.8.
+func (env *testEnv) profileVulnerabilityID(report testReporter) {
+	if !env.inProfiles(profileVEX) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.CVE == nil && v.IDs == nil {
+			report(path, "vulnerability needs either 'cve' or 'ids'")
+		}
+	})
+}
+
+// profileImpactStatement implements test 6.1.27.9.
+func (env *testEnv) profileImpactStatement(report testReporter) {
+	if !env.inProfiles(profileVEX) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.ProductStatus == nil {
+			return
+		}
+		covered := util.Set[ProductID]{}
+		for _, f := range v.Flags {
+			if f != nil {
+				for _, id := range env.expand(f.ProductIds, f.GroupIDs) {
+					covered.Add(id)
+				}
+			}
+		}
+		for _, t := range v.Threats {
+			if t != nil && t.Category != nil && *t.Category == CSAFThreatCategoryImpact {
+				for _, id := range env.expand(t.ProductIds, t.GroupIds) {
+					covered.Add(id)
+				}
+			}
+		}
+		visitProducts(v.ProductStatus.KnownNotAffected,
+			path+"/product_status/known_not_affected",
+			func(id ProductID, path string) {
+				if !covered.Contains(id) {
+					report(path, "impact statement for product id %q is missing", id)
+				}
+			})
+	})
+}
+
+// profileActionStatement implements test 6.1.27.10.
+func (env *testEnv) profileActionStatement(report testReporter) {
+	if !env.inProfiles(profileVEX) {
+		return
+	}
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.ProductStatus == nil {
+			return
+		}
+		covered := util.Set[ProductID]{}
+		for _, r := range v.Remediations {
+			if r != nil {
+				for _, id := range env.expand(r.ProductIds, r.GroupIds) {
+					covered.Add(id)
+				}
+			}
+		}
+		visitProducts(v.ProductStatus.KnownAffected,
+			path+"/product_status/known_affected",
+			func(id ProductID, path string) {
+				if !covered.Contains(id) {
+					report(path, "action statement for product id %q is missing", id)
+				}
+			})
+	})
+}
+
+// profileVulnerabilities implements test 6.1.27.11.
+func (env *testEnv) profileVulnerabilities(report testReporter) {
+	if env.inProfiles(profileSecurityAdvisory, profileVEX) && env.adv.Vulnerabilities == nil {
+		report("/vulnerabilities", "vulnerabilities are missing")
+	}
+}
+
+// translationSameLang implements test 6.1.28.
+func (env *testEnv) translationSameLang(report testReporter) {
+	doc := env.adv.Document
+	if doc != nil && doc.Lang != nil && doc.SourceLang != nil &&
+		strings.EqualFold(string(*doc.Lang), string(*doc.SourceLang)) {
+		report("/document/lang", "language is the same as the source language")
+	}
+}
+
+// remediationWithoutProducts implements test 6.1.29.
+func (env *testEnv) remediationWithoutProducts(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		for i, r := range v.Remediations {
+			if r != nil && r.ProductIds == nil && r.GroupIds == nil {
+				report(fmt.Sprintf("%s/remediations/%d", path, i),
+					"remediation does not reference any product")
+			}
+		}
+	})
+}
+
+// mixedVersioning implements test 6.1.30.
+func (env *testEnv) mixedVersioning(report testReporter) {
+	version, ok := env.documentVersion()
+	if !ok {
+		return
+	}
+	for _, rev := range env.sortedRevisions() {
+		if rev.number.semantic != version.semantic {
+			report(revisionPath(rev.index)+"/number",
+				"integer and semantic versioning are mixed")
+		}
+	}
+}
+
+var versionRangePattern = regexp.MustCompile(
+	`(?i)(<|<=|>|>=)|\b(after|all|before|earlier|later|prior|versions)\b`)
+
+// versionRangeInProductVersion implements test 6.1.31.
+func (env *testEnv) versionRangeInProductVersion(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitBranches(func(b *Branch, path string) {
+		if b.Category != nil && *b.Category == CSAFBranchCategoryProductVersion &&
+			b.Name != nil && versionRangePattern.MatchString(*b.Name) {
+			report(path+"/name", "product version %q contains a version range", *b.Name)
+		}
+	})
+}
+
+// flagWithoutProducts implements test 6.1.32.
+func (env *testEnv) flagWithoutProducts(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		for i, f := range v.Flags {
+			if f != nil && f.ProductIds == nil && f.GroupIDs == nil {
+				report(fmt.Sprintf("%s/flags/%d", path, i),
+					"flag does not reference any product")
+			}
+		}
+	})
+}
+
+// multipleFlagsPerProduct implements test 6.1.33.
+func (env *testEnv) multipleFlagsPerProduct(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		seen := util.Set[ProductID]{}
+		for i, f := range v.Flags {
+			if f == nil {
+				continue
+			}
+			for _, id := range env.expand(f.ProductIds, f.GroupIDs) {
+				if seen.Contains(id) {
+					report(fmt.Sprintf("%s/flags/%d", path, i),
+						"product id %q has more than one flag", id)
+				}
+				seen.Add(id)
+			}
+		}
+	})
+}
@@ -34,6 +34,10 @@ const (
 	TLPLabelAmber = "AMBER"
 	// TLPLabelRed is the 'RED' policy.
 	TLPLabelRed = "RED"
+	// TLPLabelClear is the 'CLEAR' policy of TLP v2.0 used by CSAF 2.1.
+	TLPLabelClear = "CLEAR"
+	// TLPLabelAmberStrict is the 'AMBER+STRICT' policy of TLP v2.0 used by CSAF 2.1.
+	TLPLabelAmberStrict = "AMBER+STRICT"
 )
 
 var tlpLabelPattern = alternativesUnmarshal(
@@ -42,6 +46,8 @@ var tlpLabelPattern = alternativesUnmarshal(
 	TLPLabelGreen,
 	TLPLabelAmber,
 	TLPLabelRed,
+	TLPLabelClear,
+	TLPLabelAmberStrict,
 )
 
 // JSONURL is an URL to JSON document.
@@ -93,6 +99,8 @@ const (
 	CSAFCategoryCoordinator Category = "coordinator"
 	// CSAFCategoryDiscoverer is the "discoverer" category.
 	CSAFCategoryDiscoverer Category = "discoverer"
+	// CSAFCategoryMultiplier is the "multiplier" category introduced in CSAF 2.1.
+	CSAFCategoryMultiplier Category = "multiplier"
 	// CSAFCategoryOther is the "other" category.
 	CSAFCategoryOther Category = "other"
 	// CSAFCategoryTranslator is the "translator" category.
@@ -106,6 +114,7 @@ const (
 var csafCategoryPattern = alternativesUnmarshal(
 	string(CSAFCategoryCoordinator),
 	string(CSAFCategoryDiscoverer),
+	string(CSAFCategoryMultiplier),
 	string(CSAFCategoryOther),
 	string(CSAFCategoryTranslator),
 	string(CSAFCategoryUser),
@@ -0,0 +1,371 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// optionalPreset are the optional (recommended) tests of section 6.2
+// of the CSAF 2.0 specification.
+// Not covered are the tests 6.2.13 (Sorting) and 6.2.20 (Additional
+// Properties) as they need the raw JSON document. The latter is
+// already checked by the schema validation.
+var optionalPreset = testPreset{
+	level: warningLevel,
+	tests: []advisoryTest{
+		{"optionalTest_6_2_1", (*testEnv).unusedProductDefinitions},
+		{"optionalTest_6_2_2", (*testEnv).missingRemediations},
+		{"optionalTest_6_2_3", (*testEnv).missingScores},
+		{"optionalTest_6_2_4", (*testEnv).buildMetadataInRevisions},
+		{"optionalTest_6_2_5", (*testEnv).olderInitialReleaseDate},
+		{"optionalTest_6_2_6", (*testEnv).olderCurrentReleaseDate},
+		{"optionalTest_6_2_7", (*testEnv).missingInvolvementDates},
+		{"optionalTest_6_2_8", (*testEnv).onlyMD5Hashes},
+		{"optionalTest_6_2_9", (*testEnv).onlySHA1Hashes},
+		{"optionalTest_6_2_10", (*testEnv).missingTLPLabel},
+		{"optionalTest_6_2_11", (*testEnv).missingCanonicalURL},
+		{"optionalTest_6_2_12", (*testEnv).missingDocumentLang},
+		{"optionalTest_6_2_14", (*testEnv).privateLanguage},
+		{"optionalTest_6_2_15", (*testEnv).defaultLanguage},
+		{"optionalTest_6_2_16", (*testEnv).missingIdentificationHelpers},
+		{"optionalTest_6_2_17", (*testEnv).cveInIDs},
+		{"optionalTest_6_2_18", (*testEnv).versionRangeWithoutVers},
+		{"optionalTest_6_2_19", (*testEnv).cvssForFixedProducts},
+	},
+}
+
+// affectedLists are the product status lists which
+// mark a product as affected.
+var affectedLists = []string{"first_affected", "known_affected", "last_affected"}
+
+// visitAffected calls fn for every product id of a vulnerability
+// which is in an affected status with its path.
+func visitAffected(v *Vulnerability, path string, fn func(ProductID, string)) {
+	if v.ProductStatus == nil {
+		return
+	}
+	for _, l := range v.ProductStatus.lists() {
+		for _, name := range affectedLists {
+			if l.name == name {
+				visitProducts(l.products, path+"/product_status/"+name, fn)
+			}
+		}
+	}
+}
+
+// unusedProductDefinitions implements test 6.2.1.
+func (env *testEnv) unusedProductDefinitions(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	used := util.Set[ProductID]{}
+	env.visitProductReferences(func(id ProductID, _ string) { used.Add(id) })
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		if fpn.ProductID != nil && !used.Contains(*fpn.ProductID) {
+			report(path+"/product_id",
+				"product id %q is defined but never used", *fpn.ProductID)
+		}
+	})
+}
+
+// missingRemediations implements test 6.2.2.
+func (env *testEnv) missingRemediations(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		remediated := util.Set[ProductID]{}
+		for _, r := range v.Remediations {
+			if r != nil {
+				for _, id := range env.expand(r.ProductIds, r.GroupIds) {
+					remediated.Add(id)
+				}
+			}
+		}
+		visitAffected(v, path, func(id ProductID, path string) {
+			if !remediated.Contains(id) {
+				report(path, "product id %q has no remediation", id)
+			}
+		})
+	})
+}
+
+// missingScores implements test 6.2.3.
+func (env *testEnv) missingScores(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		scored := util.Set[ProductID]{}
+		for _, s := range v.AllScores() {
+			if s != nil {
+				for _, id := range env.expand(s.Products, nil) {
+					scored.Add(id)
+				}
+			}
+		}
+		visitAffected(v, path, func(id ProductID, path string) {
+			if !scored.Contains(id) {
+				report(path, "product id %q has no score", id)
+			}
+		})
+	})
+}
+
+// buildMetadataInRevisions implements test 6.2.4.
+func (env *testEnv) buildMetadataInRevisions(report testReporter) {
+	t := env.tracking()
+	if t == nil {
+		return
+	}
+	for i, r := range t.RevisionHistory {
+		if r != nil && r.Number != nil && strings.Contains(string(*r.Number), "+") {
+			report(revisionPath(i)+"/number",
+				"revision number %q contains build metadata", *r.Number)
+		}
+	}
+}
+
+// parseTrackingDate parses a date of the tracking.
+func parseTrackingDate(date *string) (time.Time, bool) {
+	if date == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, *date)
+	return t, err == nil
+}
+
+// olderInitialReleaseDate implements test 6.2.5.
+func (env *testEnv) olderInitialReleaseDate(report testReporter) {
+	t := env.tracking()
+	if t == nil {
+		return
+	}
+	initial, ok := parseTrackingDate(t.InitialReleaseDate)
+	revs := env.sortedRevisions()
+	if !ok || len(revs) == 0 {
+		return
+	}
+	if oldest := revs[0]; initial.Before(oldest.date) {
+		report("/document/tracking/initial_release_date",
+			"initial release date is older than the oldest revision from %s",
+			oldest.date.Format(time.RFC3339))
+	}
+}
+
+// olderCurrentReleaseDate implements test 6.2.6.
+func (env *testEnv) olderCurrentReleaseDate(report testReporter) {
+	t := env.tracking()
+	if t == nil {
+		return
+	}
+	current, ok := parseTrackingDate(t.CurrentReleaseDate)
+	revs := env.sortedRevisions()
+	if !ok || len(revs) == 0 {
+		return
+	}
+	if newest := revs[len(revs)-1]; current.Before(newest.date) {
+		report("/document/tracking/current_release_date",
+			"current release date is older than the newest revision from %s",
+			newest.date.Format(time.RFC3339))
+	}
+}
+
+// missingInvolvementDates implements test 6.2.7.
+func (env *testEnv) missingInvolvementDates(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		for i, iv := range v.Involvements {
+			if iv != nil && iv.Date == nil {
+				report(fmt.Sprintf("%s/involvements/%d", path, i),
+					"involvement has no date")
+			}
+		}
+	})
+}
+
+// onlyHashAlgorithm reports all hashes which only use the given algorithm.
+func (env *testEnv) onlyHashAlgorithm(algorithm string, report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		h := fpn.ProductIdentificationHelper
+		if h == nil || h.Hashes == nil || len(h.Hashes.FileHashes) == 0 {
+			return
+		}
+		for _, fh := range h.Hashes.FileHashes {
+			if fh == nil || fh.Algorithm == nil ||
+				!strings.EqualFold(*fh.Algorithm, algorithm) {
+				return
+			}
+		}
+		report(path+"/product_identification_helper/hashes/file_hashes",
+			"%s is the only hash algorithm used", algorithm)
+	})
+}
+
+// onlyMD5Hashes implements test 6.2.8.
+func (env *testEnv) onlyMD5Hashes(report testReporter) {
+	env.onlyHashAlgorithm("md5", report)
+}
+
+// onlySHA1Hashes implements test 6.2.9.
+func (env *testEnv) onlySHA1Hashes(report testReporter) {
+	env.onlyHashAlgorithm("sha1", report)
+}
+
+// missingTLPLabel implements test 6.2.10.
+func (env *testEnv) missingTLPLabel(report testReporter) {
+	doc := env.adv.Document
+	if doc == nil || doc.Distribution == nil ||
+		doc.Distribution.TLP == nil || doc.Distribution.TLP.DocumentTLPLabel == nil {
+		report("/document/distribution/tlp/label", "TLP label is missing")
+	}
+}
+
+// missingCanonicalURL implements test 6.2.11.
+func (env *testEnv) missingCanonicalURL(report testReporter) {
+	doc := env.adv.Document
+	if doc == nil || doc.Tracking == nil || doc.Tracking.ID == nil {
+		return
+	}
+	fname := util.CleanFileName(string(*doc.Tracking.ID))
+	for _, r := range doc.References {
+		if r == nil || r.URL == nil || r.ReferenceCategory == nil ||
+			*r.ReferenceCategory != string(CSAFReferenceCategorySelf) {
+			continue
+		}
+		if strings.HasPrefix(*r.URL, "https://") && strings.HasSuffix(*r.URL, "/"+fname) {
+			return
+		}
+	}
+	report("/document/references",
+		"no reference of category 'self' points to the canonical URL ending in %q", fname)
+}
+
+// missingDocumentLang implements test 6.2.12.
+func (env *testEnv) missingDocumentLang(report testReporter) {
+	if doc := env.adv.Document; doc == nil || doc.Lang == nil {
+		report("/document/lang", "document language is missing")
+	}
+}
+
+// visitLanguages calls fn for the language and the
+// source language of the document with their paths.
+func (env *testEnv) visitLanguages(fn func(Lang, string)) {
+	doc := env.adv.Document
+	if doc == nil {
+		return
+	}
+	if doc.Lang != nil {
+		fn(*doc.Lang, "/document/lang")
+	}
+	if doc.SourceLang != nil {
+		fn(*doc.SourceLang, "/document/source_lang")
+	}
+}
+
+// privateLanguage implements test 6.2.14.
+func (env *testEnv) privateLanguage(report testReporter) {
+	env.visitLanguages(func(lang Lang, path string) {
+		subtags := strings.Split(strings.ToLower(string(lang)), "-")
+		primary := subtags[0]
+		if len(primary) == 3 && primary >= "qaa" && primary <= "qtz" {
+			report(path, "language %q uses a private use language", lang)
+			return
+		}
+		for _, sub := range subtags {
+			if sub == "x" {
+				report(path, "language %q uses a private use subtag", lang)
+				return
+			}
+		}
+	})
+}
+
+// defaultLanguage implements test 6.2.15.
+func (env *testEnv) defaultLanguage(report testReporter) {
+	env.visitLanguages(func(lang Lang, path string) {
+		if strings.EqualFold(string(lang), "i-default") {
+			report(path, "language %q is the default language", lang)
+		}
+	})
+}
+
+// missingIdentificationHelpers implements test 6.2.16.
+func (env *testEnv) missingIdentificationHelpers(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitFullProductNames(func(fpn *FullProductName, path string) {
+		if fpn.ProductIdentificationHelper == nil {
+			report(path, "product has no product identification helper")
+		}
+	})
+}
+
+var cveIDPattern = regexp.MustCompile(`^CVE-[0-9]{4}-[0-9]{4,}$`)
+
+// cveInIDs implements test 6.2.17.
+func (env *testEnv) cveInIDs(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		for i, id := range v.IDs {
+			if id != nil && id.Text != nil && cveIDPattern.MatchString(*id.Text) {
+				report(fmt.Sprintf("%s/ids/%d/text", path, i),
+					"CVE %q should be given in the cve field", *id.Text)
+			}
+		}
+	})
+}
+
+// versionRangeWithoutVers implements test 6.2.18.
+func (env *testEnv) versionRangeWithoutVers(report testReporter) {
+	if env.adv.ProductTree == nil {
+		return
+	}
+	env.adv.ProductTree.visitBranches(func(b *Branch, path string) {
+		if b.Category != nil && *b.Category == CSAFBranchCategoryProductVersionRange &&
+			b.Name != nil && !strings.HasPrefix(*b.Name, "vers:") {
+			report(path+"/name", "product version range %q does not use vers", *b.Name)
+		}
+	})
+}
+
+// cvssForFixedProducts implements test 6.2.19.
+func (env *testEnv) cvssForFixedProducts(report testReporter) {
+	env.visitVulnerabilities(func(v *Vulnerability, path string) {
+		if v.ProductStatus == nil {
+			return
+		}
+		fixed := util.Set[ProductID]{}
+		for _, id := range env.expand(v.ProductStatus.Fixed, nil) {
+			fixed.Add(id)
+		}
+		for _, id := range env.expand(v.ProductStatus.FirstFixed, nil) {
+			fixed.Add(id)
+		}
+		visitVulnerabilityScores(v, path, func(s *Score, scorePath, _ string) {
+			for _, id := range env.expand(s.Products, nil) {
+				if !fixed.Contains(id) {
+					continue
+				}
+				if s.CVSS2 != nil && (s.CVSS2.EnvironmentalScore == nil ||
+					*s.CVSS2.EnvironmentalScore != 0) {
+					report(scorePath+"/cvss_v2",
+						"fixed product id %q has no environmental score of 0", id)
+				}
+				if s.CVSS3 != nil && (s.CVSS3.EenvironmentalScore == nil ||
+					*s.CVSS3.EenvironmentalScore != 0) {
+					report(scorePath+"/cvss_v3",
+						"fixed product id %q has no environmental score of 0", id)
+				}
+			}
+		})
+	})
+}
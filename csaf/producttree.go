@@ -0,0 +1,301 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"sort"
+	"strings"
+)
+
+// BranchPathElement is an element of the branch chain
+// leading to a product.
+type BranchPathElement struct {
+	Category BranchCategory `json:"category"`
+	Name     string         `json:"name"`
+}
+
+// ProductRelationship is the relationship a product is defined by.
+type ProductRelationship struct {
+	Category                  RelationshipCategory `json:"category"`
+	ProductReference          ProductID            `json:"product_reference"`
+	RelatesToProductReference ProductID            `json:"relates_to_product_reference"`
+}
+
+// ProductEntry is a product of a product tree with its resolved
+// branch path and identification helpers.
+// Vendor, ProductFamily, ProductName and Version are taken from
+// the innermost branches of the respective categories.
+// Version is also set by a product version range.
+type ProductEntry struct {
+	ProductID     ProductID            `json:"product_id"`
+	Name          string               `json:"name,omitempty"`
+	Path          []BranchPathElement  `json:"path,omitempty"`
+	Vendor        string               `json:"vendor,omitempty"`
+	ProductFamily string               `json:"product_family,omitempty"`
+	ProductName   string               `json:"product_name,omitempty"`
+	Version       string               `json:"version,omitempty"`
+	CPEs          []CPE                `json:"cpes,omitempty"`
+	PURLs         []PURL               `json:"purls,omitempty"`
+	Hashes        []*Hashes            `json:"hashes,omitempty"`
+	Relationship  *ProductRelationship `json:"relationship,omitempty"`
+
+	helpers []*ProductIdentificationHelper
+}
+
+// Helpers returns the product identification helpers of the product.
+func (pe *ProductEntry) Helpers() []*ProductIdentificationHelper {
+	return pe.helpers
+}
+
+// addHelper adds the identifiers of a helper to the entry.
+func (pe *ProductEntry) addHelper(h *ProductIdentificationHelper) {
+	if h == nil {
+		return
+	}
+	pe.helpers = append(pe.helpers, h)
+	if h.CPE != nil && !containsValue(pe.CPEs, *h.CPE) {
+		pe.CPEs = append(pe.CPEs, *h.CPE)
+	}
+	if h.PURL != nil && !containsValue(pe.PURLs, *h.PURL) {
+		pe.PURLs = append(pe.PURLs, *h.PURL)
+	}
+	for _, purl := range h.PURLs {
+		if purl != nil && !containsValue(pe.PURLs, *purl) {
+			pe.PURLs = append(pe.PURLs, *purl)
+		}
+	}
+	if h.Hashes != nil {
+		pe.Hashes = append(pe.Hashes, h.Hashes)
+	}
+}
+
+// containsValue returns true if v is in list.
+func containsValue[T comparable](list []T, v T) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPath sets the path of the entry and derives vendor,
+// product family, product name and version from it.
+func (pe *ProductEntry) applyPath(path []BranchPathElement) {
+	pe.Path = path
+	for _, e := range path {
+		switch e.Category {
+		case CSAFBranchCategoryVendor:
+			pe.Vendor = e.Name
+		case CSAFBranchCategoryProductFamily:
+			pe.ProductFamily = e.Name
+		case CSAFBranchCategoryProductName:
+			pe.ProductName = e.Name
+		case CSAFBranchCategoryProductVersion, CSAFBranchCategoryProductVersionRange:
+			pe.Version = e.Name
+		}
+	}
+}
+
+// ProductIndex is a flattened, resolved view of a product tree.
+type ProductIndex struct {
+	entries []*ProductEntry
+	byID    map[ProductID]*ProductEntry
+	groups  map[ProductGroupID][]ProductID
+}
+
+// Index flattens the branches, the full product names and the
+// relationships of the product tree into a product index.
+// A product defined more than once is merged into one entry.
+// Products defined by relationships without a branch path of
+// their own inherit the path of their product reference.
+func (pt *ProductTree) Index() *ProductIndex {
+	pi := &ProductIndex{
+		byID:   map[ProductID]*ProductEntry{},
+		groups: map[ProductGroupID][]ProductID{},
+	}
+	if pt == nil {
+		return pi
+	}
+
+	var recBranch func(b *Branch, path []BranchPathElement)
+	recBranch = func(b *Branch, path []BranchPathElement) {
+		if b == nil {
+			return
+		}
+		var elem BranchPathElement
+		if b.Category != nil {
+			elem.Category = *b.Category
+		}
+		if b.Name != nil {
+			elem.Name = *b.Name
+		}
+		path = append(path[:len(path):len(path)], elem)
+		if e := pi.add(b.Product); e != nil && e.Path == nil {
+			e.applyPath(path)
+		}
+		for _, c := range b.Branches {
+			recBranch(c, path)
+		}
+	}
+	for _, b := range pt.Branches {
+		recBranch(b, nil)
+	}
+
+	if fpns := pt.FullProductNames; fpns != nil {
+		for _, fpn := range *fpns {
+			pi.add(fpn)
+		}
+	}
+
+	if rels := pt.RelationShips; rels != nil {
+		for _, rel := range *rels {
+			if rel == nil {
+				continue
+			}
+			e := pi.add(rel.FullProductName)
+			if e == nil {
+				continue
+			}
+			var pr ProductRelationship
+			if rel.Category != nil {
+				pr.Category = *rel.Category
+			}
+			if rel.ProductReference != nil {
+				pr.ProductReference = *rel.ProductReference
+			}
+			if rel.RelatesToProductReference != nil {
+				pr.RelatesToProductReference = *rel.RelatesToProductReference
+			}
+			e.Relationship = &pr
+		}
+		// Resolve the paths after all relationships are known
+		// as they may reference each other.
+		for _, e := range pi.entries {
+			pi.inheritPath(e, map[ProductID]bool{})
+		}
+	}
+
+	for _, pg := range pt.ProductGroups {
+		if pg == nil || pg.GroupID == nil || pg.ProductIDs == nil {
+			continue
+		}
+		for _, p := range *pg.ProductIDs {
+			if p != nil && !containsValue(pi.groups[*pg.GroupID], *p) {
+				pi.groups[*pg.GroupID] = append(pi.groups[*pg.GroupID], *p)
+			}
+		}
+	}
+	return pi
+}
+
+// add adds a full product name to the index and returns its entry.
+func (pi *ProductIndex) add(fpn *FullProductName) *ProductEntry {
+	if fpn == nil || fpn.ProductID == nil {
+		return nil
+	}
+	e := pi.byID[*fpn.ProductID]
+	if e == nil {
+		e = &ProductEntry{ProductID: *fpn.ProductID}
+		pi.byID[e.ProductID] = e
+		pi.entries = append(pi.entries, e)
+	}
+	if e.Name == "" && fpn.Name != nil {
+		e.Name = *fpn.Name
+	}
+	e.addHelper(fpn.ProductIdentificationHelper)
+	return e
+}
+
+// inheritPath sets the path of a relationship product to the
+// path of its product reference if it has none of its own.
+// visited protects against circular definitions.
+func (pi *ProductIndex) inheritPath(e *ProductEntry, visited map[ProductID]bool) {
+	if e.Path != nil || e.Relationship == nil || visited[e.ProductID] {
+		return
+	}
+	visited[e.ProductID] = true
+	ref := pi.byID[e.Relationship.ProductReference]
+	if ref == nil {
+		return
+	}
+	pi.inheritPath(ref, visited)
+	if ref.Path != nil {
+		e.applyPath(ref.Path)
+	}
+}
+
+// Products returns all products in order of their definition.
+func (pi *ProductIndex) Products() []*ProductEntry {
+	return pi.entries
+}
+
+// Product returns the product with the given id or nil if not found.
+func (pi *ProductIndex) Product(id ProductID) *ProductEntry {
+	return pi.byID[id]
+}
+
+// filter returns the products for which accept returns true.
+func (pi *ProductIndex) filter(accept func(*ProductEntry) bool) []*ProductEntry {
+	var found []*ProductEntry
+	for _, e := range pi.entries {
+		if accept(e) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// FindByPURL returns the products having a PURL starting with prefix.
+func (pi *ProductIndex) FindByPURL(prefix string) []*ProductEntry {
+	return pi.filter(func(e *ProductEntry) bool {
+		for _, purl := range e.PURLs {
+			if strings.HasPrefix(string(purl), prefix) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// FindByCPE returns the products having a CPE starting with prefix.
+func (pi *ProductIndex) FindByCPE(prefix string) []*ProductEntry {
+	return pi.filter(func(e *ProductEntry) bool {
+		for _, cpe := range e.CPEs {
+			if strings.HasPrefix(string(cpe), prefix) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Group returns the products of the product group with the given id.
+// Product ids without definition are left out.
+func (pi *ProductIndex) Group(id ProductGroupID) []*ProductEntry {
+	var found []*ProductEntry
+	for _, p := range pi.groups[id] {
+		if e := pi.byID[p]; e != nil {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// GroupsOf returns the sorted ids of the product groups the product is in.
+func (pi *ProductIndex) GroupsOf(id ProductID) []ProductGroupID {
+	var groups []ProductGroupID
+	for gid, members := range pi.groups {
+		if containsValue(members, id) {
+			groups = append(groups, gid)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i] < groups[j] })
+	return groups
+}
@@ -0,0 +1,131 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProductTreeIndex(t *testing.T) {
+	adv := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		pt := walkJSON(doc, "product_tree").(map[string]any)
+		pt["full_product_names"] = []any{map[string]any{
+			"name":       "Example OS 11",
+			"product_id": "CSAFPID-0100",
+			"product_identification_helper": map[string]any{
+				"cpe": "cpe:2.3:o:example:os:11:*:*:*:*:*:*:*",
+			},
+		}}
+		pt["relationships"] = []any{map[string]any{
+			"category":                     "installed_on",
+			"product_reference":            "CSAFPID-0001",
+			"relates_to_product_reference": "CSAFPID-0100",
+			"full_product_name": map[string]any{
+				"name":       "Example Company Product 1.0 on Example OS 11",
+				"product_id": "CSAFPID-0200",
+			},
+		}}
+	})
+
+	pi := adv.ProductTree.Index()
+
+	var ids []ProductID
+	for _, e := range pi.Products() {
+		ids = append(ids, e.ProductID)
+	}
+	if want := []ProductID{"CSAFPID-0001", "CSAFPID-0002", "CSAFPID-0100", "CSAFPID-0200"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected products %v, got %v", want, ids)
+	}
+
+	e := pi.Product("CSAFPID-0001")
+	if e.Vendor != "Example Company" || e.ProductName != "Product" || e.Version != "1.0" {
+		t.Errorf("unexpected vendor/product/version %q/%q/%q", e.Vendor, e.ProductName, e.Version)
+	}
+	if len(e.Path) != 3 || e.Path[2].Category != CSAFBranchCategoryProductVersion {
+		t.Errorf("unexpected path %v", e.Path)
+	}
+	if want := []PURL{"pkg:generic/example/product@1.0"}; !reflect.DeepEqual(e.PURLs, want) {
+		t.Errorf("expected PURLs %v, got %v", want, e.PURLs)
+	}
+
+	rel := pi.Product("CSAFPID-0200")
+	if rel.Relationship == nil ||
+		rel.Relationship.Category != CSAFRelationshipCategoryInstalledOn ||
+		rel.Relationship.RelatesToProductReference != "CSAFPID-0100" {
+		t.Errorf("unexpected relationship %+v", rel.Relationship)
+	}
+	if rel.Version != "1.0" {
+		t.Errorf("expected relationship to inherit version 1.0, got %q", rel.Version)
+	}
+
+	if pi.Product("CSAFPID-9999") != nil {
+		t.Error("expected unknown product to be nil")
+	}
+
+	productIDs := func(entries []*ProductEntry) []ProductID {
+		var ids []ProductID
+		for _, e := range entries {
+			ids = append(ids, e.ProductID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  []*ProductEntry
+		want []ProductID
+	}{
+		{"purl prefix", pi.FindByPURL("pkg:generic/example/"), []ProductID{"CSAFPID-0001", "CSAFPID-0002"}},
+		{"purl exact", pi.FindByPURL("pkg:generic/example/product@1.1"), []ProductID{"CSAFPID-0002"}},
+		{"purl none", pi.FindByPURL("pkg:npm/"), nil},
+		{"cpe prefix", pi.FindByCPE("cpe:2.3:o:example:os:"), []ProductID{"CSAFPID-0100"}},
+		{"group", pi.Group("CSAFGID-0001"), []ProductID{"CSAFPID-0001", "CSAFPID-0002"}},
+		{"unknown group", pi.Group("CSAFGID-9999"), nil},
+	} {
+		if got := productIDs(tc.got); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	if groups := pi.GroupsOf("CSAFPID-0002"); !reflect.DeepEqual(groups, []ProductGroupID{"CSAFGID-0001"}) {
+		t.Errorf("unexpected groups %v", groups)
+	}
+}
+
+func TestProductTreeIndexCSAF21(t *testing.T) {
+	older := loadTestAdvisoryModel(t, nil)
+	for _, adv := range []*Advisory{
+		loadTestAdvisoryModel(t, toCSAF21),
+		func() *Advisory {
+			converted, _, err := older.ConvertToCSAF21(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return converted
+		}(),
+	} {
+		pi := adv.ProductTree.Index()
+		if n := len(pi.FindByPURL("pkg:generic/example/")); n != 2 {
+			t.Errorf("expected 2 products, got %d", n)
+		}
+		e := pi.Product("CSAFPID-0002")
+		if want := []PURL{"pkg:generic/example/product@1.1"}; !reflect.DeepEqual(e.PURLs, want) {
+			t.Errorf("expected PURLs %v, got %v", want, e.PURLs)
+		}
+	}
+}
+
+func TestProductTreeIndexEmpty(t *testing.T) {
+	var pt *ProductTree
+	pi := pt.Index()
+	if len(pi.Products()) != 0 || pi.Product("CSAFPID-0001") != nil {
+		t.Error("expected empty index")
+	}
+}
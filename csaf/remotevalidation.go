@@ -78,6 +78,15 @@ type RemoteValidationResult struct {
 	Tests []RemoteTest `json:"tests"`
 }
 
+// Warnings returns the number of warnings of all tests.
+func (rvr *RemoteValidationResult) Warnings() int {
+	var n int
+	for i := range rvr.Tests {
+		n += len(rvr.Tests[i].Warning)
+	}
+	return n
+}
+
 type cache interface {
 	get(key []byte) ([]byte, error)
 	set(key []byte, value []byte) error
@@ -166,6 +166,15 @@ type Format struct {
 	Version string `json:"version"`
 }
 
+// CSAFFormat returns the format of an entry of a CSAF document
+// of the given version. Unknown versions are treated as CSAF 2.0.
+func CSAFFormat(version Version) Format {
+	if version != CSAFVersion21 {
+		version = CSAFVersion20
+	}
+	return Format{Schema: SchemaURL(version), Version: string(version)}
+}
+
 // Entry for ROLIE.
 type Entry struct {
 	ID        string    `json:"id"`
@@ -0,0 +1,232 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+// ProductStatusCategory is the name of a product status list.
+type ProductStatusCategory string
+
+const (
+	// ProductStatusFirstAffected is the "first_affected" status.
+	ProductStatusFirstAffected ProductStatusCategory = "first_affected"
+	// ProductStatusFirstFixed is the "first_fixed" status.
+	ProductStatusFirstFixed ProductStatusCategory = "first_fixed"
+	// ProductStatusFixed is the "fixed" status.
+	ProductStatusFixed ProductStatusCategory = "fixed"
+	// ProductStatusKnownAffected is the "known_affected" status.
+	ProductStatusKnownAffected ProductStatusCategory = "known_affected"
+	// ProductStatusKnownNotAffected is the "known_not_affected" status.
+	ProductStatusKnownNotAffected ProductStatusCategory = "known_not_affected"
+	// ProductStatusLastAffected is the "last_affected" status.
+	ProductStatusLastAffected ProductStatusCategory = "last_affected"
+	// ProductStatusRecommended is the "recommended" status.
+	ProductStatusRecommended ProductStatusCategory = "recommended"
+	// ProductStatusUnderInvestigation is the "under_investigation" status.
+	ProductStatusUnderInvestigation ProductStatusCategory = "under_investigation"
+)
+
+// ProductVulnerabilityStatus is the resolved status of a product
+// regarding a vulnerability.
+// Related holds the statuses of the products which are defined
+// by relationships referencing this product.
+type ProductVulnerabilityStatus struct {
+	ProductID     ProductID
+	Vulnerability *Vulnerability
+	Relationship  *ProductRelationship
+	Statuses      []ProductStatusCategory
+	Remediations  []*Remediation
+	Threats       []*Threat
+	Flags         []*Flag
+	Scores        []*Score
+	Related       []*ProductVulnerabilityStatus
+}
+
+// has returns true if the status contains one of the given categories.
+func (pvs *ProductVulnerabilityStatus) has(categories ...ProductStatusCategory) bool {
+	for _, s := range pvs.Statuses {
+		for _, c := range categories {
+			if s == c {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Affected returns true if the product is affected.
+func (pvs *ProductVulnerabilityStatus) Affected() bool {
+	return pvs.has(
+		ProductStatusFirstAffected,
+		ProductStatusKnownAffected,
+		ProductStatusLastAffected)
+}
+
+// NotAffected returns true if the product is known to be not affected.
+func (pvs *ProductVulnerabilityStatus) NotAffected() bool {
+	return pvs.has(ProductStatusKnownNotAffected)
+}
+
+// Fixed returns true if the product contains a fix.
+func (pvs *ProductVulnerabilityStatus) Fixed() bool {
+	return pvs.has(ProductStatusFirstFixed, ProductStatusFixed)
+}
+
+// UnderInvestigation returns true if the status of the
+// product is under investigation.
+func (pvs *ProductVulnerabilityStatus) UnderInvestigation() bool {
+	return pvs.has(ProductStatusUnderInvestigation)
+}
+
+// Known returns true if the product is mentioned in the product
+// status, the remediations, the flags or the scores of the vulnerability.
+// Threats are not considered as they may apply to all products.
+func (pvs *ProductVulnerabilityStatus) Known() bool {
+	return len(pvs.Statuses) > 0 ||
+		len(pvs.Remediations) > 0 ||
+		len(pvs.Flags) > 0 ||
+		len(pvs.Scores) > 0
+}
+
+// StatusResolver resolves the status of products regarding the
+// vulnerabilities of an advisory. Product groups are expanded.
+type StatusResolver struct {
+	adv   *Advisory
+	index *ProductIndex
+}
+
+// NewStatusResolver returns a new status resolver for an advisory.
+func NewStatusResolver(adv *Advisory) *StatusResolver {
+	return &StatusResolver{
+		adv:   adv,
+		index: adv.ProductTree.Index(),
+	}
+}
+
+// Index returns the product index used by the resolver.
+func (sr *StatusResolver) Index() *ProductIndex {
+	return sr.index
+}
+
+// Vulnerability returns the vulnerability with the given key.
+// The key is the CVE or if not present the first of the ids
+// in the form "system_name:text" or the title.
+func (sr *StatusResolver) Vulnerability(key string) *Vulnerability {
+	for _, v := range sr.adv.Vulnerabilities {
+		if v != nil && v.key() == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// Status returns the status of a product regarding the vulnerability
+// with the given key. It returns nil if there is no such vulnerability.
+func (sr *StatusResolver) Status(id ProductID, key string) *ProductVulnerabilityStatus {
+	v := sr.Vulnerability(key)
+	if v == nil {
+		return nil
+	}
+	return sr.VulnerabilityStatus(id, v)
+}
+
+// Statuses returns the statuses of a product regarding all
+// vulnerabilities which mention it directly or by relationship.
+func (sr *StatusResolver) Statuses(id ProductID) []*ProductVulnerabilityStatus {
+	var statuses []*ProductVulnerabilityStatus
+	for _, v := range sr.adv.Vulnerabilities {
+		if v == nil {
+			continue
+		}
+		if pvs := sr.VulnerabilityStatus(id, v); pvs.Known() || len(pvs.Related) > 0 {
+			statuses = append(statuses, pvs)
+		}
+	}
+	return statuses
+}
+
+// VulnerabilityStatus returns the status of a product regarding
+// the given vulnerability.
+func (sr *StatusResolver) VulnerabilityStatus(id ProductID, v *Vulnerability) *ProductVulnerabilityStatus {
+	return sr.resolve(id, v, map[ProductID]bool{})
+}
+
+// resolve builds the status of a product and its related products.
+// visited protects against circular relationships.
+func (sr *StatusResolver) resolve(
+	id ProductID,
+	v *Vulnerability,
+	visited map[ProductID]bool,
+) *ProductVulnerabilityStatus {
+	visited[id] = true
+	pvs := &ProductVulnerabilityStatus{
+		ProductID:     id,
+		Vulnerability: v,
+	}
+	if e := sr.index.Product(id); e != nil {
+		pvs.Relationship = e.Relationship
+	}
+	if ps := v.ProductStatus; ps != nil {
+		for _, l := range ps.lists() {
+			if sr.index.contains(id, l.products, nil) {
+				pvs.Statuses = append(pvs.Statuses, ProductStatusCategory(l.name))
+			}
+		}
+	}
+	for _, r := range v.Remediations {
+		if r != nil && sr.index.contains(id, r.ProductIds, r.GroupIds) {
+			pvs.Remediations = append(pvs.Remediations, r)
+		}
+	}
+	for _, t := range v.Threats {
+		// Threats without products apply to the whole vulnerability.
+		if t != nil && (t.ProductIds == nil && t.GroupIds == nil ||
+			sr.index.contains(id, t.ProductIds, t.GroupIds)) {
+			pvs.Threats = append(pvs.Threats, t)
+		}
+	}
+	for _, f := range v.Flags {
+		if f != nil && sr.index.contains(id, f.ProductIds, f.GroupIDs) {
+			pvs.Flags = append(pvs.Flags, f)
+		}
+	}
+	for _, s := range v.AllScores() {
+		if s != nil && sr.index.contains(id, s.Products, nil) {
+			pvs.Scores = append(pvs.Scores, s)
+		}
+	}
+	for _, e := range sr.index.Products() {
+		if r := e.Relationship; r != nil && !visited[e.ProductID] &&
+			(r.ProductReference == id || r.RelatesToProductReference == id) {
+			if related := sr.resolve(e.ProductID, v, visited); related.Known() ||
+				len(related.Related) > 0 {
+				pvs.Related = append(pvs.Related, related)
+			}
+		}
+	}
+	return pvs
+}
+
+// contains returns true if the product is in the given
+// products or in one of the given product groups.
+func (pi *ProductIndex) contains(id ProductID, products *Products, groups *ProductGroupIDs) bool {
+	if products != nil {
+		for _, p := range *products {
+			if p != nil && *p == id {
+				return true
+			}
+		}
+	}
+	if groups != nil {
+		for _, g := range *groups {
+			if g != nil && containsValue(pi.groups[*g], id) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStatusResolver(t *testing.T) {
+	adv := loadTestAdvisoryModel(t, func(doc map[string]any) {
+		pt := walkJSON(doc, "product_tree").(map[string]any)
+		pt["full_product_names"] = []any{map[string]any{
+			"name":       "Example OS 11",
+			"product_id": "CSAFPID-0100",
+		}}
+		pt["relationships"] = []any{map[string]any{
+			"category":                     "installed_on",
+			"product_reference":            "CSAFPID-0002",
+			"relates_to_product_reference": "CSAFPID-0100",
+			"full_product_name": map[string]any{
+				"name":       "Example Company Product 1.1 on Example OS 11",
+				"product_id": "CSAFPID-0200",
+			},
+		}}
+		v := walkJSON(doc, "vulnerabilities", 0).(map[string]any)
+		ps := v["product_status"].(map[string]any)
+		ps["known_not_affected"] = []any{"CSAFPID-0200"}
+		v["flags"] = []any{map[string]any{
+			"label":       "vulnerable_code_not_in_execute_path",
+			"product_ids": []any{"CSAFPID-0200"},
+		}}
+		v["threats"] = []any{
+			map[string]any{"category": "exploit_status", "details": "Exploited."},
+			map[string]any{
+				"category":  "impact",
+				"details":   "Full compromise.",
+				"group_ids": []any{"CSAFGID-0001"},
+			},
+		}
+	})
+
+	sr := NewStatusResolver(adv)
+
+	if sr.Status("CSAFPID-0001", "CVE-9999-0001") != nil {
+		t.Error("expected unknown vulnerability to be nil")
+	}
+
+	affected := sr.Status("CSAFPID-0001", "CVE-2023-0001")
+	if !affected.Affected() || affected.Fixed() || affected.NotAffected() {
+		t.Errorf("expected CSAFPID-0001 to be affected, got %v", affected.Statuses)
+	}
+	// The remediation is given by group.
+	if len(affected.Remediations) != 1 {
+		t.Errorf("expected one remediation via group, got %d", len(affected.Remediations))
+	}
+	if len(affected.Threats) != 2 {
+		t.Errorf("expected two threats, got %d", len(affected.Threats))
+	}
+	if len(affected.Scores) != 1 {
+		t.Errorf("expected one score, got %d", len(affected.Scores))
+	}
+	if len(affected.Related) != 0 {
+		t.Errorf("expected no related products, got %d", len(affected.Related))
+	}
+
+	fixed := sr.Status("CSAFPID-0002", "CVE-2023-0001")
+	if !fixed.Fixed() || fixed.Affected() {
+		t.Errorf("expected CSAFPID-0002 to be fixed, got %v", fixed.Statuses)
+	}
+	if len(fixed.Related) != 1 {
+		t.Fatalf("expected one related product, got %d", len(fixed.Related))
+	}
+	related := fixed.Related[0]
+	if related.ProductID != "CSAFPID-0200" || !related.NotAffected() {
+		t.Errorf("unexpected related status %s %v", related.ProductID, related.Statuses)
+	}
+	if related.Relationship == nil ||
+		related.Relationship.Category != CSAFRelationshipCategoryInstalledOn {
+		t.Errorf("unexpected relationship %+v", related.Relationship)
+	}
+	if len(related.Flags) != 1 ||
+		*related.Flags[0].Label != CSAFFlagLabelVulnerableCodeNotInExecutePath {
+		t.Errorf("unexpected flags %v", related.Flags)
+	}
+	// Only the general threat applies to the relationship product.
+	if len(related.Threats) != 1 {
+		t.Errorf("expected one threat, got %d", len(related.Threats))
+	}
+
+	// The platform is only mentioned by the relationship.
+	platform := sr.Statuses("CSAFPID-0100")
+	if len(platform) != 1 || platform[0].Known() || len(platform[0].Related) != 1 {
+		t.Errorf("unexpected statuses of platform %+v", platform)
+	}
+
+	if want := []ProductStatusCategory{ProductStatusFixed}; !reflect.DeepEqual(fixed.Statuses, want) {
+		t.Errorf("expected %v, got %v", want, fixed.Statuses)
+	}
+
+	if statuses := sr.Statuses("CSAFPID-9999"); len(statuses) != 0 {
+		t.Errorf("expected no statuses for unknown product, got %d", len(statuses))
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 const (
 	idExpr                 = `$.document.tracking.id`
+	versionExpr            = `$.document.csaf_version`
 	titleExpr              = `$.document.title`
 	publisherExpr          = `$.document.publisher`
 	initialReleaseDateExpr = `$.document.tracking.initial_release_date`
@@ -23,11 +24,14 @@ const (
 	tlpLabelExpr           = `$.document.distribution.tlp.label`
 	summaryExpr            = `$.document.notes[? @.category=="summary" || @.type=="summary"].text`
 	statusExpr             = `$.document.tracking.status`
+	scoresExpr             = `$.vulnerabilities[*].scores[*]`
+	metricsExpr            = `$.vulnerabilities[*].metrics[*]`
 )
 
 // AdvisorySummary is a summary of some essentials of an CSAF advisory.
 type AdvisorySummary struct {
 	ID                 string
+	CSAFVersion        Version
 	Title              string
 	Publisher          *Publisher
 	InitialReleaseDate time.Time
@@ -35,6 +39,8 @@ type AdvisorySummary struct {
 	Summary            string
 	TLPLabel           string
 	Status             string
+	Scores             Scores
+	Metrics            Metrics
 }
 
 // NewAdvisorySummary creates a summary from an advisory doc
@@ -47,6 +53,7 @@ func NewAdvisorySummary(
 	e := &AdvisorySummary{
 		Publisher: new(Publisher),
 	}
+	var version string
 
 	if err := pe.Match([]util.PathEvalMatcher{
 		{Expr: idExpr, Action: util.StringMatcher(&e.ID)},
@@ -57,9 +64,31 @@ func NewAdvisorySummary(
 		{Expr: tlpLabelExpr, Action: util.StringMatcher(&e.TLPLabel), Optional: true},
 		{Expr: publisherExpr, Action: util.ReMarshalMatcher(e.Publisher)},
 		{Expr: statusExpr, Action: util.StringMatcher(&e.Status)},
+		{Expr: scoresExpr, Action: util.ReMarshalMatcher(&e.Scores), Optional: true},
+		{Expr: metricsExpr, Action: util.ReMarshalMatcher(&e.Metrics), Optional: true},
+		{Expr: versionExpr, Action: util.StringMatcher(&version), Optional: true},
 	}, doc); err != nil {
 		return nil, err
 	}
+	e.CSAFVersion = Version(version)
 
 	return e, nil
 }
+
+// HighestBaseScores returns the highest base score of the scores
+// and CSAF 2.1 metrics of the advisory indexed by CVSS version.
+func (as *AdvisorySummary) HighestBaseScores() map[string]float64 {
+	highest := map[string]float64{}
+	all := append(append(Scores{}, as.Scores...), as.Metrics.Scores()...)
+	for version, scores := range all.productScores() {
+		for _, sv := range scores {
+			if sv.score == nil {
+				continue
+			}
+			if h, ok := highest[version]; !ok || *sv.score > h {
+				highest[version] = *sv.score
+			}
+		}
+	}
+	return highest
+}
@@ -22,6 +22,9 @@ import (
 //go:embed schema/csaf_json_schema.json
 var csafSchema []byte
 
+//go:embed schema/csaf_2.1_json_schema.json
+var csaf21Schema []byte
+
 //go:embed schema/cvss-v2.0.json
 var cvss20 []byte
 
@@ -31,6 +34,9 @@ var cvss30 []byte
 //go:embed schema/cvss-v3.1.json
 var cvss31 []byte
 
+//go:embed schema/cvss-v4.0.json
+var cvss40 []byte
+
 //go:embed schema/provider_json_schema.json
 var providerSchema []byte
 
@@ -49,16 +55,19 @@ type compiledSchema struct {
 
 const (
 	csafSchemaURL       = "https://docs.oasis-open.org/csaf/csaf/v2.0/csaf_json_schema.json"
+	csaf21SchemaURL     = "https://docs.oasis-open.org/csaf/csaf/v2.1/schema/csaf.json"
 	providerSchemaURL   = "https://docs.oasis-open.org/csaf/csaf/v2.0/provider_json_schema.json"
 	aggregatorSchemaURL = "https://docs.oasis-open.org/csaf/csaf/v2.0/aggregator_json_schema.json"
 	cvss20SchemaURL     = "https://www.first.org/cvss/cvss-v2.0.json"
 	cvss30SchemaURL     = "https://www.first.org/cvss/cvss-v3.0.json"
 	cvss31SchemaURL     = "https://www.first.org/cvss/cvss-v3.1.json"
+	cvss40SchemaURL     = "https://www.first.org/cvss/cvss-v4.0.json"
 	rolieSchemaURL      = "https://raw.githubusercontent.com/tschmidtb51/csaf/ROLIE-schema/csaf_2.0/json_schema/ROLIE_feed_json_schema.json"
 )
 
 var (
 	compiledCSAFSchema       = compiledSchema{url: csafSchemaURL}
+	compiledCSAF21Schema     = compiledSchema{url: csaf21SchemaURL}
 	compiledProviderSchema   = compiledSchema{url: providerSchemaURL}
 	compiledAggregatorSchema = compiledSchema{url: aggregatorSchemaURL}
 	compiledRolieSchema      = compiledSchema{url: rolieSchemaURL}
@@ -73,12 +82,16 @@ func loadURL(s string) (io.ReadCloser, error) {
 	switch s {
 	case csafSchemaURL:
 		return loader(csafSchema)
+	case csaf21SchemaURL:
+		return loader(csaf21Schema)
 	case cvss20SchemaURL:
 		return loader(cvss20)
 	case cvss30SchemaURL:
 		return loader(cvss30)
 	case cvss31SchemaURL:
 		return loader(cvss31)
+	case cvss40SchemaURL:
+		return loader(cvss40)
 	case providerSchemaURL:
 		return loader(providerSchema)
 	case aggregatorSchemaURL:
@@ -153,9 +166,38 @@ func (cs *compiledSchema) validate(doc any) ([]string, error) {
 	return res, nil
 }
 
+// DocumentVersion returns the CSAF version given in
+// 'document.csaf_version' of the document doc.
+// An empty string is returned if there is no such version.
+func DocumentVersion(doc any) Version {
+	m, ok := doc.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if m, ok = m["document"].(map[string]any); !ok {
+		return ""
+	}
+	version, _ := m["csaf_version"].(string)
+	return Version(version)
+}
+
+// SchemaURL returns the URL of the JSON schema of the given
+// CSAF version. Unknown versions are mapped to CSAF 2.0.
+func SchemaURL(version Version) string {
+	if version == CSAFVersion21 {
+		return csaf21SchemaURL
+	}
+	return csafSchemaURL
+}
+
 // ValidateCSAF validates the document doc against the JSON schema
-// of CSAF.
+// of CSAF. The schema is selected by the version found in
+// 'document.csaf_version'. Documents without a known version
+// are validated against CSAF 2.0.
 func ValidateCSAF(doc any) ([]string, error) {
+	if DocumentVersion(doc) == CSAFVersion21 {
+		return compiledCSAF21Schema.validate(doc)
+	}
 	return compiledCSAFSchema.validate(doc)
 }
 
@@ -0,0 +1,226 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package csaf
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/csaf-poc/csaf_distribution/v3/util"
+)
+
+// csaf21Template is the template the examples of the
+// CSAF 2.1 specification are based on.
+const csaf21Template = `{
+  "$schema": "https://docs.oasis-open.org/csaf/csaf/v2.1/schema/csaf.json",
+  "document": {
+    "category": "csaf_base",
+    "csaf_version": "2.1",
+    "distribution": {
+      "tlp": {
+        "label": "CLEAR"
+      }
+    },
+    "publisher": {
+      "category": "other",
+      "name": "OASIS CSAF TC",
+      "namespace": "https://csaf.io"
+    },
+    "title": "Template for generating CSAF files for Validator examples",
+    "tracking": {
+      "current_release_date": "2024-01-24T10:00:00.000Z",
+      "id": "OASIS_CSAF_TC-CSAF_2.1-2024-TEMPLATE",
+      "initial_release_date": "2024-01-24T10:00:00.000Z",
+      "revision_history": [
+        {
+          "date": "2024-01-24T10:00:00.000Z",
+          "number": "1",
+          "summary": "Initial version."
+        }
+      ],
+      "status": "final",
+      "version": "1"
+    }
+  }
+}`
+
+// toCSAF21 converts the test advisory into a CSAF 2.1 document.
+func toCSAF21(doc map[string]any) {
+	doc["$schema"] = csaf21SchemaURL
+	d := walkJSON(doc, "document").(map[string]any)
+	d["csaf_version"] = "2.1"
+	d["license_expression"] = "CC-BY-4.0"
+	d["distribution"] = map[string]any{"tlp": map[string]any{"label": "CLEAR"}}
+	for _, i := range []int{0, 1} {
+		pih := walkJSON(doc, "product_tree", "branches", 0, "branches", 0, "branches", i,
+			"product", "product_identification_helper").(map[string]any)
+		pih["purls"] = []any{pih["purl"]}
+		delete(pih, "purl")
+	}
+	v := walkJSON(doc, "vulnerabilities", 0).(map[string]any)
+	cwe := v["cwe"].(map[string]any)
+	cwe["version"] = "4.13"
+	v["cwes"] = []any{cwe}
+	delete(v, "cwe")
+	score := walkJSON(v, "scores", 0).(map[string]any)
+	v["metrics"] = []any{map[string]any{
+		"products": score["products"],
+		"content":  map[string]any{"cvss_v3": score["cvss_v3"]},
+	}}
+	delete(v, "scores")
+}
+
+func TestValidateCSAF(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(map[string]any)
+		valid  bool
+	}{
+		{"csaf 2.0", nil, true},
+		{"csaf 2.1", toCSAF21, true},
+		{"csaf 2.0 with scores as 2.1", func(doc map[string]any) {
+			walkJSON(doc, "document").(map[string]any)["csaf_version"] = "2.1"
+		}, false},
+		{"csaf 2.1 as 2.0", func(doc map[string]any) {
+			toCSAF21(doc)
+			walkJSON(doc, "document").(map[string]any)["csaf_version"] = "2.0"
+		}, false},
+		{"unknown version", func(doc map[string]any) {
+			walkJSON(doc, "document").(map[string]any)["csaf_version"] = "3.0"
+		}, false},
+	} {
+		doc := loadTestAdvisory(t, tc.modify)
+		errs, err := ValidateCSAF(doc)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if valid := len(errs) == 0; valid != tc.valid {
+			t.Errorf("%s: expected valid %t, got %t: %v", tc.name, tc.valid, valid, errs)
+		}
+	}
+}
+
+func TestValidateCSAF21Example(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(map[string]any)
+		valid  bool
+	}{
+		{"template", nil, true},
+		{"without $schema", func(doc map[string]any) {
+			delete(doc, "$schema")
+		}, false},
+		{"with 2.0 $schema", func(doc map[string]any) {
+			doc["$schema"] = csafSchemaURL
+		}, false},
+		{"without distribution", func(doc map[string]any) {
+			delete(walkJSON(doc, "document").(map[string]any), "distribution")
+		}, false},
+	} {
+		var doc map[string]any
+		if err := json.Unmarshal([]byte(csaf21Template), &doc); err != nil {
+			t.Fatal(err)
+		}
+		if tc.modify != nil {
+			tc.modify(doc)
+		}
+		errs, err := ValidateCSAF(doc)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if valid := len(errs) == 0; valid != tc.valid {
+			t.Errorf("%s: expected valid %t, got %t: %v", tc.name, tc.valid, valid, errs)
+		}
+	}
+}
+
+func TestDocumentVersion(t *testing.T) {
+	if v := DocumentVersion(loadTestAdvisory(t, toCSAF21)); v != CSAFVersion21 {
+		t.Errorf("expected version %q, got %q", CSAFVersion21, v)
+	}
+	if v := DocumentVersion("no document"); v != "" {
+		t.Errorf("expected no version, got %q", v)
+	}
+	if f := CSAFFormat(""); f.Version != string(CSAFVersion20) || f.Schema != csafSchemaURL {
+		t.Errorf("unexpected format %+v", f)
+	}
+	if f := CSAFFormat(CSAFVersion21); f.Version != string(CSAFVersion21) || f.Schema != csaf21SchemaURL {
+		t.Errorf("unexpected format %+v", f)
+	}
+}
+
+func TestCSAF21Advisory(t *testing.T) {
+	doc := loadTestAdvisory(t, toCSAF21)
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var adv Advisory
+	if err := json.Unmarshal(data, &adv); err != nil {
+		t.Fatal(err)
+	}
+	if err := adv.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	v := adv.Vulnerabilities[0]
+	if len(v.CWEs) != 1 || v.CWEs[0].Version == nil || *v.CWEs[0].Version != "4.13" {
+		t.Errorf("unexpected cwes %v", v.CWEs)
+	}
+	if scores := v.AllScores(); len(scores) != 1 || scores[0].CVSS3 == nil {
+		t.Errorf("expected one CVSS v3 score, got %v", scores)
+	}
+
+	// Round trip must keep the CSAF 2.1 fields.
+	var again any
+	if err := util.ReMarshalJSON(&again, &adv); err != nil {
+		t.Fatal(err)
+	}
+	if errs, err := ValidateCSAF(again); err != nil || len(errs) > 0 {
+		t.Errorf("round trip is not schema valid: %v (%v)", errs, err)
+	}
+
+	validator, err := (&LocalValidatorOptions{Presets: []string{"mandatory"}}).Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rvr, err := validator.Validate(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rvr.Valid {
+		t.Errorf("expected CSAF 2.1 document to be valid: %+v", rvr.Tests)
+	}
+
+	metric := walkJSON(doc, "vulnerabilities", 0, "metrics", 0, "content", "cvss_v3")
+	metric.(map[string]any)["baseScore"] = 9.7
+	if rvr, err = validator.Validate(doc); err != nil {
+		t.Fatal(err)
+	}
+	const path = "/vulnerabilities/0/metrics/0/content/cvss_v3/baseScore"
+	for _, test := range rvr.Tests {
+		if test.Name == "mandatoryTest_6_1_9" &&
+			(test.Valid || test.Error[0].InstancePath != path) {
+			t.Errorf("expected %s to fail at %s: %v", test.Name, path, test.Error)
+		}
+	}
+}
+
+func TestCSAF21Summary(t *testing.T) {
+	doc := loadTestAdvisory(t, toCSAF21)
+	sum, err := NewAdvisorySummary(util.NewPathEval(), doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.CSAFVersion != CSAFVersion21 || sum.TLPLabel != TLPLabelClear {
+		t.Errorf("unexpected version %q and TLP label %q", sum.CSAFVersion, sum.TLPLabel)
+	}
+	if s := sum.HighestBaseScores(); s["3.1"] != 9.8 {
+		t.Errorf("unexpected highest base scores %v", s)
+	}
+}
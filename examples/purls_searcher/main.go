@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/csaf-poc/csaf_distribution/v3/csaf"
-	"github.com/csaf-poc/csaf_distribution/v3/util"
 )
 
 func main() {
@@ -40,18 +39,16 @@ func run(files []string, ids string) error {
 			return fmt.Errorf("loading %q failed: %w", file, err)
 		}
 
+		index := adv.ProductTree.Index()
+
 		for _, id := range strings.Split(ids, ",") {
-			already := util.Set[csaf.PURL]{}
-			i := 0
-			adv.ProductTree.FindProductIdentificationHelpers(
-				csaf.ProductID(id),
-				func(h *csaf.ProductIdentificationHelper) {
-					if h.PURL != nil && !already.Contains(*h.PURL) {
-						already.Add(*h.PURL)
-						i++
-						fmt.Printf("%d. %s\n", i, *h.PURL)
-					}
-				})
+			product := index.Product(csaf.ProductID(id))
+			if product == nil {
+				continue
+			}
+			for i, purl := range product.PURLs {
+				fmt.Printf("%d. %s\n", i+1, purl)
+			}
 		}
 	}
 
@@ -0,0 +1,357 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+// Package archive implements archives of downloaded advisories
+// to carry them into other networks. An archive is a tar file,
+// optionally gzip compressed, or a zip file. It contains a
+// manifest with the sizes and hashes of all the files in it.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestName is the name of the manifest inside an archive.
+const ManifestName = "manifest.json"
+
+// ValidationValid is the validation status of
+// advisories which passed the validation.
+const ValidationValid = "valid"
+
+// File describes a file inside an archive.
+type File struct {
+	Name       string `json:"name"`
+	TLP        string `json:"tlp,omitempty"`
+	Validation string `json:"validation,omitempty"`
+	Size       int64  `json:"size"`
+	SHA256     string `json:"sha256"`
+	SHA512     string `json:"sha512"`
+}
+
+// Manifest lists the files of an archive.
+type Manifest struct {
+	Created time.Time `json:"created"`
+	Files   []File    `json:"files"`
+}
+
+type format int
+
+const (
+	tarFormat format = iota
+	tarGzFormat
+	zipFormat
+)
+
+// formatOf returns the format of an archive by its file name.
+func formatOf(fname string) (format, error) {
+	switch lower := strings.ToLower(fname); {
+	case strings.HasSuffix(lower, ".tar"):
+		return tarFormat, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return tarGzFormat, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return zipFormat, nil
+	}
+	return 0, fmt.Errorf(
+		"unsupported archive format of %q: use .tar, .tar.gz, .tgz or .zip", fname)
+}
+
+// describe returns the description of a file with the given content.
+func describe(name, tlp, validation string, data []byte) File {
+	sum256 := sha256.Sum256(data)
+	sum512 := sha512.Sum512(data)
+	return File{
+		Name:       name,
+		TLP:        tlp,
+		Validation: validation,
+		Size:       int64(len(data)),
+		SHA256:     hex.EncodeToString(sum256[:]),
+		SHA512:     hex.EncodeToString(sum512[:]),
+	}
+}
+
+// Writer writes an archive. The archive is written to a temporary
+// file which is renamed to the final name when the writer is closed.
+// A Writer is safe for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	fname    string
+	file     *os.File
+	gz       *gzip.Writer
+	tw       *tar.Writer
+	zw       *zip.Writer
+	manifest Manifest
+	files    map[string]int
+}
+
+// Create creates a new archive. The format is chosen by the
+// extension of the file name.
+func Create(fname string) (*Writer, error) {
+	f, err := formatOf(fname)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.CreateTemp(filepath.Dir(fname), filepath.Base(fname)+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	w := &Writer{
+		fname:    fname,
+		file:     file,
+		manifest: Manifest{Created: time.Now().UTC().Truncate(time.Second)},
+		files:    map[string]int{},
+	}
+	switch f {
+	case tarFormat:
+		w.tw = tar.NewWriter(file)
+	case tarGzFormat:
+		w.gz = gzip.NewWriter(file)
+		w.tw = tar.NewWriter(w.gz)
+	case zipFormat:
+		w.zw = zip.NewWriter(file)
+	}
+	return w, nil
+}
+
+// write writes a file to the archive.
+// w.mu has to be locked.
+func (w *Writer) write(name string, data []byte) error {
+	if w.zw != nil {
+		out, err := w.zw.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: w.manifest.Created,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	}
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     int64(len(data)),
+		ModTime:  w.manifest.Created,
+		Format:   tar.FormatPAX,
+	}); err != nil {
+		return err
+	}
+	_, err := w.tw.Write(data)
+	return err
+}
+
+// Add adds a file with the given name to the archive. The tlp is the
+// TLP label and validation is the validation status of the advisory
+// the file belongs to. Both may be empty.
+// Adding the same content again under the same name is ignored.
+func (w *Writer) Add(name, tlp, validation string, data []byte) error {
+	if name == ManifestName {
+		return fmt.Errorf("%q is reserved for the manifest", name)
+	}
+	desc := describe(name, tlp, validation, data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if idx, ok := w.files[name]; ok {
+		if w.manifest.Files[idx].SHA512 == desc.SHA512 {
+			return nil
+		}
+		return fmt.Errorf("%q already added with different content", name)
+	}
+	if err := w.write(name, data); err != nil {
+		return err
+	}
+	w.files[name] = len(w.manifest.Files)
+	w.manifest.Files = append(w.manifest.Files, desc)
+	return nil
+}
+
+// Close writes the manifest and finishes the archive.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	err := func() error {
+		manifest, err := json.MarshalIndent(&w.manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := w.write(ManifestName, manifest); err != nil {
+			return err
+		}
+		if w.zw != nil {
+			if err := w.zw.Close(); err != nil {
+				return err
+			}
+		} else if err := w.tw.Close(); err != nil {
+			return err
+		}
+		if w.gz != nil {
+			if err := w.gz.Close(); err != nil {
+				return err
+			}
+		}
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		return os.Rename(w.file.Name(), w.fname)
+	}()
+	if err != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+	}
+	return err
+}
+
+// Contents are the verified contents of an archive.
+type Contents struct {
+	Manifest *Manifest
+	// Files maps the names of the files to their data.
+	Files map[string][]byte
+}
+
+// Read reads an archive completely into memory and
+// verifies its files against the manifest.
+func Read(fname string) (*Contents, error) {
+	f, err := formatOf(fname)
+	if err != nil {
+		return nil, err
+	}
+	files := map[string][]byte{}
+	add := func(name string, r io.Reader) error {
+		if _, dup := files[name]; dup {
+			return fmt.Errorf("duplicate file %q", name)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		files[name] = data
+		return nil
+	}
+
+	if f == zipFormat {
+		err = readZip(fname, add)
+	} else {
+		err = readTar(fname, f == tarGzFormat, add)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, ok := files[ManifestName]
+	if !ok {
+		return nil, fmt.Errorf("archive %q has no manifest", fname)
+	}
+	delete(files, ManifestName)
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if err := manifest.verify(files); err != nil {
+		return nil, err
+	}
+	return &Contents{Manifest: &manifest, Files: files}, nil
+}
+
+// verify checks that the given files match the manifest.
+func (m *Manifest) verify(files map[string][]byte) error {
+	listed := make(map[string]bool, len(m.Files))
+	for i := range m.Files {
+		expected := &m.Files[i]
+		data, ok := files[expected.Name]
+		if !ok {
+			return fmt.Errorf("file %q of manifest is missing", expected.Name)
+		}
+		if got := describe(expected.Name, expected.TLP, expected.Validation, data); got != *expected {
+			return fmt.Errorf("file %q does not match manifest", expected.Name)
+		}
+		listed[expected.Name] = true
+	}
+	for name := range files {
+		if !listed[name] {
+			return fmt.Errorf("file %q is not listed in manifest", name)
+		}
+	}
+	return nil
+}
+
+// readZip calls fn for each regular file in a zip archive.
+func readZip(fname string, fn func(string, io.Reader) error) error {
+	zr, err := zip.OpenReader(fname)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+	for _, zf := range zr.File {
+		if !zf.Mode().IsRegular() {
+			continue
+		}
+		if err := func() error {
+			r, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			return fn(zf.Name, r)
+		}(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTar calls fn for each regular file in a tar archive.
+func readTar(fname string, compressed bool, fn func(string, io.Reader) error) error {
+	file, err := os.Open(fname)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package archive
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	files := []struct {
+		name       string
+		tlp        string
+		validation string
+		data       string
+	}{
+		{"example.com/provider-metadata.json", "", "", `{"role": "csaf_provider"}`},
+		{"example.com/white/2023/a.json", "WHITE", ValidationValid, `{"document": {}}`},
+		{"example.com/white/2023/a.json.sha256", "WHITE", ValidationValid, "256"},
+		{"example.com/failed_validation/white/2023/b.json", "WHITE", "invalid", `{}`},
+	}
+
+	for _, name := range []string{"run.tar", "run.tar.gz", "run.tgz", "run.zip"} {
+		fname := filepath.Join(t.TempDir(), name)
+		w, err := Create(fname)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, f := range files {
+			if err := w.Add(f.name, f.tlp, f.validation, []byte(f.data)); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+		}
+		// Adding the same again is fine, different content not.
+		if err := w.Add(files[1].name, files[1].tlp, files[1].validation, []byte(files[1].data)); err != nil {
+			t.Errorf("%s: adding same file again failed: %v", name, err)
+		}
+		if err := w.Add(files[1].name, files[1].tlp, files[1].validation, []byte("{}")); err == nil {
+			t.Errorf("%s: adding different content under same name succeeded", name)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		contents, err := Read(fname)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n := len(contents.Manifest.Files); n != len(files) {
+			t.Fatalf("%s: got %d files in manifest expected %d", name, n, len(files))
+		}
+		for i, f := range files {
+			if got := contents.Manifest.Files[i]; got.Name != f.name || got.TLP != f.tlp || got.Validation != f.validation {
+				t.Errorf("%s: unexpected manifest entry %+v", name, got)
+			}
+			if got := string(contents.Files[f.name]); got != f.data {
+				t.Errorf("%s: %s: got %q expected %q", name, f.name, got, f.data)
+			}
+		}
+	}
+
+	if _, err := Create(filepath.Join(t.TempDir(), "run.rar")); err == nil {
+		t.Error("unsupported format should result in an error")
+	}
+}
+
+func TestReadTampered(t *testing.T) {
+	write := func(t *testing.T, files map[string]string) string {
+		fname := filepath.Join(t.TempDir(), "tampered.tar")
+		f, err := os.Create(fname)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		tw := tar.NewWriter(f)
+		for name, data := range files {
+			if err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     name,
+				Mode:     0644,
+				Size:     int64(len(data)),
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write([]byte(data)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := tw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return fname
+	}
+
+	manifest := `{"files": [{"name": "a.json", "size": 2,
+"sha256": "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a",
+"sha512": "27c74670adb75075fad058d5ceaf7b20c4e7786c83bae8a32f626f9782af34c9a33c2046ef60fd2a7878d378e29fec851806bbd9a67878f3a9f1cda4830763fd"}]}`
+
+	for _, tc := range []struct {
+		name  string
+		files map[string]string
+		ok    bool
+	}{
+		{"good", map[string]string{ManifestName: manifest, "a.json": "{}"}, true},
+		{"no manifest", map[string]string{"a.json": "{}"}, false},
+		{"modified", map[string]string{ManifestName: manifest, "a.json": "[]"}, false},
+		{"missing", map[string]string{ManifestName: manifest}, false},
+		{"unlisted", map[string]string{ManifestName: manifest, "a.json": "{}", "b.json": "{}"}, false},
+	} {
+		_, err := Read(write(t, tc.files))
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("%s: got %v expected success %t", tc.name, err, tc.ok)
+		}
+	}
+}
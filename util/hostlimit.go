@@ -0,0 +1,231 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// minHostRate is the lowest rate a host is throttled to.
+	minHostRate = rate.Limit(1.0 / 60)
+	// throttledHostRate is the rate a host without
+	// a rate ceiling is throttled to first.
+	throttledHostRate = rate.Limit(1)
+	// unlimitedHostRate is the rate from which on a host
+	// without a rate ceiling is not limited any more.
+	unlimitedHostRate = rate.Limit(50)
+	// hostThrottleGrace is the time in which further throttling
+	// responses of a host do not lower its rate again.
+	hostThrottleGrace = time.Second
+	// hostRecovery is the interval after which the rate
+	// of a throttled host is raised again.
+	hostRecovery = 30 * time.Second
+	// hostRecoveryFactor is the factor the rate of a
+	// throttled host is raised by.
+	hostRecoveryFactor = 1.5
+)
+
+// hostLimit is the rate limit of a single host.
+type hostLimit struct {
+	limiter *rate.Limiter
+	// until is the time till the host asked us to wait.
+	until time.Time
+	// changed is the time of the last change of the rate.
+	changed time.Time
+}
+
+// HostLimiter keeps adaptive rate limits per host.
+// If a host answers with 429 (Too Many Requests) or
+// with a 'Retry-After' header its rate is halved
+// and requests are delayed as asked for. Afterwards
+// the rate recovers slowly up to the ceiling.
+// A HostLimiter is safe for concurrent use.
+type HostLimiter struct {
+	ceiling rate.Limit
+	mu      sync.Mutex
+	hosts   map[string]*hostLimit
+}
+
+// NewHostLimiter creates a new HostLimiter. The given ceiling is
+// the highest rate of requests per second to a single host.
+// A ceiling less or equal zero means unlimited.
+func NewHostLimiter(ceiling rate.Limit) *HostLimiter {
+	if ceiling <= 0 {
+		ceiling = rate.Inf
+	}
+	return &HostLimiter{
+		ceiling: ceiling,
+		hosts:   map[string]*hostLimit{},
+	}
+}
+
+// host returns the limit of the given host.
+// hl.mu has to be locked.
+func (hl *HostLimiter) host(name string) *hostLimit {
+	h := hl.hosts[name]
+	if h == nil {
+		h = &hostLimit{limiter: rate.NewLimiter(hl.ceiling, 1)}
+		hl.hosts[name] = h
+	}
+	return h
+}
+
+// Rate returns the current rate of the given host.
+func (hl *HostLimiter) Rate(host string) rate.Limit {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	return hl.host(host).limiter.Limit()
+}
+
+// Wait blocks till a request to the given host is allowed.
+func (hl *HostLimiter) Wait(ctx context.Context, host string) error {
+	hl.mu.Lock()
+	h := hl.host(host)
+	until, limiter := h.until, h.limiter
+	hl.mu.Unlock()
+
+	if d := time.Until(until); d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return limiter.Wait(ctx)
+}
+
+// Update adapts the rate of the given host to a response of it.
+func (hl *HostLimiter) Update(host string, res *http.Response) {
+	hl.update(host, res, time.Now())
+}
+
+func (hl *HostLimiter) update(host string, res *http.Response, now time.Time) {
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+	h := hl.host(host)
+
+	throttled := res.StatusCode == http.StatusTooManyRequests
+	if res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusServiceUnavailable {
+		if after, ok := parseRetryAfter(res.Header.Get("Retry-After"), now); ok {
+			throttled = true
+			if until := now.Add(after); until.After(h.until) {
+				h.until = until
+			}
+		}
+	}
+
+	current := h.limiter.Limit()
+
+	if throttled {
+		// Concurrent requests may be answered with several throttling responses.
+		if now.Sub(h.changed) < hostThrottleGrace && current != hl.ceiling {
+			return
+		}
+		next := current / 2
+		if current == rate.Inf {
+			next = throttledHostRate
+		}
+		if next < minHostRate {
+			next = minHostRate
+		}
+		h.limiter.SetLimitAt(now, next)
+		h.changed = now
+		return
+	}
+
+	// Recover slowly.
+	if current >= hl.ceiling || now.Sub(h.changed) < hostRecovery {
+		return
+	}
+	next := current * hostRecoveryFactor
+	if next >= hl.ceiling || (hl.ceiling == rate.Inf && next >= unlimitedHostRate) {
+		next = hl.ceiling
+	}
+	h.limiter.SetLimitAt(now, next)
+	h.changed = now
+}
+
+// HostLimitingClient is a Client implementing rate throttling
+// per target host with a [HostLimiter].
+type HostLimitingClient struct {
+	Client
+	Limiter *HostLimiter
+}
+
+// hostOf returns the host of the given URL.
+func hostOf(u string) string {
+	if p, err := url.Parse(u); err == nil {
+		return p.Host
+	}
+	return ""
+}
+
+// limit makes a request to the given URL
+// throttled by the rate of its host.
+func (hc *HostLimitingClient) limit(
+	ctx context.Context,
+	u string,
+	do func() (*http.Response, error),
+) (*http.Response, error) {
+	host := hostOf(u)
+	if err := hc.Limiter.Wait(ctx, host); err != nil {
+		return nil, err
+	}
+	res, err := do()
+	if err == nil {
+		hc.Limiter.Update(host, res)
+	}
+	return res, err
+}
+
+// Do implements the respective method of the [Client] interface.
+func (hc *HostLimitingClient) Do(req *http.Request) (*http.Response, error) {
+	return hc.limit(req.Context(), req.URL.String(), func() (*http.Response, error) {
+		return hc.Client.Do(req)
+	})
+}
+
+// Get implements the respective method of the [Client] interface.
+func (hc *HostLimitingClient) Get(url string) (*http.Response, error) {
+	return hc.limit(context.Background(), url, func() (*http.Response, error) {
+		return hc.Client.Get(url)
+	})
+}
+
+// Head implements the respective method of the [Client] interface.
+func (hc *HostLimitingClient) Head(url string) (*http.Response, error) {
+	return hc.limit(context.Background(), url, func() (*http.Response, error) {
+		return hc.Client.Head(url)
+	})
+}
+
+// Post implements the respective method of the [Client] interface.
+func (hc *HostLimitingClient) Post(url, contentType string, body io.Reader) (*http.Response, error) {
+	return hc.limit(context.Background(), url, func() (*http.Response, error) {
+		return hc.Client.Post(url, contentType, body)
+	})
+}
+
+// PostForm implements the respective method of the [Client] interface.
+func (hc *HostLimitingClient) PostForm(url string, data url.Values) (*http.Response, error) {
+	return hc.limit(context.Background(), url, func() (*http.Response, error) {
+		return hc.Client.PostForm(url, data)
+	})
+}
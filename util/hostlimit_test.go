@@ -0,0 +1,131 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func response(status int, retryAfter string) *http.Response {
+	res := &http.Response{StatusCode: status, Header: http.Header{}}
+	if retryAfter != "" {
+		res.Header.Set("Retry-After", retryAfter)
+	}
+	return res
+}
+
+func TestHostLimiterAdapt(t *testing.T) {
+	const host = "example.com"
+	hl := NewHostLimiter(8)
+	now := time.Now()
+
+	expect := func(step string, r rate.Limit) {
+		t.Helper()
+		if got := hl.Rate(host); got != r {
+			t.Errorf("%s: got rate %v expected %v", step, got, r)
+		}
+	}
+
+	expect("initial", 8)
+	hl.update(host, response(http.StatusOK, ""), now)
+	expect("success at ceiling", 8)
+
+	hl.update(host, response(http.StatusTooManyRequests, ""), now)
+	expect("throttled", 4)
+	hl.update(host, response(http.StatusTooManyRequests, ""), now.Add(hostThrottleGrace/2))
+	expect("throttled within grace", 4)
+	hl.update(host, response(http.StatusServiceUnavailable, "10"), now.Add(hostThrottleGrace))
+	expect("retry after", 2)
+	hl.update(host, response(http.StatusServiceUnavailable, ""), now.Add(2*hostThrottleGrace))
+	expect("unavailable without retry after", 2)
+
+	hl.mu.Lock()
+	until := hl.host(host).until
+	hl.mu.Unlock()
+	if !until.Equal(now.Add(hostThrottleGrace + 10*time.Second)) {
+		t.Errorf("unexpected retry after time %v", until)
+	}
+
+	now = now.Add(hostThrottleGrace)
+	hl.update(host, response(http.StatusOK, ""), now.Add(hostRecovery/2))
+	expect("too early to recover", 2)
+	now = now.Add(hostRecovery)
+	hl.update(host, response(http.StatusOK, ""), now)
+	expect("recovering", 2*hostRecoveryFactor)
+	now = now.Add(hostRecovery)
+	hl.update(host, response(http.StatusOK, ""), now)
+	expect("recovering further", 2*hostRecoveryFactor*hostRecoveryFactor)
+	for i := 0; i < 2; i++ {
+		now = now.Add(hostRecovery)
+		hl.update(host, response(http.StatusOK, ""), now)
+	}
+	expect("recovered", 8)
+
+	if r := hl.Rate("other.example.com"); r != 8 {
+		t.Errorf("other host: got rate %v expected 8", r)
+	}
+}
+
+func TestHostLimiterUnlimited(t *testing.T) {
+	const host = "example.com"
+	hl := NewHostLimiter(0)
+	now := time.Now()
+	if r := hl.Rate(host); r != rate.Inf {
+		t.Fatalf("got rate %v expected unlimited", r)
+	}
+	hl.update(host, response(http.StatusTooManyRequests, ""), now)
+	if r := hl.Rate(host); r != throttledHostRate {
+		t.Fatalf("got rate %v expected %v", r, throttledHostRate)
+	}
+	for r := throttledHostRate; r != rate.Inf; r = hl.Rate(host) {
+		now = now.Add(hostRecovery)
+		hl.update(host, response(http.StatusOK, ""), now)
+		if hl.Rate(host) <= r {
+			t.Fatalf("rate %v does not recover", r)
+		}
+	}
+}
+
+func TestHostLimitingClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	hl := NewHostLimiter(0)
+	hc := &HostLimitingClient{Client: server.Client(), Limiter: hl}
+	res, err := hc.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	host := hostOf(server.URL)
+	if r := hl.Rate(host); r != throttledHostRate {
+		t.Errorf("got rate %v expected %v", r, throttledHostRate)
+	}
+
+	// The host asked to wait a minute.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := hc.Do(req); err != context.DeadlineExceeded {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"strings"
+	"time"
+)
+
+// ParseUpdateInterval interprets the update interval of a provider
+// as a duration. Besides positive Go durations the descriptive values
+// "hourly", "daily", "weekly" and "monthly" are recognized.
+// Returns false if the interval cannot be interpreted.
+func ParseUpdateInterval(s string) (time.Duration, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "hourly":
+		return time.Hour, true
+	case "daily":
+		return 24 * time.Hour, true
+	case "weekly":
+		return 7 * 24 * time.Hour, true
+	case "monthly":
+		return 30 * 24 * time.Hour, true
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,37 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUpdateInterval(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		expected time.Duration
+		ok       bool
+	}{
+		{"hourly", time.Hour, true},
+		{"Daily", 24 * time.Hour, true},
+		{"weekly", 7 * 24 * time.Hour, true},
+		{"monthly", 30 * 24 * time.Hour, true},
+		{"6h", 6 * time.Hour, true},
+		{"on best effort", 0, false},
+		{"-1h", 0, false},
+		{"0s", 0, false},
+		{"", 0, false},
+	} {
+		got, ok := ParseUpdateInterval(tc.in)
+		if got != tc.expected || ok != tc.ok {
+			t.Errorf("%q: got %v, %t expected %v, %t", tc.in, got, ok, tc.expected, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxBackoff is the default upper limit of
+// the delay between two attempts of a [RetryingClient].
+const DefaultMaxBackoff = 5 * time.Minute
+
+// RetryingClient is a client that retries requests which failed transiently.
+// Only the idempotent GET and HEAD requests are retried.
+// They are retried on network errors and on the
+// status codes 429, 502, 503 and 504.
+type RetryingClient struct {
+	Client
+	// Attempts is the maximal number of attempts made for a request.
+	// Values less than two disable the retrying.
+	Attempts int
+	// Backoff is the delay before the first retry.
+	// It is doubled with each further retry.
+	Backoff time.Duration
+	// MaxBackoff limits the delay between two attempts.
+	// Longer delays requested by a 'Retry-After' header
+	// are not waited for. Defaults to DefaultMaxBackoff.
+	MaxBackoff time.Duration
+	// Jitter is the fraction by which the delays are varied randomly.
+	Jitter float64
+	// Log is called before each retry.
+	Log func(method, url string, attempt int, delay time.Duration)
+}
+
+// retryable returns true if the result of a request indicates
+// a transient failure.
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded)
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses the value of a 'Retry-After' header.
+// It is given either in seconds or as an HTTP date.
+func parseRetryAfter(s string, now time.Time) (time.Duration, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(s); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	t, err := http.ParseTime(s)
+	if err != nil {
+		return 0, false
+	}
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// delay returns the delay before the next attempt.
+// Returns false if the server asks to wait longer than allowed.
+func (rc *RetryingClient) delay(backoff time.Duration, res *http.Response) (time.Duration, bool) {
+	max := rc.MaxBackoff
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	if res != nil && (res.StatusCode == http.StatusTooManyRequests ||
+		res.StatusCode == http.StatusServiceUnavailable) {
+		if after, ok := parseRetryAfter(res.Header.Get("Retry-After"), time.Now()); ok {
+			return after, after <= max
+		}
+	}
+	d := backoff
+	if d > max {
+		d = max
+	}
+	if rc.Jitter > 0 {
+		d += time.Duration((rand.Float64()*2 - 1) * rc.Jitter * float64(d))
+	}
+	return d, true
+}
+
+// log logs a retry to a callback if given.
+func (rc *RetryingClient) log(method, url string, attempt int, delay time.Duration) {
+	if rc.Log != nil {
+		rc.Log(method, url, attempt, delay)
+	} else {
+		log.Printf("[%s]: %s: retry %d in %s\n", method, url, attempt, delay)
+	}
+}
+
+// retry calls do till it succeeds, fails permanently
+// or the number of attempts is exhausted.
+func (rc *RetryingClient) retry(
+	ctx context.Context,
+	method, url string,
+	do func() (*http.Response, error),
+) (*http.Response, error) {
+	backoff := rc.Backoff
+	for attempt := 1; ; attempt++ {
+		res, err := do()
+		if attempt >= rc.Attempts || !retryable(res, err) {
+			return res, err
+		}
+		delay, ok := rc.delay(backoff, res)
+		if !ok {
+			return res, err
+		}
+		if res != nil {
+			// Drain the body to allow the reuse of the connection.
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+		rc.log(method, url, attempt, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+// Do implements the respective method of the [Client] interface.
+func (rc *RetryingClient) Do(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) ||
+		(req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return rc.Client.Do(req)
+	}
+	first := true
+	return rc.retry(req.Context(), req.Method, req.URL.String(), func() (*http.Response, error) {
+		// Re-establish the body for the following attempts.
+		if !first && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		first = false
+		return rc.Client.Do(req)
+	})
+}
+
+// Get implements the respective method of the [Client] interface.
+// The waiting between the attempts cannot be interrupted.
+// Use Do with a request carrying a context if this is needed.
+func (rc *RetryingClient) Get(url string) (*http.Response, error) {
+	return rc.retry(context.Background(), http.MethodGet, url, func() (*http.Response, error) {
+		return rc.Client.Get(url)
+	})
+}
+
+// Head implements the respective method of the [Client] interface.
+// The waiting between the attempts cannot be interrupted.
+// Use Do with a request carrying a context if this is needed.
+func (rc *RetryingClient) Head(url string) (*http.Response, error) {
+	return rc.retry(context.Background(), http.MethodHead, url, func() (*http.Response, error) {
+		return rc.Client.Head(url)
+	})
+}
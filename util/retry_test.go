@@ -0,0 +1,143 @@
+// This file is Free Software under the MIT License
+// without warranty, see README.md and LICENSES/MIT.txt for details.
+//
+// SPDX-License-Identifier: MIT
+//
+// SPDX-FileCopyrightText: 2023 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2023 Intevation GmbH <https://intevation.de>
+
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		in       string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Sat, 01 Jul 2023 12:00:30 GMT", 30 * time.Second, true},
+		{"Sat, 01 Jul 2023 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(tc.in, now)
+		if got != tc.expected || ok != tc.ok {
+			t.Errorf("%q: got %v, %t expected %v, %t", tc.in, got, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func TestRetryingClient(t *testing.T) {
+	var calls atomic.Int32
+	// failures is the number of requests failing before one succeeds.
+	var failures atomic.Int32
+	var status atomic.Int32
+	var retryAfter atomic.Value
+	retryAfter.Store("")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures.Load() {
+			if ra := retryAfter.Load().(string); ra != "" {
+				w.Header().Set("Retry-After", ra)
+			}
+			w.WriteHeader(int(status.Load()))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	rc := &RetryingClient{
+		Client:     server.Client(),
+		Attempts:   3,
+		Backoff:    time.Millisecond,
+		MaxBackoff: time.Second,
+		Jitter:     0.5,
+		Log:        func(string, string, int, time.Duration) {},
+	}
+
+	for _, tc := range []struct {
+		name       string
+		failures   int32
+		status     int
+		retryAfter string
+		post       bool
+		expected   int
+		calls      int32
+	}{
+		{"success", 0, 0, "", false, http.StatusOK, 1},
+		{"unavailable once", 1, http.StatusServiceUnavailable, "", false, http.StatusOK, 2},
+		{"too many requests", 2, http.StatusTooManyRequests, "0", false, http.StatusOK, 3},
+		{"attempts exhausted", 3, http.StatusBadGateway, "", false, http.StatusBadGateway, 3},
+		{"permanent", 1, http.StatusNotFound, "", false, http.StatusNotFound, 1},
+		{"retry after too long", 1, http.StatusServiceUnavailable, "3600", false, http.StatusServiceUnavailable, 1},
+		{"post", 1, http.StatusServiceUnavailable, "", true, http.StatusServiceUnavailable, 1},
+	} {
+		calls.Store(0)
+		failures.Store(tc.failures)
+		status.Store(int32(tc.status))
+		retryAfter.Store(tc.retryAfter)
+
+		var (
+			res *http.Response
+			err error
+		)
+		if tc.post {
+			res, err = rc.Post(server.URL, "text/plain", strings.NewReader("data"))
+		} else {
+			res, err = rc.Get(server.URL)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		res.Body.Close()
+		if res.StatusCode != tc.expected {
+			t.Errorf("%s: got status %d expected %d", tc.name, res.StatusCode, tc.expected)
+		}
+		if got := calls.Load(); got != tc.calls {
+			t.Errorf("%s: got %d calls expected %d", tc.name, got, tc.calls)
+		}
+	}
+}
+
+func TestRetryingClientCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rc := &RetryingClient{
+		Client:     server.Client(),
+		Attempts:   3,
+		Backoff:    time.Hour,
+		MaxBackoff: time.Hour,
+		Log:        func(string, string, int, time.Duration) {},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if _, err := rc.Do(req); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation, got %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("cancellation took %s", d)
+	}
+}